@@ -3,39 +3,246 @@ package main
 import (
 	"awesomeProject/internal/application"
 	"awesomeProject/internal/config"
+	"awesomeProject/internal/email"
+	"awesomeProject/internal/graphapi"
+	"awesomeProject/internal/grpcserver"
+	"awesomeProject/internal/itip"
+	"awesomeProject/internal/notifier"
+	"awesomeProject/internal/outbox"
+	"awesomeProject/internal/realtime"
 	"awesomeProject/internal/repository"
+	"awesomeProject/internal/repository/cache"
+	"awesomeProject/internal/repository/memory"
+	"awesomeProject/internal/repository/sqlite"
 	"awesomeProject/internal/router"
 	"awesomeProject/internal/router/handlers"
+	"awesomeProject/internal/scheduler"
 	"awesomeProject/internal/service"
+	"awesomeProject/internal/syncfeed"
+	"awesomeProject/internal/trash"
+	"awesomeProject/internal/webhook"
 	"awesomeProject/pkg/logger"
 	"context"
 	"fmt"
 	"go.uber.org/zap"
+	"os"
+	"time"
 )
 
 func main() {
 	ctx := context.Background()
 	cfg := config.MustLoad("config/.env")
-	fmt.Printf("%#v\n", cfg)
 	log, err := logger.NewLogger(cfg.LogLevel)
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize logger: %v", err))
 	}
 	log.Info("initialize logger success")
+	// The config holds passwords, tokens and DSNs, so only settings without secrets are logged.
+	log.Info("configuration loaded", zap.String("addr", cfg.Addr), zap.String("driver", cfg.Driver), zap.String("grpc_addr", cfg.GRPCAddr))
 	defer log.Sync()
-	storage, err := repository.NewStorage(ctx, cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode, log)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, cfg, os.Args[2:], log); err != nil {
+			log.Fatal("migration failed", zap.Error(err))
+		}
+		return
+	}
+	switch cfg.Driver {
+	case config.DriverMemory:
+		log.Warn("using in-memory storage; data is lost on restart")
+		runStandalone(cfg, memory.NewRepository(log), log)
+		return
+	case config.DriverSQLite:
+		if cfg.Path == "" {
+			log.Fatal("DB_PATH is required for the sqlite driver")
+		}
+		sqliteStorage, err := sqlite.NewStorage(ctx, cfg.Path, cfg.AutoMigrate, log)
+		if err != nil {
+			log.Fatal("failed to initialize storage", zap.Error(err))
+		}
+		runStandalone(cfg, sqliteStorage.NewRepository(), log)
+		return
+	}
+	storage, err := repository.NewStorage(ctx, cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode, repository.Options{
+		AutoMigrate:     cfg.AutoMigrate,
+		MaxConns:        int32(cfg.MaxConns),
+		MinConns:        int32(cfg.MinConns),
+		Replicas:        cfg.Replicas,
+		ReplicaMaxConns: int32(cfg.ReplicaMaxConns),
+		ReplicaMinConns: int32(cfg.ReplicaMinConns),
+		ReadYourWrites:  cfg.ReadYourWrites,
+	}, log)
 	if err != nil {
 		log.Fatal("failed to initialize storage", zap.Error(err))
 	}
 	repo := storage.NewRepository()
 
+	webhookNotifier := webhook.NewNotifier(repo)
+	// Every consumer of changes is a sink of its own, so a failing one is retried alone.
+	changeSinks := []outbox.NamedSink{{Name: "user_webhooks", Sink: outbox.NewNotifierSink(webhookNotifier)}}
+
+	var calendarRepo service.CalendarRepository = repo
+	if viewCache := newCache(cfg.Cache, repo, log); viewCache != nil {
+		calendarRepo = viewCache
+		// Resource bookings bypass the cache and reach it through the outbox.
+		changeSinks = append(changeSinks, outbox.NamedSink{Name: "view_cache", Sink: outbox.NewNotifierSink(viewCache)})
+	}
+	calendarService := service.NewCalendarService(calendarRepo, log)
+	defer calendarService.CloseRepo()
+	reminderService := service.NewReminderService(repo, log)
+	webhookService := service.NewWebhookService(repo, log)
+	profileService := service.NewProfileService(repo, log)
+	schedulingService := service.NewSchedulingService(calendarService, repo, log)
+	resourceService := service.NewResourceService(calendarService, repo, log)
+	calendarService.SetResourceBooker(resourceService)
+	taskService := service.NewTaskService(calendarService, repo, log)
+	trashService := service.NewTrashService(calendarService, repo, log)
+	historyService := service.NewHistoryService(calendarService, repo, log)
+	syncService := service.NewSyncService(repo, log)
+	streamService := service.NewStreamService(cfg.StreamBuffer, log)
+	graphAPI, err := graphapi.New(calendarService, reminderService, cfg.GraphQLMaxComplexity, log)
+	if err != nil {
+		log.Fatal("failed to initialize GraphQL API", zap.Error(err))
+	}
+
+	rout := router.NewRouter(router.Handlers{
+		Calendar:   handlers.NewCalendarHandler(calendarService, taskService),
+		Reminder:   handlers.NewReminderHandler(reminderService),
+		Webhook:    handlers.NewWebhookHandler(webhookService),
+		Profile:    handlers.NewProfileHandler(profileService),
+		Scheduling: handlers.NewSchedulingHandler(schedulingService),
+		Resource:   handlers.NewResourceHandler(resourceService),
+		Task:       handlers.NewTaskHandler(taskService),
+		Trash:      handlers.NewTrashHandler(trashService),
+		History:    handlers.NewHistoryHandler(historyService),
+		Sync:       handlers.NewSyncHandler(syncService),
+		Stream:     handlers.NewStreamHandler(streamService, cfg.StreamHeartbeat),
+		Session:    handlers.NewSessionHandler(calendarService, streamService, cfg.StreamHeartbeat),
+		GraphQL:    handlers.NewGraphQLHandler(graphAPI),
+
+		ITIPReplySecret: itipReplySecret(cfg, log),
+	}, cfg.LogLevel, log)
+	// Reminder channels are retried independently, so a failing one does not repeat the others.
+	reminderChannels := []scheduler.Channel{
+		{Name: "log", Notifier: notifier.NewLogNotifier(log)},
+		{Name: "webhook", Notifier: webhookNotifier},
+	}
+	var itipSender itip.Sender = itip.NewLogSender(log)
+	if cfg.SMTP.Host != "" {
+		templates, err := email.NewTemplates()
+		if err != nil {
+			log.Fatal("failed to load email templates", zap.Error(err))
+		}
+		mailer := email.NewMailer(email.Config(cfg.SMTP))
+		reminderChannels = append(reminderChannels, scheduler.Channel{Name: "email", Notifier: email.NewNotifier(mailer, templates, repo, log)})
+		itipSender = itip.NewEmailSender(mailer)
+	}
+	// Invitations for external attendees follow the outbox so they survive restarts.
+	itipNotifier := itip.NewNotifier(itipSender, repo, cfg.SMTP.From, log)
+	changeSinks = append(changeSinks, outbox.NamedSink{Name: "itip", Sink: outbox.NewNotifierSink(itipNotifier)})
+	reminderScheduler := scheduler.NewScheduler(repo, reminderChannels, cfg.ReminderPollInterval, log)
+	webhookDispatcher := webhook.NewDispatcher(repo, webhook.NewSender(cfg.WebhookTimeout), cfg.WebhookPollInterval, log)
+	sinks, err := newOutboxSinks(cfg.Outbox, changeSinks, cfg.WebhookTimeout)
+	if err != nil {
+		log.Fatal("failed to initialize outbox sinks", zap.Error(err))
+	}
+	// Every change also reaches the event streams of all instances.
+	sinks = append(sinks, outbox.NamedSink{Name: "realtime", Sink: realtime.NewBroadcaster(repo)})
+	outboxRelay := outbox.NewRelay(repo, sinks, cfg.Outbox.PollInterval, log)
+	outboxPruner := outbox.NewPruner(repo, cfg.Outbox.Retention, cfg.Outbox.PruneInterval, log)
+	changeListener := realtime.NewListener(repo, streamService, time.Second, log)
+	trashPurger := trash.NewPurger(repo, cfg.TrashRetention, cfg.TrashPurgeInterval, log)
+	syncPruner := syncfeed.NewPruner(repo, cfg.SyncRetention, cfg.SyncPruneInterval, log)
+	app := application.NewApp(rout, cfg.Addr, log, reminderScheduler, webhookDispatcher, outboxRelay, outboxPruner, trashPurger, syncPruner, changeListener)
+	serveGRPC(app, cfg, grpcserver.New(calendarService, schedulingService, streamService, log), log)
+	if err := app.Run(); err != nil {
+		log.Fatal("failed to run app", zap.Error(err))
+	}
+}
+
+// runStandalone serves the event endpoints from a repository without the Postgres-only
+// features: reminders, webhooks, profiles, resources, tasks, the trash, the audit log, the
+// change feed, event streams, live sessions and the gRPC Watch call are not available,
+// deletes are permanent and changes are only logged.
+func runStandalone(cfg *config.Config, repo service.CalendarRepository, log *zap.Logger) {
+	if viewCache := newCache(cfg.Cache, repo, log); viewCache != nil {
+		repo = viewCache
+	}
 	calendarService := service.NewCalendarService(repo, log)
 	defer calendarService.CloseRepo()
-	handler := handlers.NewCalendarHandler(calendarService)
+	calendarService.SetNotifier(notifier.NewLogNotifier(log))
+	graphAPI, err := graphapi.New(calendarService, nil, cfg.GraphQLMaxComplexity, log)
+	if err != nil {
+		log.Fatal("failed to initialize GraphQL API", zap.Error(err))
+	}
+	rout := router.NewRouter(router.Handlers{
+		Calendar: handlers.NewCalendarHandler(calendarService, nil),
+		GraphQL:  handlers.NewGraphQLHandler(graphAPI),
 
-	rout := router.NewRouter(handler, cfg.LogLevel, log)
+		ITIPReplySecret: itipReplySecret(cfg, log),
+	}, cfg.LogLevel, log)
 	app := application.NewApp(rout, cfg.Addr, log)
+	serveGRPC(app, cfg, grpcserver.New(calendarService, calendarService, nil, log), log)
 	if err := app.Run(); err != nil {
 		log.Fatal("failed to run app", zap.Error(err))
 	}
 }
+
+// itipReplySecret returns the token the mail gateway authenticates iTIP replies with.
+func itipReplySecret(cfg *config.Config, log *zap.Logger) string {
+	if cfg.ITIPReplySecret == "" {
+		log.Warn("ITIP_REPLY_SECRET is not set; iTIP replies are not accepted")
+	}
+	return cfg.ITIPReplySecret
+}
+
+// serveGRPC adds the gRPC API to app when GRPC_ADDR is set.
+func serveGRPC(app *application.App, cfg *config.Config, srv *grpcserver.Server, log *zap.Logger) {
+	if cfg.GRPCAddr == "" {
+		return
+	}
+	if cfg.GRPCToken == "" {
+		log.Warn("GRPC_TOKEN is not set; the gRPC API accepts unauthenticated calls")
+	}
+	app.ServeGRPC(srv.Register(cfg.GRPCToken), cfg.GRPCAddr)
+}
+
+// newCache wraps repo with the configured view cache, or returns nil when caching is off.
+func newCache(cfg config.Cache, repo service.CalendarRepository, log *zap.Logger) *cache.Repository {
+	switch cfg.Driver {
+	case config.CacheMemory:
+		return cache.New(repo, cache.NewLRU(cfg.Size, cfg.TTL), log)
+	case config.CacheRedis:
+		if cfg.RedisAddr == "" {
+			log.Fatal("CACHE_REDIS_ADDR is required for the redis cache")
+		}
+		return cache.New(repo, cache.NewRedis(cfg.RedisAddr, cfg.TTL, time.Second), log)
+	}
+	return nil
+}
+
+// newOutboxSinks adds the configured downstream sinks to the given ones. The sink names
+// record deliveries and must stay stable.
+func newOutboxSinks(cfg config.Outbox, sinks []outbox.NamedSink, timeout time.Duration) ([]outbox.NamedSink, error) {
+	for _, name := range cfg.Sinks {
+		var sink outbox.Sink
+		switch name {
+		case "stdout":
+			sink = outbox.NewStdoutSink()
+		case "file":
+			fileSink, err := outbox.NewFileSink(cfg.File)
+			if err != nil {
+				return nil, err
+			}
+			sink = fileSink
+		case "webhook":
+			if cfg.WebhookURL == "" {
+				return nil, fmt.Errorf("OUTBOX_WEBHOOK_URL is required for the webhook sink")
+			}
+			sink = outbox.NewWebhookSink(cfg.WebhookURL, cfg.WebhookSecret, timeout)
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+		sinks = append(sinks, outbox.NamedSink{Name: name, Sink: sink})
+	}
+	return sinks, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"awesomeProject/internal/config"
+	"awesomeProject/internal/repository"
+	"awesomeProject/internal/repository/sqlite"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+)
+
+const migrateUsage = "usage: migrate up [N] | down [N] | goto VERSION | force VERSION | version"
+
+// runMigrate runs a migration command against the configured database:
+//
+//	up [N]         apply all or the next N pending migrations
+//	down [N]       roll back the last N migrations, one by default
+//	goto VERSION   migrate up or down to VERSION
+//	force VERSION  set VERSION without running migrations, clearing the dirty flag
+//	version        print the current version
+func runMigrate(ctx context.Context, cfg *config.Config, args []string, log *zap.Logger) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(migrateUsage)
+	}
+	var m *migrate.Migrate
+	var err error
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		m, err = repository.NewMigrator(repository.ConnString(cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode))
+	case config.DriverSQLite:
+		if cfg.Path == "" {
+			return errors.New("DB_PATH is required for the sqlite driver")
+		}
+		m, err = sqlite.NewMigrator(ctx, cfg.Path)
+	default:
+		return fmt.Errorf("the %s driver has no migrations", cfg.Driver)
+	}
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	m.Log = migrateLogger{log: log.Named("migrate")}
+
+	command := args[0]
+	arg := -1
+	if len(args) == 2 {
+		if arg, err = strconv.Atoi(args[1]); err != nil || arg < 0 {
+			return fmt.Errorf("invalid argument %q: %s", args[1], migrateUsage)
+		}
+	}
+	switch {
+	case command == "up" && arg < 0:
+		err = m.Up()
+	case command == "up":
+		err = m.Steps(arg)
+	case command == "down" && arg < 0:
+		err = m.Steps(-1)
+	case command == "down":
+		err = m.Steps(-arg)
+	case command == "goto" && arg >= 0:
+		err = m.Migrate(uint(arg))
+	case command == "force" && arg >= 0:
+		err = m.Force(arg)
+	case command == "version" && len(args) == 1:
+	default:
+		return errors.New(migrateUsage)
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Info("no change")
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		log.Info("no migrations applied")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("database version", zap.Uint("version", version), zap.Bool("dirty", dirty))
+	return nil
+}
+
+// migrateLogger reports migration progress through zap.
+type migrateLogger struct {
+	log *zap.Logger
+}
+
+func (l migrateLogger) Printf(format string, v ...interface{}) {
+	l.log.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l migrateLogger) Verbose() bool {
+	return false
+}
@@ -2,28 +2,64 @@ package application
 
 import (
 	"awesomeProject/internal/router"
+	"context"
 	"errors"
 	"fmt"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"net"
 	"net/http"
 )
 
+// Worker is a background job that runs alongside the HTTP server until ctx is cancelled.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
 type App struct {
 	router     *router.Router
 	httpServer *http.Server
+	grpcServer *grpc.Server
+	grpcAddr   string
+	workers    []Worker
 	log        *zap.Logger
 }
 
-func NewApp(router *router.Router, addr string, log *zap.Logger) *App {
+func NewApp(router *router.Router, addr string, log *zap.Logger, workers ...Worker) *App {
 	return &App{router: router,
 		httpServer: &http.Server{
 			Addr:    addr,
 			Handler: router.GetHTTPHandler(),
 		},
-		log: log}
+		workers: workers,
+		log:     log}
+}
+
+// ServeGRPC makes Run serve srv on addr alongside the HTTP server.
+func (a *App) ServeGRPC(srv *grpc.Server, addr string) {
+	a.grpcServer, a.grpcAddr = srv, addr
 }
 
 func (a *App) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, w := range a.workers {
+		go w.Run(ctx)
+	}
+	if a.grpcServer != nil {
+		lis, err := net.Listen("tcp", a.grpcAddr)
+		if err != nil {
+			return fmt.Errorf("gRPC server failed: %w", err)
+		}
+		a.log.Info("Starting gRPC server", zap.String("address", a.grpcAddr))
+		go func() {
+			if err := a.grpcServer.Serve(lis); err != nil {
+				a.log.Error("gRPC server failed", zap.Error(err))
+			}
+		}()
+		defer a.grpcServer.Stop()
+	}
+
 	a.log.Info("Starting HTTP server", zap.String("address", a.httpServer.Addr))
 	if err := a.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		a.log.Error("HTTP server failed", zap.Error(err))
@@ -0,0 +1,80 @@
+// Package audit carries the request ID of a change down to the storage layer and computes
+// the field changes recorded in the audit log.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the ID of the request that causes its changes.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID of ctx, or "" for changes made outside a request.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Change is the old and new value of a field; a missing side is null.
+type Change struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// Diff compares two JSON objects, either of which may be empty, and returns the changed
+// top-level fields.
+func Diff(before, after []byte) (map[string]Change, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]Change)
+	for key, from := range old {
+		if to, ok := cur[key]; !ok || !equal(from, to) {
+			changes[key] = Change{From: from, To: orNull(to)}
+		}
+	}
+	for key, to := range cur {
+		if _, ok := old[key]; !ok {
+			changes[key] = Change{From: orNull(nil), To: to}
+		}
+	}
+	return changes, nil
+}
+
+func fields(data []byte) (map[string]json.RawMessage, error) {
+	m := make(map[string]json.RawMessage)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return m, nil
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to diff: %w", err)
+	}
+	return m, nil
+}
+
+func equal(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+func orNull(v json.RawMessage) json.RawMessage {
+	if v == nil {
+		return json.RawMessage("null")
+	}
+	return v
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	before := []byte(`{"Event":"Sync","Start":"2025-06-02T09:00:00Z","Attendees":[{"UserID":2}],"Gone":1}`)
+	after := []byte(`{"Event":"Sync", "Start":"2025-06-02T10:00:00Z","Attendees":[{"UserID":2}],"New":true}`)
+
+	changes, err := Diff(before, after)
+	require.NoError(t, err)
+	got, err := json.Marshal(changes)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"Start": {"from": "2025-06-02T09:00:00Z", "to": "2025-06-02T10:00:00Z"},
+		"Gone": {"from": 1, "to": null},
+		"New": {"from": null, "to": true}
+	}`, string(got))
+}
+
+func TestDiff_MissingSide(t *testing.T) {
+	changes, err := Diff(nil, []byte(`{"Event":"Sync"}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]Change{"Event": {From: json.RawMessage("null"), To: json.RawMessage(`"Sync"`)}}, changes)
+
+	changes, err = Diff([]byte(`null`), nil)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = Diff([]byte(`[1]`), nil)
+	require.Error(t, err)
+}
+
+func TestRequestID(t *testing.T) {
+	require.Empty(t, RequestID(context.Background()))
+	require.Equal(t, "abc", RequestID(WithRequestID(context.Background(), "abc")))
+}
@@ -1,22 +1,113 @@
 package config
 
 import (
+	"fmt"
 	"github.com/joho/godotenv"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Addr     string
-	LogLevel string
+	Addr                 string
+	LogLevel             string
+	ReminderPollInterval time.Duration
+	WebhookPollInterval  time.Duration
+	WebhookTimeout       time.Duration
+	SMTP                 SMTP
+	Outbox               Outbox
+	Cache                Cache
+	// TrashRetention is how long deleted events can be restored before they are purged.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+	// SyncRetention is how long deletions stay in the change feed; older sync tokens expire.
+	SyncRetention     time.Duration
+	SyncPruneInterval time.Duration
+	// StreamHeartbeat is how often idle event streams send a comment to stay open, and
+	// StreamBuffer how many changes a stream may fall behind before it is closed.
+	StreamHeartbeat time.Duration
+	StreamBuffer    int
+	// GRPCAddr enables the gRPC API on this address; calls must carry GRPCToken when it is set.
+	// The token is meant for trusted services: it grants access to every calendar.
+	GRPCAddr  string
+	GRPCToken string
+	// ITIPReplySecret is the bearer token the inbound mail gateway sends with iTIP replies;
+	// /itip_reply is not served without it.
+	ITIPReplySecret string
+	// GraphQLMaxComplexity is the highest score of a GraphQL query that is still run.
+	GraphQLMaxComplexity int
 	Storage
 }
+
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+	DriverSQLite   = "sqlite"
+)
+
 type Storage struct {
+	// Driver selects the event storage: postgres (default), memory or sqlite.
+	Driver string
+	// Path is the SQLite database file.
+	Path     string
 	User     string
 	Password string
 	Host     string
 	Port     string
 	DBName   string
 	SSLMode  string
+	// AutoMigrate applies pending migrations at server start; disable it to run them
+	// separately with the migrate subcommand.
+	AutoMigrate bool
+	// MaxConns and MinConns size the primary pool, ReplicaMaxConns and ReplicaMinConns
+	// the pool of each replica.
+	MaxConns        int
+	MinConns        int
+	ReplicaMaxConns int
+	ReplicaMinConns int
+	// Replicas are connection strings of Postgres read replicas.
+	Replicas []string
+	// ReadYourWrites is how long a user's reads stay on the primary after their write.
+	ReadYourWrites time.Duration
+}
+
+const (
+	CacheNone   = "none"
+	CacheMemory = "memory"
+	CacheRedis  = "redis"
+)
+
+// Cache configures the cache of the day, week and month views.
+type Cache struct {
+	// Driver is none (default), memory for an in-process LRU or redis.
+	Driver string
+	// Size is the number of views the in-process LRU holds.
+	Size      int
+	TTL       time.Duration
+	RedisAddr string
+}
+
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	StartTLS bool
+	Timeout  time.Duration
+}
+
+type Outbox struct {
+	// Sinks lists extra downstream sinks: stdout, file, webhook.
+	Sinks         []string
+	File          string
+	WebhookURL    string
+	WebhookSecret string
+	PollInterval  time.Duration
+	// Retention is how long published messages are kept before they are pruned.
+	Retention     time.Duration
+	PruneInterval time.Duration
 }
 
 func MustLoad(path string) *Config {
@@ -24,16 +115,124 @@ func MustLoad(path string) *Config {
 		panic(".env file not found")
 	}
 	stor := Storage{
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		Host:     os.Getenv("DB_HOST"),
-		Port:     os.Getenv("DB_PORT"),
-		DBName:   os.Getenv("DB_NAME"),
-		SSLMode:  os.Getenv("DB_SSLMODE"),
+		Driver:      mustOneOf("DB_DRIVER", DriverPostgres, DriverMemory, DriverSQLite),
+		Path:        os.Getenv("DB_PATH"),
+		AutoMigrate: mustBool("DB_AUTO_MIGRATE", true),
+		User:        os.Getenv("DB_USER"),
+		Password:    os.Getenv("DB_PASSWORD"),
+		Host:        os.Getenv("DB_HOST"),
+		Port:        os.Getenv("DB_PORT"),
+		DBName:      os.Getenv("DB_NAME"),
+		SSLMode:     os.Getenv("DB_SSLMODE"),
+
+		MaxConns:        mustInt("DB_MAX_CONNS", 10, 1),
+		MinConns:        mustInt("DB_MIN_CONNS", 2, 0),
+		ReplicaMaxConns: mustInt("DB_REPLICA_MAX_CONNS", 10, 1),
+		ReplicaMinConns: mustInt("DB_REPLICA_MIN_CONNS", 2, 0),
+		Replicas:        splitList(os.Getenv("DB_REPLICAS")),
+		ReadYourWrites:  mustDuration("DB_READ_YOUR_WRITES", 5*time.Second),
 	}
 	return &Config{
-		Addr:     os.Getenv("ADDR"),
-		LogLevel: os.Getenv("LOG_LEVEL"),
-		Storage:  stor,
+		Addr:                 os.Getenv("ADDR"),
+		LogLevel:             os.Getenv("LOG_LEVEL"),
+		ReminderPollInterval: mustDuration("REMINDER_POLL_INTERVAL", 30*time.Second),
+		WebhookPollInterval:  mustDuration("WEBHOOK_POLL_INTERVAL", 10*time.Second),
+		WebhookTimeout:       mustDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		TrashRetention:       mustDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval:   mustDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		SyncRetention:        mustDuration("SYNC_RETENTION", 30*24*time.Hour),
+		SyncPruneInterval:    mustDuration("SYNC_PRUNE_INTERVAL", time.Hour),
+		StreamHeartbeat:      mustDuration("STREAM_HEARTBEAT", 15*time.Second),
+		StreamBuffer:         mustInt("STREAM_BUFFER", 64, 1),
+		GRPCAddr:             os.Getenv("GRPC_ADDR"),
+		GRPCToken:            os.Getenv("GRPC_TOKEN"),
+		ITIPReplySecret:      os.Getenv("ITIP_REPLY_SECRET"),
+		GraphQLMaxComplexity: mustInt("GRAPHQL_MAX_COMPLEXITY", 5000, 1),
+		SMTP: SMTP{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+			StartTLS: mustBool("SMTP_STARTTLS", true),
+			Timeout:  mustDuration("SMTP_TIMEOUT", 10*time.Second),
+		},
+		Outbox: Outbox{
+			Sinks:         splitList(os.Getenv("OUTBOX_SINKS")),
+			File:          os.Getenv("OUTBOX_FILE"),
+			WebhookURL:    os.Getenv("OUTBOX_WEBHOOK_URL"),
+			WebhookSecret: os.Getenv("OUTBOX_WEBHOOK_SECRET"),
+			PollInterval:  mustDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			Retention:     mustDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+			PruneInterval: mustDuration("OUTBOX_PRUNE_INTERVAL", time.Hour),
+		},
+		Cache: Cache{
+			Driver:    mustOneOf("CACHE_DRIVER", CacheNone, CacheMemory, CacheRedis),
+			Size:      mustInt("CACHE_SIZE", 10000, 1),
+			TTL:       mustDuration("CACHE_TTL", time.Minute),
+			RedisAddr: os.Getenv("CACHE_REDIS_ADDR"),
+		},
+		Storage: stor,
+	}
+}
+
+func mustDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		panic(fmt.Sprintf("invalid %s: %q", key, v))
+	}
+	return d
+}
+
+// mustInt returns the integer value of key, which must be at least min.
+func mustInt(key string, def, min int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min {
+		panic(fmt.Sprintf("invalid %s: %q", key, v))
+	}
+	return n
+}
+
+func mustBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		panic(fmt.Sprintf("invalid %s: %q", key, v))
+	}
+	return b
+}
+
+// mustOneOf returns the value of key, which must be one of allowed; the first is the default.
+func mustOneOf(key string, allowed ...string) string {
+	v := os.Getenv(key)
+	if v == "" {
+		return allowed[0]
+	}
+	for _, a := range allowed {
+		if v == a {
+			return v
+		}
+	}
+	panic(fmt.Sprintf("invalid %s: %q", key, v))
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
 	}
+	return out
 }
@@ -0,0 +1,224 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeSMTPServer is a minimal local SMTP server accepting AUTH PLAIN and recording messages.
+type fakeSMTPServer struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	auth     string
+	from     string
+	rcpts    []string
+	messages [][]byte
+}
+
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeSMTPServer{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) addr() (string, string) {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	return host, port
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost fake smtp")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH PLAIN"):
+			s.mu.Lock()
+			s.auth = strings.TrimSpace(line[len("AUTH PLAIN"):])
+			s.mu.Unlock()
+			reply("235 2.7.0 Authentication successful")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, []byte(data.String()))
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+type fakeRecipients map[int64]*models.Profile
+
+func (f fakeRecipients) GetProfile(ctx context.Context, userID int64) (*models.Profile, error) {
+	p, ok := f[userID]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return p, nil
+}
+
+func newTestNotifier(t *testing.T, srv *fakeSMTPServer, recipients fakeRecipients) *Notifier {
+	host, port := srv.addr()
+	mailer := NewMailer(Config{
+		Host:     host,
+		Port:     port,
+		Username: "calendar",
+		Password: "secret",
+		From:     "calendar@example.com",
+		Timeout:  time.Second,
+	})
+	templates, err := NewTemplates()
+	require.NoError(t, err)
+	return NewNotifier(mailer, templates, recipients, zap.NewNop())
+}
+
+func TestNotifier_SendsReminderWithInvite(t *testing.T) {
+	srv := startFakeSMTPServer(t)
+	n := newTestNotifier(t, srv, fakeRecipients{
+		7: {UserID: 7, Email: "alice@example.com", Name: "Alice"},
+	})
+
+	err := n.Notify(context.Background(), models.Notification{
+		Type:     models.NotificationReminder,
+		UserID:   7,
+		Event:    models.Event{ID: 11, UserID: 7, Date: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), Event: "Pi <day>"},
+		Reminder: &models.Reminder{ID: 1, EventID: 11, UserID: 7, Before: 24 * time.Hour},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, srv.messages, 1)
+	require.Equal(t, "calendar@example.com", srv.from)
+	require.Equal(t, []string{"alice@example.com"}, srv.rcpts)
+	creds, err := base64.StdEncoding.DecodeString(srv.auth)
+	require.NoError(t, err)
+	require.Equal(t, "\x00calendar\x00secret", string(creds))
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(srv.messages[0])))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Reminder: Pi <day> on Friday, 14 March 2025", subject)
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	parts := readParts(t, multipart.NewReader(msg.Body, params["boundary"]))
+	require.Contains(t, parts["text/plain"], `"Pi <day>" is scheduled for Friday, 14 March 2025 (in 24h0m0s)`)
+	require.Contains(t, parts["text/html"], "<strong>Pi &lt;day&gt;</strong>")
+	ics := parts["text/calendar"]
+	require.Contains(t, ics, "METHOD:PUBLISH\r\n")
+	require.Contains(t, ics, "UID:event-11@awesomeProject\r\n")
+	require.Contains(t, ics, "DTSTART;VALUE=DATE:20250314\r\n")
+	require.Contains(t, ics, "DTEND;VALUE=DATE:20250315\r\n")
+	require.Contains(t, ics, `SUMMARY:Pi <day>`)
+}
+
+func TestNotifier_SkipsUsersWithoutEmail(t *testing.T) {
+	srv := startFakeSMTPServer(t)
+	n := newTestNotifier(t, srv, fakeRecipients{8: {UserID: 8}})
+
+	for _, userID := range []int64{8, 9} {
+		err := n.Notify(context.Background(), models.Notification{Type: models.NotificationReminder, UserID: userID})
+		require.NoError(t, err)
+	}
+	require.Empty(t, srv.messages)
+}
+
+func TestMailer_StartTLSRequired(t *testing.T) {
+	srv := startFakeSMTPServer(t)
+	host, port := srv.addr()
+	m := NewMailer(Config{Host: host, Port: port, From: "calendar@example.com", StartTLS: true, Timeout: time.Second})
+
+	err := m.Send(context.Background(), []string{"bob@example.com"}, []byte("Subject: hi\r\n\r\nhi\r\n"))
+	require.ErrorIs(t, err, ErrStartTLSUnsupported)
+	require.Empty(t, srv.messages)
+}
+
+// readParts flattens a multipart tree into decoded bodies keyed by media type.
+func readParts(t *testing.T, r *multipart.Reader) map[string]string {
+	out := map[string]string{}
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			return out
+		}
+		require.NoError(t, err)
+		mediaType, params, err := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		if strings.HasPrefix(mediaType, "multipart/") {
+			for k, v := range readParts(t, multipart.NewReader(p, params["boundary"])) {
+				out[k] = v
+			}
+			continue
+		}
+		var body []byte
+		if p.Header.Get("Content-Transfer-Encoding") == "base64" {
+			body, err = io.ReadAll(base64.NewDecoder(base64.StdEncoding, p))
+		} else {
+			body, err = io.ReadAll(p)
+		}
+		require.NoError(t, err)
+		out[mediaType] = string(body)
+	}
+}
@@ -0,0 +1,92 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+var ErrStartTLSUnsupported = errors.New("smtp server does not support STARTTLS")
+
+// Config describes how to reach the SMTP relay.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	// StartTLS requires upgrading the connection before authenticating.
+	StartTLS bool
+	Timeout  time.Duration
+}
+
+// Mailer sends messages through a single SMTP relay.
+type Mailer struct {
+	cfg       Config
+	tlsConfig *tls.Config
+}
+
+func NewMailer(cfg Config) *Mailer {
+	return &Mailer{cfg: cfg, tlsConfig: &tls.Config{ServerName: cfg.Host}}
+}
+
+func (m *Mailer) From() string {
+	return m.cfg.From
+}
+
+// Send delivers a fully rendered message to the given recipients.
+func (m *Mailer) Send(ctx context.Context, to []string, msg []byte) error {
+	dialer := &net.Dialer{Timeout: m.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.cfg.Host, m.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else if m.cfg.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(m.cfg.Timeout))
+	}
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer c.Close()
+
+	if m.cfg.StartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return ErrStartTLSUnsupported
+		}
+		if err := c.StartTLS(m.tlsConfig); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+	if err := c.Mail(m.cfg.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,116 @@
+package email
+
+import (
+	"awesomeProject/internal/ical"
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+type RecipientStore interface {
+	GetProfile(ctx context.Context, userID int64) (*models.Profile, error)
+}
+
+// Notifier emails notifications to the address in the recipient's profile,
+// attaching the event as an .ics invite. Users without an email address are skipped.
+type Notifier struct {
+	mailer     *Mailer
+	templates  *Templates
+	recipients RecipientStore
+	now        func() time.Time
+	log        *zap.Logger
+}
+
+func NewNotifier(mailer *Mailer, templates *Templates, recipients RecipientStore, log *zap.Logger) *Notifier {
+	return &Notifier{
+		mailer:     mailer,
+		templates:  templates,
+		recipients: recipients,
+		now:        time.Now,
+		log:        log.Named("EmailNotifier"),
+	}
+}
+
+func (n *Notifier) Notify(ctx context.Context, notification models.Notification) error {
+	profile, err := n.recipients.GetProfile(ctx, notification.UserID)
+	if errors.Is(err, models.ErrNotFound) || (err == nil && profile.Email == "") {
+		n.log.Debug("No email address for user, skipping", zap.Int64("user_id", notification.UserID))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up recipient: %w", err)
+	}
+
+	data := templateData{
+		Name:  profile.Name,
+		Event: notification.Event,
+		Date:  notification.Event.Date.Format("Monday, 02 January 2006"),
+	}
+	if notification.Reminder != nil && notification.Reminder.Before > 0 {
+		data.Before = notification.Reminder.Before.String()
+	}
+	subject, text, html, err := n.templates.Render(notification.Type, data)
+	if err != nil {
+		return err
+	}
+
+	now := n.now()
+	msg := &Message{
+		From:    n.mailer.From(),
+		To:      []string{profile.Email},
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+		Date:    now,
+		Attachments: []Attachment{{
+			Filename:    "invite.ics",
+			ContentType: "text/calendar; charset=utf-8; method=" + inviteMethod(notification.Type),
+			Data:        invite(notification, now),
+		}},
+	}
+	body, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+	if err := n.mailer.Send(ctx, msg.To, body); err != nil {
+		return err
+	}
+	n.log.Debug("Email sent", zap.Int64("user_id", notification.UserID), zap.String("type", notification.Type))
+	return nil
+}
+
+func inviteMethod(notificationType string) string {
+	if notificationType == models.NotificationEventDeleted {
+		return ical.MethodCancel
+	}
+	return ical.MethodPublish
+}
+
+func invite(notification models.Notification, now time.Time) []byte {
+	status := ical.StatusConfirmed
+	if notification.Type == models.NotificationEventDeleted {
+		status = ical.StatusCancelled
+	}
+	ev := notification.Event
+	start, end, allDay := ev.Start, ev.End, ev.AllDay
+	if start.IsZero() {
+		start, end, allDay = ev.Date, ev.Date.AddDate(0, 0, 1), true
+	}
+	cal := &ical.Calendar{
+		Method: inviteMethod(notification.Type),
+		Events: []ical.Event{{
+			UID:     ical.EventUID(ev.ID),
+			Summary: ev.Event,
+			Start:   start,
+			End:     end,
+			AllDay:  allDay,
+			RRule:   ev.RRule,
+			Status:  status,
+			Stamp:   now,
+		}},
+	}
+	return cal.Encode()
+}
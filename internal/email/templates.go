@@ -0,0 +1,117 @@
+package email
+
+import (
+	"awesomeProject/internal/models"
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// templateData is what notification templates are rendered with.
+type templateData struct {
+	Name   string
+	Event  models.Event
+	Date   string
+	Before string
+}
+
+type template struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+type templateSource struct {
+	subject, text, html string
+}
+
+var templateSources = map[string]templateSource{
+	models.NotificationReminder: {
+		subject: `Reminder: {{.Event.Event}} on {{.Date}}`,
+		text: `Hi{{with .Name}} {{.}}{{end}},
+
+this is a reminder that "{{.Event.Event}}" is scheduled for {{.Date}}{{with .Before}} (in {{.}}){{end}}.
+
+The event is attached as an invite.
+`,
+		html: `<p>Hi{{with .Name}} {{.}}{{end}},</p>
+<p>this is a reminder that <strong>{{.Event.Event}}</strong> is scheduled for {{.Date}}{{with .Before}} (in {{.}}){{end}}.</p>
+<p>The event is attached as an invite.</p>
+`,
+	},
+	models.NotificationEventCreated: {
+		subject: `New event: {{.Event.Event}} on {{.Date}}`,
+		text: `Hi{{with .Name}} {{.}}{{end}},
+
+"{{.Event.Event}}" was added to your calendar for {{.Date}}.
+`,
+		html: `<p>Hi{{with .Name}} {{.}}{{end}},</p>
+<p><strong>{{.Event.Event}}</strong> was added to your calendar for {{.Date}}.</p>
+`,
+	},
+	models.NotificationEventUpdated: {
+		subject: `Updated event: {{.Event.Event}} on {{.Date}}`,
+		text: `Hi{{with .Name}} {{.}}{{end}},
+
+"{{.Event.Event}}" was updated and is now scheduled for {{.Date}}.
+`,
+		html: `<p>Hi{{with .Name}} {{.}}{{end}},</p>
+<p><strong>{{.Event.Event}}</strong> was updated and is now scheduled for {{.Date}}.</p>
+`,
+	},
+	models.NotificationEventDeleted: {
+		subject: `Cancelled: event on {{.Date}}`,
+		text: `Hi{{with .Name}} {{.}}{{end}},
+
+your event{{with .Event.Event}} "{{.}}"{{end}} on {{.Date}} was removed from your calendar.
+`,
+		html: `<p>Hi{{with .Name}} {{.}}{{end}},</p>
+<p>your event{{with .Event.Event}} <strong>{{.}}</strong>{{end}} on {{.Date}} was removed from your calendar.</p>
+`,
+	},
+}
+
+// Templates renders notification emails by notification type.
+type Templates struct {
+	byType map[string]template
+}
+
+func NewTemplates() (*Templates, error) {
+	t := &Templates{byType: make(map[string]template, len(templateSources))}
+	for typ, src := range templateSources {
+		subject, err := texttemplate.New(typ + ".subject").Parse(src.subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s subject template: %w", typ, err)
+		}
+		text, err := texttemplate.New(typ + ".txt").Parse(src.text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s text template: %w", typ, err)
+		}
+		html, err := htmltemplate.New(typ + ".html").Parse(src.html)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s html template: %w", typ, err)
+		}
+		t.byType[typ] = template{subject: subject, text: text, html: html}
+	}
+	return t, nil
+}
+
+// Render returns the subject, text and HTML bodies for a notification type.
+func (t *Templates) Render(typ string, data templateData) (subject, text, html string, err error) {
+	tmpl, ok := t.byType[typ]
+	if !ok {
+		return "", "", "", fmt.Errorf("no email template for notification type %q", typ)
+	}
+	var subj, txt, htm bytes.Buffer
+	if err := tmpl.subject.Execute(&subj, data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := tmpl.text.Execute(&txt, data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render text body: %w", err)
+	}
+	if err := tmpl.html.Execute(&htm, data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render html body: %w", err)
+	}
+	return subj.String(), txt.String(), htm.String(), nil
+}
@@ -0,0 +1,105 @@
+package graphapi
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listSize is the number of items a list field is expected to return when a query is scored.
+const listSize = 10
+
+// scorer estimates the cost of an operation before it runs. Every field costs one, and the
+// fields selected under a list count once per expected item: one per requested user for
+// calendars, listSize for the other lists.
+type scorer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+// complexity scores the named operation of a validated document, or its only operation.
+func complexity(schema graphql.Schema, doc *ast.Document, operationName string, variables map[string]any) int {
+	s := &scorer{fragments: make(map[string]*ast.FragmentDefinition), variables: variables}
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			s.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || def.Name != nil && def.Name.Value == operationName {
+				operation = def
+			}
+		}
+	}
+	if operation == nil {
+		return 0
+	}
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	return s.selections(root, operation.SelectionSet)
+}
+
+// selections scores a selection set on parent; parent is nil below fields that are not
+// objects of the schema, like the introspection fields.
+func (s *scorer) selections(parent *graphql.Object, set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+	cost := 0
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			cost += s.field(parent, selection)
+		case *ast.InlineFragment:
+			cost += s.selections(parent, selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := s.fragments[selection.Name.Value]; ok {
+				cost += s.selections(parent, fragment.SelectionSet)
+			}
+		}
+	}
+	return cost
+}
+
+func (s *scorer) field(parent *graphql.Object, field *ast.Field) int {
+	var def *graphql.FieldDefinition
+	if parent != nil {
+		def = parent.Fields()[field.Name.Value]
+	}
+	if def == nil {
+		return 1 + s.selections(nil, field.SelectionSet)
+	}
+	items := 1
+	typ := def.Type
+	if nonNull, ok := typ.(*graphql.NonNull); ok {
+		typ = nonNull.OfType
+	}
+	if list, ok := typ.(*graphql.List); ok {
+		items = s.listSize(field)
+		typ = list.OfType
+		if nonNull, ok := typ.(*graphql.NonNull); ok {
+			typ = nonNull.OfType
+		}
+	}
+	object, _ := typ.(*graphql.Object)
+	return 1 + items*s.selections(object, field.SelectionSet)
+}
+
+// listSize returns the number of userIds a field asks for, or listSize.
+func (s *scorer) listSize(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "userIds" {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.ListValue:
+			return len(value.Values)
+		case *ast.Variable:
+			if ids, ok := s.variables[value.Name.Value].([]any); ok {
+				return len(ids)
+			}
+		}
+	}
+	return listSize
+}
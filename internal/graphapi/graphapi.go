@@ -0,0 +1,134 @@
+// Package graphapi serves calendars, their events, attendees and reminders over GraphQL,
+// with mutations for event CRUD. It shares the services of the HTTP API, so both apply
+// the same rules. Nested fields are loaded in batches per level of the query instead of
+// once per event, and queries scoring above a complexity limit are rejected before they run.
+package graphapi
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"go.uber.org/zap"
+)
+
+// Error codes reported in the extensions of GraphQL errors.
+const (
+	CodeBadUserInput = "BAD_USER_INPUT"
+	CodeNotFound     = "NOT_FOUND"
+	CodeForbidden    = "FORBIDDEN"
+	CodeConflict     = "CONFLICT"
+	CodeCancelled    = "CANCELLED"
+	CodeUnavailable  = "UNAVAILABLE"
+	CodeTooComplex   = "QUERY_TOO_COMPLEX"
+)
+
+type API struct {
+	schema   graphql.Schema
+	calendar *service.CalendarService
+	// reminders is nil when reminders are not stored, and Event.reminders is left out.
+	reminders     *service.ReminderService
+	maxComplexity int
+	log           *zap.Logger
+}
+
+func New(calendar *service.CalendarService, reminders *service.ReminderService, maxComplexity int, log *zap.Logger) (*API, error) {
+	a := &API{calendar: calendar, reminders: reminders, maxComplexity: maxComplexity, log: log.Named("GraphAPI")}
+	schema, err := a.newSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graphql schema: %w", err)
+	}
+	a.schema = schema
+	return a, nil
+}
+
+// Execute runs a query or mutation. The result has no data when the request did not run
+// because it failed to parse or validate, or scored above the complexity limit.
+func (a *API) Execute(ctx context.Context, query, operationName string, variables map[string]any) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if validation := graphql.ValidateDocument(&a.schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+	if cost := complexity(a.schema, doc, operationName, variables); cost > a.maxComplexity {
+		err := gqlerrors.NewFormattedError(fmt.Sprintf("query complexity %d exceeds the limit of %d", cost, a.maxComplexity))
+		err.Extensions = map[string]any{"code": CodeTooComplex}
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{err}}
+	}
+	ctx = context.WithValue(ctx, loadersKey{}, a.newLoaders(ctx))
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        a.schema,
+		AST:           doc,
+		OperationName: operationName,
+		Args:          variables,
+		Context:       ctx,
+	})
+	restoreCodes(result.Errors)
+	return result
+}
+
+// apiError is an error with its code in the extensions.
+type apiError struct {
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func (e *apiError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+func badInput(format string, args ...any) error {
+	return &apiError{code: CodeBadUserInput, message: fmt.Sprintf(format, args...)}
+}
+
+// toError maps service errors to coded errors; unexpected ones are logged and hidden.
+func (a *API) toError(err error) error {
+	var apiErr *apiError
+	switch {
+	case err == nil || errors.As(err, &apiErr):
+		return err
+	case errors.Is(err, service.ErrInvalidEvent), errors.Is(err, service.ErrInvalidWindow):
+		return &apiError{code: CodeBadUserInput, message: err.Error()}
+	case errors.Is(err, models.ErrNotFound):
+		return &apiError{code: CodeNotFound, message: err.Error()}
+	case errors.Is(err, models.ErrForbidden):
+		return &apiError{code: CodeForbidden, message: err.Error()}
+	case errors.Is(err, models.ErrConflict):
+		return &apiError{code: CodeConflict, message: err.Error()}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return &apiError{code: CodeCancelled, message: err.Error()}
+	}
+	a.log.Error("Calendar call failed", zap.Error(err))
+	return &apiError{code: CodeUnavailable, message: "calendar temporarily unavailable"}
+}
+
+// restoreCodes fills in the extensions graphql-go drops from the errors of thunks, which
+// it wraps twice more than those of plain resolvers.
+func restoreCodes(errs []gqlerrors.FormattedError) {
+	for i := range errs {
+		var err error = errs[i]
+		for err != nil && errs[i].Extensions == nil {
+			switch e := err.(type) {
+			case *apiError:
+				errs[i].Extensions = e.Extensions()
+			case gqlerrors.FormattedError:
+				err = e.OriginalError()
+			case *gqlerrors.Error:
+				err = e.OriginalError
+			default:
+				err = nil
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package graphapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/repository/memory"
+	"awesomeProject/internal/service"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingRepo counts the batch reads reaching the wrapped repository.
+type countingRepo struct {
+	service.CalendarRepository
+	ranges    int
+	attendees int
+}
+
+func (r *countingRepo) GetEventsInRange(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Event, error) {
+	r.ranges++
+	return r.CalendarRepository.GetEventsInRange(ctx, userIDs, from, to)
+}
+
+func (r *countingRepo) GetAttendees(ctx context.Context, eventIDs []int64) (map[int64][]models.Attendee, error) {
+	r.attendees++
+	return r.CalendarRepository.GetAttendees(ctx, eventIDs)
+}
+
+// fakeReminderRepo serves fixed reminders and counts the batch reads.
+type fakeReminderRepo struct {
+	reminders []models.Reminder
+	calls     int
+}
+
+func (f *fakeReminderRepo) CreateReminder(ctx context.Context, reminder *models.Reminder) error {
+	return nil
+}
+
+func (f *fakeReminderRepo) DeleteReminder(ctx context.Context, reminder *models.Reminder) error {
+	return nil
+}
+
+func (f *fakeReminderRepo) GetReminders(ctx context.Context, userID int64, eventID int64) ([]models.Reminder, error) {
+	return nil, nil
+}
+
+func (f *fakeReminderRepo) GetRemindersForEvents(ctx context.Context, userIDs, eventIDs []int64) ([]models.Reminder, error) {
+	f.calls++
+	var out []models.Reminder
+	for _, rem := range f.reminders {
+		for i := range userIDs {
+			if rem.UserID == userIDs[i] && rem.EventID == eventIDs[i] {
+				out = append(out, rem)
+			}
+		}
+	}
+	return out, nil
+}
+
+type fixture struct {
+	api       *API
+	calendar  *service.CalendarService
+	repo      *countingRepo
+	reminders *fakeReminderRepo
+}
+
+func newFixture(t *testing.T, maxComplexity int) *fixture {
+	t.Helper()
+	f := &fixture{repo: &countingRepo{CalendarRepository: memory.NewRepository(zap.NewNop())}, reminders: &fakeReminderRepo{}}
+	f.calendar = service.NewCalendarService(f.repo, zap.NewNop())
+	api, err := New(f.calendar, service.NewReminderService(f.reminders, zap.NewNop()), maxComplexity, zap.NewNop())
+	require.NoError(t, err)
+	f.api = api
+	return f
+}
+
+func (f *fixture) create(t *testing.T, event models.Event) int64 {
+	t.Helper()
+	event.Date = time.Date(event.Start.Year(), event.Start.Month(), event.Start.Day(), 0, 0, 0, 0, time.UTC)
+	require.NoError(t, f.calendar.CreateEvent(context.Background(), &event))
+	return event.ID
+}
+
+func at(d, h int) time.Time {
+	return time.Date(2025, 6, d, h, 0, 0, 0, time.UTC)
+}
+
+func data(t *testing.T, result *graphql.Result) string {
+	t.Helper()
+	require.Empty(t, result.Errors)
+	out, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func code(t *testing.T, result *graphql.Result) any {
+	t.Helper()
+	require.Len(t, result.Errors, 1)
+	return result.Errors[0].Extensions["code"]
+}
+
+func TestAPI_BatchesNestedFields(t *testing.T) {
+	f := newFixture(t, 5000)
+	planning := f.create(t, models.Event{UserID: 1, Event: "Planning", Start: at(2, 9), End: at(2, 10),
+		Attendees: []models.Attendee{{UserID: 3}, {Email: "guest@example.com"}}})
+	f.create(t, models.Event{UserID: 1, Event: "Review", Start: at(2, 14), End: at(2, 15)})
+	f.create(t, models.Event{UserID: 1, Event: "Tomorrow", Start: at(3, 9), End: at(3, 10)})
+	lunch := f.create(t, models.Event{UserID: 2, Event: "Lunch", Start: at(2, 12), End: at(2, 13),
+		Attendees: []models.Attendee{{UserID: 1}}})
+	f.create(t, models.Event{UserID: 3, Event: "Standup", Start: at(1, 8), End: at(1, 9), RRule: "FREQ=DAILY"})
+	f.create(t, models.Event{UserID: 3, Event: "Ended", Start: at(1, 10), End: at(1, 11), RRule: "FREQ=DAILY;COUNT=1"})
+	f.reminders.reminders = []models.Reminder{
+		{ID: 1, EventID: planning, UserID: 1, Before: 15 * time.Minute},
+		// Only the organizer's reminders are shown.
+		{ID: 2, EventID: lunch, UserID: 1, Before: 5 * time.Minute},
+	}
+	f.repo.ranges = 0
+
+	result := f.api.Execute(context.Background(), `
+		query Day($ids: [ID!]!) {
+			calendars(userIds: $ids) {
+				userId
+				events(from: "2025-06-02T00:00:00Z", to: "2025-06-03T00:00:00Z") {
+					title
+					attendees { userId email status }
+					reminders { minutesBefore }
+				}
+			}
+		}`, "Day", map[string]any{"ids": []any{"1", "2", "3"}})
+
+	// Invitations are listed in the invitee's calendar too.
+	require.JSONEq(t, `{"calendars": [
+		{"userId": "1", "events": [
+			{"title": "Planning", "reminders": [{"minutesBefore": 15}], "attendees": [
+				{"userId": "3", "email": null, "status": "needs-action"},
+				{"userId": null, "email": "guest@example.com", "status": "needs-action"}]},
+			{"title": "Lunch", "attendees": [{"userId": "1", "email": null, "status": "needs-action"}], "reminders": []},
+			{"title": "Review", "attendees": [], "reminders": []}]},
+		{"userId": "2", "events": [
+			{"title": "Lunch", "attendees": [{"userId": "1", "email": null, "status": "needs-action"}], "reminders": []}]},
+		{"userId": "3", "events": [
+			{"title": "Standup", "attendees": [], "reminders": []},
+			{"title": "Planning", "reminders": [{"minutesBefore": 15}], "attendees": [
+				{"userId": "3", "email": null, "status": "needs-action"},
+				{"userId": null, "email": "guest@example.com", "status": "needs-action"}]}]}
+	]}`, data(t, result))
+	require.Equal(t, 1, f.repo.ranges)
+	require.Equal(t, 1, f.repo.attendees)
+	require.Equal(t, 1, f.reminders.calls)
+
+	// Each window is read once for all calendars asking for it.
+	result = f.api.Execute(context.Background(), `{
+		calendars(userIds: [1, 2]) {
+			today: events(from: "2025-06-02T00:00:00Z", to: "2025-06-03T00:00:00Z") { title }
+			tomorrow: events(from: "2025-06-03T00:00:00Z", to: "2025-06-04T00:00:00Z") { title }
+		}
+	}`, "", nil)
+	data(t, result)
+	require.Equal(t, 3, f.repo.ranges)
+}
+
+func TestAPI_Mutations(t *testing.T) {
+	f := newFixture(t, 5000)
+	ctx := context.Background()
+	input := map[string]any{
+		"userId": "1", "title": "Planning", "date": "2025-06-02",
+		"start": "2025-06-02T09:00:00Z", "end": "2025-06-02T10:00:00Z",
+		"attendees": []any{map[string]any{"email": "guest@example.com"}},
+	}
+	const create = `mutation Create($input: EventInput!, $policy: ConflictPolicy) {
+		createEvent(input: $input, conflictPolicy: $policy) { event { id title attendees { email } } conflicts { title } }
+	}`
+	result := f.api.Execute(ctx, create, "", map[string]any{"input": input})
+	require.Empty(t, result.Errors)
+	event := result.Data.(map[string]any)["createEvent"].(map[string]any)["event"].(map[string]any)
+	id := event["id"].(string)
+	require.Equal(t, []any{map[string]any{"email": "guest@example.com"}}, event["attendees"])
+
+	result = f.api.Execute(ctx, create, "", map[string]any{"input": input, "policy": "REJECT"})
+	require.Equal(t, CodeConflict, code(t, result))
+	result = f.api.Execute(ctx, create, "", map[string]any{"input": input})
+	require.JSONEq(t, `{"createEvent": {"event": {"id": "2", "title": "Planning", "attendees": [{"email": "guest@example.com"}]},
+		"conflicts": [{"title": "Planning"}]}}`, data(t, result))
+
+	// An update without attendees keeps them.
+	const update = `mutation Update($input: EventInput!) { updateEvent(input: $input) { event { title attendees { email } } } }`
+	result = f.api.Execute(ctx, update, "", map[string]any{"input": map[string]any{
+		"id": id, "userId": "1", "title": "Renamed", "date": "2025-06-02",
+	}})
+	require.JSONEq(t, `{"updateEvent": {"event": {"title": "Renamed", "attendees": [{"email": "guest@example.com"}]}}}`, data(t, result))
+	result = f.api.Execute(ctx, update, "", map[string]any{"input": map[string]any{
+		"id": id, "userId": "2", "title": "Hijacked", "date": "2025-06-02",
+	}})
+	require.Equal(t, CodeForbidden, code(t, result))
+	result = f.api.Execute(ctx, update, "", map[string]any{"input": map[string]any{
+		"userId": "1", "title": "Renamed", "date": "2025-06-02",
+	}})
+	require.Equal(t, CodeBadUserInput, code(t, result))
+
+	result = f.api.Execute(ctx, `mutation { deleteEvent(userId: 1, id: `+id+`) }`, "", nil)
+	require.JSONEq(t, `{"deleteEvent": "`+id+`"}`, data(t, result))
+	result = f.api.Execute(ctx, `{ calendar(userId: 1) { events(from: "2025-06-02T00:00:00Z", to: "2025-06-03T00:00:00Z") { title } } }`, "", nil)
+	require.JSONEq(t, `{"calendar": {"events": [{"title": "Planning"}]}}`, data(t, result))
+}
+
+func TestAPI_Errors(t *testing.T) {
+	f := newFixture(t, 100)
+	ctx := context.Background()
+
+	// Errors of batched fields keep their code.
+	result := f.api.Execute(ctx, `{ calendar(userId: 1) { events(from: "2025-06-01T00:00:00Z", to: "2025-12-01T00:00:00Z") { title } } }`, "", nil)
+	require.Equal(t, CodeBadUserInput, code(t, result))
+	require.Equal(t, map[string]any{"calendar": map[string]any{"events": nil}}, result.Data)
+
+	result = f.api.Execute(ctx, `{ calendar(userId: 1) { title } }`, "", nil)
+	require.Nil(t, result.Data)
+	require.NotEmpty(t, result.Errors)
+
+	// Nothing is read for a query over the limit.
+	result = f.api.Execute(ctx, `{
+		calendars(userIds: [1, 2, 3]) {
+			events(from: "2025-06-02T00:00:00Z", to: "2025-06-03T00:00:00Z") { title attendees { email } }
+		}
+	}`, "", nil)
+	require.Nil(t, result.Data)
+	require.Equal(t, CodeTooComplex, code(t, result))
+	require.Zero(t, f.repo.ranges)
+}
+
+func TestComplexity(t *testing.T) {
+	f := newFixture(t, 5000)
+	cases := []struct {
+		query     string
+		variables map[string]any
+		want      int
+	}{
+		{`{ calendar(userId: 1) { userId } }`, nil, 2},
+		{`{ calendar(userId: 1) { events(from: "2025-06-02T00:00:00Z", to: "2025-06-03T00:00:00Z") { title attendees { email } } } }`, nil, 1 + 1 + 10*(1+1+10)},
+		{`{ calendar(userId: 1) { ...day } }
+		  fragment day on Calendar { events(from: "2025-06-02T00:00:00Z", to: "2025-06-03T00:00:00Z") { title } }`, nil, 1 + 1 + 10},
+		{`query($ids: [ID!]!) { calendars(userIds: $ids) { userId __typename } }`, map[string]any{"ids": []any{"1", "2"}}, 1 + 2*2},
+		{`mutation { createEvent(input: {userId: 1, title: "Sync", date: "2025-06-02"}) { event { id } conflicts { id } } }`, nil, 1 + 2 + 11},
+	}
+	for _, tt := range cases {
+		doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+		require.NoError(t, err)
+		require.Equal(t, tt.want, complexity(f.api.schema, doc, "", tt.variables), tt.query)
+	}
+}
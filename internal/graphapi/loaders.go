@@ -0,0 +1,189 @@
+package graphapi
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+// loader collects the keys asked for while a level of the query resolves and fetches them
+// in one batch when the first of their values is needed. graphql-go resolves the thunks
+// of one level before it descends to the next, so every level costs one fetch. The
+// executor runs in a single goroutine, and a loader lives for one request.
+type loader[K comparable, V any] struct {
+	fetch   func(keys []K) (map[K]V, error)
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, queued: make(map[K]bool), values: make(map[K]V), errs: make(map[K]error)}
+}
+
+// load queues the key and returns a thunk of its value. Keys the fetch leaves out get the
+// zero value.
+func (l *loader[K, V]) load(key K) func() (any, error) {
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	return func() (any, error) {
+		if len(l.pending) > 0 {
+			l.dispatch()
+		}
+		return l.values[key], l.errs[key]
+	}
+}
+
+// prime stores a value fetched along with another batch, so the key is not fetched again.
+// Keys already asked for keep their pending fetch.
+func (l *loader[K, V]) prime(key K, value V) {
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.values[key] = value
+	}
+}
+
+func (l *loader[K, V]) dispatch() {
+	keys := l.pending
+	l.pending = nil
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		l.values[key] = values[key]
+	}
+}
+
+// window is the events of a user within [from, to); the times are in UTC so that equal
+// windows are equal keys.
+type window struct {
+	userID   int64
+	from, to time.Time
+}
+
+// reminderKey is the reminders a user set on an event.
+type reminderKey struct {
+	userID, eventID int64
+}
+
+// loaders are the batch loaders of one request.
+type loaders struct {
+	events    *loader[window, []models.Event]
+	attendees *loader[int64, []models.Attendee]
+	reminders *loader[reminderKey, []models.Reminder]
+}
+
+type loadersKey struct{}
+
+func (a *API) newLoaders(ctx context.Context) *loaders {
+	l := &loaders{
+		attendees: newLoader(func(keys []int64) (map[int64][]models.Attendee, error) {
+			attendees, err := a.calendar.GetAttendees(ctx, keys)
+			return attendees, a.toError(err)
+		}),
+	}
+	l.events = newLoader(func(keys []window) (map[window][]models.Event, error) {
+		events, err := a.fetchEvents(ctx, keys, l.attendees)
+		return events, a.toError(err)
+	})
+	if a.reminders != nil {
+		l.reminders = newLoader(func(keys []reminderKey) (map[reminderKey][]models.Reminder, error) {
+			reminders, err := a.fetchReminders(ctx, keys)
+			return reminders, a.toError(err)
+		})
+	}
+	return l
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// fetchEvents reads the events of all users asking for the same window at once. An event
+// is listed for its organizer and for the invitees who have not declined, so the invitees
+// are read with it and handed to the attendees loader.
+func (a *API) fetchEvents(ctx context.Context, keys []window, attendees *loader[int64, []models.Attendee]) (map[window][]models.Event, error) {
+	type span struct{ from, to time.Time }
+	var spans []span
+	users := make(map[span][]int64)
+	for _, key := range keys {
+		s := span{key.from, key.to}
+		if _, ok := users[s]; !ok {
+			spans = append(spans, s)
+		}
+		users[s] = append(users[s], key.userID)
+	}
+	events := make(map[window][]models.Event, len(keys))
+	for _, s := range spans {
+		found, err := a.calendar.GetEventsInRange(ctx, users[s], s.from, s.to)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			continue
+		}
+		ids := make([]int64, len(found))
+		for i, ev := range found {
+			ids[i] = ev.ID
+		}
+		invitees, err := a.calendar.GetAttendees(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		requested := make(map[int64]bool, len(users[s]))
+		for _, id := range users[s] {
+			requested[id] = true
+		}
+		for _, ev := range found {
+			attendees.prime(ev.ID, invitees[ev.ID])
+			for _, id := range viewers(ev, invitees[ev.ID]) {
+				if requested[id] {
+					key := window{id, s.from, s.to}
+					events[key] = append(events[key], ev)
+				}
+			}
+		}
+	}
+	for key, list := range events {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Start.Before(list[j].Start) })
+		events[key] = list
+	}
+	return events, nil
+}
+
+// viewers returns the users who see ev in their calendar: its organizer and the internal
+// invitees who have not declined.
+func viewers(ev models.Event, attendees []models.Attendee) []int64 {
+	users := []int64{ev.UserID}
+	for _, at := range attendees {
+		if at.UserID != 0 && at.UserID != ev.UserID && at.Status != models.AttendeeDeclined {
+			users = append(users, at.UserID)
+		}
+	}
+	return users
+}
+
+// fetchReminders reads the reminders of every user and event pair at once.
+func (a *API) fetchReminders(ctx context.Context, keys []reminderKey) (map[reminderKey][]models.Reminder, error) {
+	userIDs := make([]int64, len(keys))
+	eventIDs := make([]int64, len(keys))
+	for i, key := range keys {
+		userIDs[i], eventIDs[i] = key.userID, key.eventID
+	}
+	found, err := a.reminders.GetRemindersForEvents(ctx, userIDs, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	reminders := make(map[reminderKey][]models.Reminder, len(keys))
+	for _, rem := range found {
+		key := reminderKey{rem.UserID, rem.EventID}
+		reminders[key] = append(reminders[key], rem)
+	}
+	return reminders, nil
+}
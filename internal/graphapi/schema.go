@@ -0,0 +1,347 @@
+package graphapi
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"github.com/graphql-go/graphql"
+	"strconv"
+	"time"
+)
+
+// calendar is the calendar of a user; its events are loaded by window.
+type calendar struct {
+	userID int64
+}
+
+// payload is the result of a create or update.
+type payload struct {
+	event     models.Event
+	conflicts []models.Event
+}
+
+func (a *API) newSchema() (graphql.Schema, error) {
+	attendeeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Attendee",
+		Fields: graphql.Fields{
+			"userId":     attendeeField(graphql.ID, func(at models.Attendee) any { return optionalID(at.UserID) }),
+			"resourceId": attendeeField(graphql.ID, func(at models.Attendee) any { return optionalID(at.ResourceID) }),
+			"email":      attendeeField(graphql.String, func(at models.Attendee) any { return optional(at.Email) }),
+			"status":     attendeeField(graphql.NewNonNull(graphql.String), func(at models.Attendee) any { return at.Status }),
+			"comment":    attendeeField(graphql.String, func(at models.Attendee) any { return optional(at.Comment) }),
+		},
+	})
+	reminderType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Reminder",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(models.Reminder).ID, nil
+			}},
+			"minutesBefore": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return int(p.Source.(models.Reminder).Before / time.Minute), nil
+			}},
+		},
+	})
+
+	eventFields := graphql.Fields{
+		"id":           eventField(graphql.NewNonNull(graphql.ID), func(ev models.Event) any { return ev.ID }),
+		"userId":       eventField(graphql.NewNonNull(graphql.ID), func(ev models.Event) any { return ev.UserID }),
+		"title":        eventField(graphql.NewNonNull(graphql.String), func(ev models.Event) any { return ev.Event }),
+		"date":         eventField(graphql.NewNonNull(graphql.String), func(ev models.Event) any { return ev.Date.Format(time.DateOnly) }),
+		"start":        eventField(graphql.NewNonNull(graphql.DateTime), func(ev models.Event) any { return ev.Start }),
+		"end":          eventField(graphql.NewNonNull(graphql.DateTime), func(ev models.Event) any { return ev.End }),
+		"allDay":       eventField(graphql.NewNonNull(graphql.Boolean), func(ev models.Event) any { return ev.AllDay }),
+		"transparency": eventField(graphql.NewNonNull(graphql.String), func(ev models.Event) any { return ev.Transparency }),
+		"visibility":   eventField(graphql.NewNonNull(graphql.String), func(ev models.Event) any { return ev.Visibility }),
+		"rrule":        eventField(graphql.String, func(ev models.Event) any { return optional(ev.RRule) }),
+		"sequence":     eventField(graphql.NewNonNull(graphql.Int), func(ev models.Event) any { return ev.Sequence }),
+		// The batched fields are nullable: graphql-go resolves their thunks after the parent,
+		// and a failed non-null one would wipe out the whole response.
+		"attendees": &graphql.Field{
+			Type: graphql.NewList(graphql.NewNonNull(attendeeType)),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return loadersFrom(p.Context).attendees.load(p.Source.(models.Event).ID), nil
+			},
+		},
+	}
+	if a.reminders != nil {
+		eventFields["reminders"] = &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(reminderType)),
+			Description: "The reminders the organizer set on the event.",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				ev := p.Source.(models.Event)
+				return loadersFrom(p.Context).reminders.load(reminderKey{ev.UserID, ev.ID}), nil
+			},
+		}
+	}
+	eventType := graphql.NewObject(graphql.ObjectConfig{Name: "Event", Fields: eventFields})
+
+	calendarType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Calendar",
+		Fields: graphql.Fields{
+			"userId": &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(calendar).userID, nil
+			}},
+			"events": &graphql.Field{
+				Type:        graphql.NewList(graphql.NewNonNull(eventType)),
+				Description: "The events the user organizes or has not declined with an occurrence in [from, to); a recurring event is listed once.",
+				Args: graphql.FieldConfigArgument{
+					"from": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.DateTime)},
+					"to":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.DateTime)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					from, _ := p.Args["from"].(time.Time)
+					to, _ := p.Args["to"].(time.Time)
+					key := window{userID: p.Source.(calendar).userID, from: from.UTC(), to: to.UTC()}
+					return loadersFrom(p.Context).events.load(key), nil
+				},
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"calendar": &graphql.Field{
+				Type: calendarType,
+				Args: graphql.FieldConfigArgument{"userId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					userID, err := parseID(p.Args["userId"], "userId")
+					if err != nil {
+						return nil, err
+					}
+					return calendar{userID: userID}, nil
+				},
+			},
+			"calendars": &graphql.Field{
+				Type: graphql.NewList(graphql.NewNonNull(calendarType)),
+				Args: graphql.FieldConfigArgument{
+					"userIds": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					ids, _ := p.Args["userIds"].([]any)
+					calendars := make([]calendar, 0, len(ids))
+					for _, id := range ids {
+						userID, err := parseID(id, "userIds")
+						if err != nil {
+							return nil, err
+						}
+						calendars = append(calendars, calendar{userID: userID})
+					}
+					return calendars, nil
+				},
+			},
+		},
+	})
+
+	attendeeInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AttendeeInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"userId":     &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"resourceId": &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"email":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+	eventInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "EventInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"id":           &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"userId":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.ID)},
+			"title":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"date":         &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String), Description: "YYYY-MM-DD"},
+			"start":        &graphql.InputObjectFieldConfig{Type: graphql.DateTime, Description: "Set with end, or leave both out for an all-day event."},
+			"end":          &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"transparency": &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"visibility":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"rrule":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"attendees": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewList(graphql.NewNonNull(attendeeInput)),
+				Description: "Replaces the invitees when set; an update without it keeps them.",
+			},
+		},
+	})
+	policyType := graphql.NewEnum(graphql.EnumConfig{
+		Name: "ConflictPolicy",
+		Values: graphql.EnumValueConfigMap{
+			"WARN":   &graphql.EnumValueConfig{Value: service.ConflictWarn},
+			"REJECT": &graphql.EnumValueConfig{Value: service.ConflictReject},
+			"FORCE":  &graphql.EnumValueConfig{Value: service.ConflictForce},
+		},
+	})
+	payloadType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "EventPayload",
+		Fields: graphql.Fields{
+			"event": &graphql.Field{Type: graphql.NewNonNull(eventType), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(payload).event, nil
+			}},
+			"conflicts": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(eventType))), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return p.Source.(payload).conflicts, nil
+			}},
+		},
+	})
+	writeArgs := graphql.FieldConfigArgument{
+		"input":          &graphql.ArgumentConfig{Type: graphql.NewNonNull(eventInput)},
+		"conflictPolicy": &graphql.ArgumentConfig{Type: policyType, DefaultValue: service.ConflictWarn},
+	}
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createEvent": &graphql.Field{Type: payloadType, Args: writeArgs, Resolve: a.createEvent},
+			"updateEvent": &graphql.Field{Type: payloadType, Args: writeArgs, Resolve: a.updateEvent},
+			"deleteEvent": &graphql.Field{
+				Type: graphql.ID,
+				Args: graphql.FieldConfigArgument{
+					"userId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: a.deleteEvent,
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func (a *API) createEvent(p graphql.ResolveParams) (any, error) {
+	event, err := toEvent(p.Args["input"], true)
+	if err != nil {
+		return nil, err
+	}
+	event.ID = 0
+	conflicts, err := a.calendar.CreateEventWithPolicy(p.Context, event, policyArg(p))
+	if err != nil {
+		return nil, a.toError(err)
+	}
+	return payload{event: *event, conflicts: conflicts}, nil
+}
+
+func (a *API) updateEvent(p graphql.ResolveParams) (any, error) {
+	event, err := toEvent(p.Args["input"], false)
+	if err != nil {
+		return nil, err
+	}
+	if event.ID == 0 {
+		return nil, badInput("input.id is required")
+	}
+	conflicts, err := a.calendar.UpdateEventWithPolicy(p.Context, event, policyArg(p))
+	if err != nil {
+		return nil, a.toError(err)
+	}
+	return payload{event: *event, conflicts: conflicts}, nil
+}
+
+func (a *API) deleteEvent(p graphql.ResolveParams) (any, error) {
+	userID, err := parseID(p.Args["userId"], "userId")
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, err
+	}
+	if err := a.calendar.DeleteEvent(p.Context, &models.Event{ID: id, UserID: userID}); err != nil {
+		return nil, a.toError(err)
+	}
+	return id, nil
+}
+
+// toEvent converts an EventInput. The attendees of an update are only set when the input
+// has them, so an update without them keeps the stored list.
+func toEvent(arg any, create bool) (*models.Event, error) {
+	input, _ := arg.(map[string]any)
+	userID, err := parseID(input["userId"], "input.userId")
+	if err != nil {
+		return nil, err
+	}
+	event := &models.Event{UserID: userID}
+	if input["id"] != nil {
+		if event.ID, err = parseID(input["id"], "input.id"); err != nil {
+			return nil, err
+		}
+	}
+	event.Event, _ = input["title"].(string)
+	if event.Event == "" {
+		return nil, badInput("input.title is required")
+	}
+	date, _ := input["date"].(string)
+	if event.Date, err = time.Parse(time.DateOnly, date); err != nil {
+		return nil, badInput("input.date must be YYYY-MM-DD")
+	}
+	start, hasStart := input["start"].(time.Time)
+	end, hasEnd := input["end"].(time.Time)
+	if hasStart != hasEnd {
+		return nil, badInput("set both input.start and input.end or neither")
+	}
+	event.Start, event.End = start, end
+	event.Transparency, _ = input["transparency"].(string)
+	event.Visibility, _ = input["visibility"].(string)
+	event.RRule, _ = input["rrule"].(string)
+
+	attendees, ok := input["attendees"].([]any)
+	if !ok && !create {
+		return event, nil
+	}
+	event.Attendees = make([]models.Attendee, 0, len(attendees))
+	for _, raw := range attendees {
+		in, _ := raw.(map[string]any)
+		var at models.Attendee
+		if in["userId"] != nil {
+			if at.UserID, err = parseID(in["userId"], "input.attendees.userId"); err != nil {
+				return nil, err
+			}
+		}
+		if in["resourceId"] != nil {
+			if at.ResourceID, err = parseID(in["resourceId"], "input.attendees.resourceId"); err != nil {
+				return nil, err
+			}
+		}
+		at.Email, _ = in["email"].(string)
+		event.Attendees = append(event.Attendees, at)
+	}
+	return event, nil
+}
+
+// policyArg returns the conflict policy argument, warn when it is null.
+func policyArg(p graphql.ResolveParams) service.ConflictPolicy {
+	if policy, ok := p.Args["conflictPolicy"].(service.ConflictPolicy); ok {
+		return policy
+	}
+	return service.ConflictWarn
+}
+
+// parseID reads a positive id argument, which graphql-go passes as a string.
+func parseID(arg any, name string) (int64, error) {
+	raw, _ := arg.(string)
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, badInput("invalid %s %q", name, raw)
+	}
+	return id, nil
+}
+
+func eventField(typ graphql.Output, get func(models.Event) any) *graphql.Field {
+	return &graphql.Field{Type: typ, Resolve: func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(models.Event)), nil
+	}}
+}
+
+func attendeeField(typ graphql.Output, get func(models.Attendee) any) *graphql.Field {
+	return &graphql.Field{Type: typ, Resolve: func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(models.Attendee)), nil
+	}}
+}
+
+// optionalID returns null for the zero id.
+func optionalID(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// optional returns null for the empty string.
+func optional(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,274 @@
+// Package grpcserver serves the calendar API of pkg/calendarpb over gRPC. It shares the
+// services of the HTTP API, so both apply the same rules.
+package grpcserver
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"awesomeProject/pkg/calendarpb"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// FreeBusyService is CalendarService, or SchedulingService where working hours are known.
+type FreeBusyService interface {
+	FreeBusy(ctx context.Context, userIDs []int64, from, to time.Time) (map[int64][]models.BusyInterval, error)
+}
+
+type Server struct {
+	calendarpb.UnimplementedCalendarServiceServer
+	calendar *service.CalendarService
+	freeBusy FreeBusyService
+	// streams is nil when changes are not streamed, and Watch is unimplemented.
+	streams *service.StreamService
+	log     *zap.Logger
+}
+
+func New(calendar *service.CalendarService, freeBusy FreeBusyService, streams *service.StreamService, log *zap.Logger) *Server {
+	return &Server{calendar: calendar, freeBusy: freeBusy, streams: streams, log: log.Named("GRPCServer")}
+}
+
+// Register creates a gRPC server with the interceptors and registers s on it. An empty
+// token leaves the server unauthenticated.
+//
+// The token is a credential of trusted services, not of a user: requests name the user they
+// act for, and Watch streams any calendar asked for. Do not hand it to end users.
+func (s *Server) Register(token string) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{loggingUnary(s.log)}
+	streams := []grpc.StreamServerInterceptor{loggingStream(s.log)}
+	if token != "" {
+		unary = append(unary, authUnary(token))
+		streams = append(streams, authStream(token))
+	}
+	unary = append(unary, errorsUnary(s.log))
+	streams = append(streams, errorsStream(s.log))
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(streams...))
+	calendarpb.RegisterCalendarServiceServer(srv, s)
+	return srv
+}
+
+func (s *Server) CreateEvent(ctx context.Context, req *calendarpb.CreateEventRequest) (*calendarpb.CreateEventResponse, error) {
+	event, err := toEvent(req.GetEvent(), true)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := toPolicy(req.GetConflictPolicy())
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := s.calendar.CreateEventWithPolicy(ctx, event, policy)
+	if err != nil {
+		return nil, conflictStatus(err, conflicts)
+	}
+	return &calendarpb.CreateEventResponse{Event: fromEvent(*event), Conflicts: fromEvents(conflicts)}, nil
+}
+
+func (s *Server) UpdateEvent(ctx context.Context, req *calendarpb.UpdateEventRequest) (*calendarpb.UpdateEventResponse, error) {
+	event, err := toEvent(req.GetEvent(), req.GetReplaceAttendees())
+	if err != nil {
+		return nil, err
+	}
+	if event.ID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "event.id is required")
+	}
+	policy, err := toPolicy(req.GetConflictPolicy())
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := s.calendar.UpdateEventWithPolicy(ctx, event, policy)
+	if err != nil {
+		return nil, conflictStatus(err, conflicts)
+	}
+	return &calendarpb.UpdateEventResponse{Event: fromEvent(*event), Conflicts: fromEvents(conflicts)}, nil
+}
+
+func (s *Server) DeleteEvent(ctx context.Context, req *calendarpb.DeleteEventRequest) (*calendarpb.DeleteEventResponse, error) {
+	if req.GetUserId() <= 0 || req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id and id are required")
+	}
+	if err := s.calendar.DeleteEvent(ctx, &models.Event{ID: req.GetId(), UserID: req.GetUserId()}); err != nil {
+		return nil, err
+	}
+	return &calendarpb.DeleteEventResponse{}, nil
+}
+
+func (s *Server) ListEvents(ctx context.Context, req *calendarpb.ListEventsRequest) (*calendarpb.ListEventsResponse, error) {
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	date, err := time.Parse(time.DateOnly, req.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "date must be YYYY-MM-DD")
+	}
+	query := &models.Event{UserID: req.GetUserId(), Date: date}
+	var events []models.Event
+	switch req.GetView() {
+	case calendarpb.View_VIEW_DAY:
+		events, err = s.calendar.GetEventsForDay(ctx, query)
+	case calendarpb.View_VIEW_WEEK:
+		events, err = s.calendar.GetEventsForWeek(ctx, query)
+	case calendarpb.View_VIEW_MONTH:
+		events, err = s.calendar.GetEventsForMonth(ctx, query)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "view must be day, week or month")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &calendarpb.ListEventsResponse{Events: fromEvents(events)}, nil
+}
+
+func (s *Server) FreeBusy(ctx context.Context, req *calendarpb.FreeBusyRequest) (*calendarpb.FreeBusyResponse, error) {
+	if req.GetFrom() == nil || req.GetTo() == nil {
+		return nil, status.Error(codes.InvalidArgument, "from and to are required")
+	}
+	busy, err := s.freeBusy.FreeBusy(ctx, req.GetUserIds(), req.GetFrom().AsTime(), req.GetTo().AsTime())
+	if err != nil {
+		return nil, err
+	}
+	resp := &calendarpb.FreeBusyResponse{Busy: make(map[int64]*calendarpb.BusyIntervals, len(busy))}
+	for userID, intervals := range busy {
+		out := &calendarpb.BusyIntervals{}
+		for _, in := range intervals {
+			out.Intervals = append(out.Intervals, &calendarpb.BusyInterval{Start: timestamppb.New(in.Start), End: timestamppb.New(in.End)})
+		}
+		resp.Busy[userID] = out
+	}
+	return resp, nil
+}
+
+// Watch sends the changes of the watched calendars until the client cancels. A client that
+// falls behind gets RESOURCE_EXHAUSTED and reloads.
+func (s *Server) Watch(req *calendarpb.WatchRequest, stream grpc.ServerStreamingServer[calendarpb.Change]) error {
+	if s.streams == nil {
+		return status.Error(codes.Unimplemented, "changes are not streamed with the configured storage")
+	}
+	userIDs := req.GetUserIds()
+	if len(userIDs) == 0 {
+		return status.Error(codes.InvalidArgument, "user_ids are required")
+	}
+	for _, id := range userIDs {
+		if id <= 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid user id %d", id)
+		}
+	}
+	changes := s.streams.Subscribe(userIDs[0], 0)
+	defer changes.Close()
+	changes.Watch(userIDs[1:]...)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case change, ok := <-changes.C:
+			if !ok {
+				if changes.Lagged() {
+					return status.Error(codes.ResourceExhausted, "watcher fell too far behind")
+				}
+				return nil
+			}
+			if err := stream.Send(&calendarpb.Change{Id: change.ID, Type: change.Type, EventId: change.EventID}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// conflictStatus attaches the events a rejected write overlaps to its FAILED_PRECONDITION
+// status, one Event detail each. Other errors are returned as they are.
+func conflictStatus(err error, conflicts []models.Event) error {
+	if !errors.Is(err, models.ErrConflict) || len(conflicts) == 0 {
+		return err
+	}
+	details := make([]protoadapt.MessageV1, 0, len(conflicts))
+	for _, ev := range conflicts {
+		details = append(details, protoadapt.MessageV1Of(fromEvent(ev)))
+	}
+	st, detailErr := status.New(codes.FailedPrecondition, err.Error()).WithDetails(details...)
+	if detailErr != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// toEvent converts a request event. Attendees are only set when replace is true, so an
+// update without them keeps the stored list.
+func toEvent(pb *calendarpb.Event, replace bool) (*models.Event, error) {
+	if pb.GetUserId() <= 0 || pb.GetTitle() == "" {
+		return nil, status.Error(codes.InvalidArgument, "event.user_id and event.title are required")
+	}
+	date, err := time.Parse(time.DateOnly, pb.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "event.date must be YYYY-MM-DD")
+	}
+	event := &models.Event{
+		ID:           pb.GetId(),
+		UserID:       pb.GetUserId(),
+		Date:         date,
+		Event:        pb.GetTitle(),
+		Transparency: pb.GetTransparency(),
+		Visibility:   pb.GetVisibility(),
+		RRule:        pb.GetRrule(),
+	}
+	if (pb.GetStart() == nil) != (pb.GetEnd() == nil) {
+		return nil, status.Error(codes.InvalidArgument, "set both event.start and event.end or neither")
+	}
+	if pb.GetStart() != nil {
+		event.Start, event.End = pb.GetStart().AsTime(), pb.GetEnd().AsTime()
+	}
+	if replace {
+		event.Attendees = make([]models.Attendee, 0, len(pb.GetAttendees()))
+		for _, a := range pb.GetAttendees() {
+			event.Attendees = append(event.Attendees, models.Attendee{UserID: a.GetUserId(), ResourceID: a.GetResourceId(), Email: a.GetEmail()})
+		}
+	}
+	return event, nil
+}
+
+func toPolicy(p calendarpb.ConflictPolicy) (service.ConflictPolicy, error) {
+	switch p {
+	case calendarpb.ConflictPolicy_CONFLICT_POLICY_UNSPECIFIED, calendarpb.ConflictPolicy_CONFLICT_POLICY_WARN:
+		return service.ConflictWarn, nil
+	case calendarpb.ConflictPolicy_CONFLICT_POLICY_REJECT:
+		return service.ConflictReject, nil
+	case calendarpb.ConflictPolicy_CONFLICT_POLICY_FORCE:
+		return service.ConflictForce, nil
+	}
+	return "", status.Errorf(codes.InvalidArgument, "unknown conflict policy %d", p)
+}
+
+func fromEvent(event models.Event) *calendarpb.Event {
+	pb := &calendarpb.Event{
+		Id:           event.ID,
+		UserId:       event.UserID,
+		Date:         event.Date.Format(time.DateOnly),
+		Title:        event.Event,
+		Start:        timestamppb.New(event.Start),
+		End:          timestamppb.New(event.End),
+		AllDay:       event.AllDay,
+		Transparency: event.Transparency,
+		Visibility:   event.Visibility,
+		Rrule:        event.RRule,
+		Sequence:     int32(event.Sequence),
+	}
+	for _, a := range event.Attendees {
+		pb.Attendees = append(pb.Attendees, &calendarpb.Attendee{
+			UserId: a.UserID, ResourceId: a.ResourceID, Email: a.Email, Status: a.Status, Comment: a.Comment,
+		})
+	}
+	return pb
+}
+
+func fromEvents(events []models.Event) []*calendarpb.Event {
+	out := make([]*calendarpb.Event, 0, len(events))
+	for _, ev := range events {
+		out = append(out, fromEvent(ev))
+	}
+	return out
+}
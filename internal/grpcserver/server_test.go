@@ -0,0 +1,143 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/repository/memory"
+	"awesomeProject/internal/service"
+	"awesomeProject/pkg/calendarpb"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestClient(t *testing.T, streams *service.StreamService, token string) calendarpb.CalendarServiceClient {
+	t.Helper()
+	calendar := service.NewCalendarService(memory.NewRepository(zap.NewNop()), zap.NewNop())
+	srv := New(calendar, calendar, streams, zap.NewNop()).Register(token)
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return calendarpb.NewCalendarServiceClient(conn)
+}
+
+func at(hour int) *timestamppb.Timestamp {
+	return timestamppb.New(time.Date(2025, 6, 2, hour, 0, 0, 0, time.UTC))
+}
+
+func TestServer_EventLifecycle(t *testing.T) {
+	client := newTestClient(t, nil, "")
+	ctx := context.Background()
+
+	created, err := client.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		UserId: 1, Date: "2025-06-02", Title: "Planning", Start: at(9), End: at(10),
+	}})
+	require.NoError(t, err)
+	id := created.GetEvent().GetId()
+	require.NotZero(t, id)
+
+	// A second overlapping event is refused under the reject policy.
+	_, err = client.CreateEvent(ctx, &calendarpb.CreateEventRequest{
+		Event:          &calendarpb.Event{UserId: 1, Date: "2025-06-02", Title: "Review", Start: at(9), End: at(11)},
+		ConflictPolicy: calendarpb.ConflictPolicy_CONFLICT_POLICY_REJECT,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	require.Equal(t, id, details[0].(*calendarpb.Event).GetId())
+
+	_, err = client.UpdateEvent(ctx, &calendarpb.UpdateEventRequest{Event: &calendarpb.Event{
+		Id: id, UserId: 1, Date: "2025-06-02", Title: "Planning", Start: at(14), End: at(15),
+	}})
+	require.NoError(t, err)
+	_, err = client.UpdateEvent(ctx, &calendarpb.UpdateEventRequest{Event: &calendarpb.Event{
+		Id: id, UserId: 2, Date: "2025-06-02", Title: "Hijacked", Start: at(14), End: at(15),
+	}})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	week, err := client.ListEvents(ctx, &calendarpb.ListEventsRequest{UserId: 1, View: calendarpb.View_VIEW_WEEK, Date: "2025-06-02"})
+	require.NoError(t, err)
+	require.Len(t, week.GetEvents(), 1)
+	require.Equal(t, 14, week.GetEvents()[0].GetStart().AsTime().Hour())
+
+	busy, err := client.FreeBusy(ctx, &calendarpb.FreeBusyRequest{UserIds: []int64{1}, From: at(0), To: at(23)})
+	require.NoError(t, err)
+	require.Len(t, busy.GetBusy()[1].GetIntervals(), 1)
+
+	_, err = client.DeleteEvent(ctx, &calendarpb.DeleteEventRequest{UserId: 1, Id: id})
+	require.NoError(t, err)
+	_, err = client.DeleteEvent(ctx, &calendarpb.DeleteEventRequest{UserId: 1, Id: id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_ValidatesRequests(t *testing.T) {
+	client := newTestClient(t, nil, "")
+	ctx := context.Background()
+
+	_, err := client.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{UserId: 1, Date: "June 2", Title: "x"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		UserId: 1, Date: "2025-06-02", Title: "x", Start: at(10), End: at(9),
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ListEvents(ctx, &calendarpb.ListEventsRequest{UserId: 1, Date: "2025-06-02"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.FreeBusy(ctx, &calendarpb.FreeBusyRequest{UserIds: []int64{1}, From: at(10), To: at(9)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	watch, err := client.Watch(ctx, &calendarpb.WatchRequest{UserIds: []int64{1}})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	client := newTestClient(t, nil, "secret")
+	req := &calendarpb.ListEventsRequest{UserId: 1, View: calendarpb.View_VIEW_DAY, Date: "2025-06-02"}
+
+	_, err := client.ListEvents(context.Background(), req)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = client.ListEvents(ctx, req)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	_, err = client.ListEvents(ctx, req)
+	require.NoError(t, err)
+}
+
+func TestServer_Watch(t *testing.T) {
+	streams := service.NewStreamService(10, zap.NewNop())
+	client := newTestClient(t, streams, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watch, err := client.Watch(ctx, &calendarpb.WatchRequest{UserIds: []int64{1, 2}})
+	require.NoError(t, err)
+	// The server subscribes after the call returns, so changes are published until one arrives.
+	go func() {
+		for id := int64(1); ctx.Err() == nil; id++ {
+			streams.Publish(models.Change{ID: id, Type: models.NotificationEventCreated, EventID: 7, UserIDs: []int64{2}})
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	change, err := watch.Recv()
+	require.NoError(t, err)
+	require.Equal(t, int64(7), change.GetEventId())
+	require.Equal(t, models.NotificationEventCreated, change.GetType())
+}
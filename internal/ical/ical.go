@@ -0,0 +1,223 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrMalformed = errors.New("malformed icalendar data")
+
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+	MethodReply   = "REPLY"
+
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+
+	PartStatNeedsAction = "NEEDS-ACTION"
+	PartStatAccepted    = "ACCEPTED"
+	PartStatDeclined    = "DECLINED"
+	PartStatTentative   = "TENTATIVE"
+
+	TodoNeedsAction = "NEEDS-ACTION"
+	TodoInProcess   = "IN-PROCESS"
+	TodoCompleted   = "COMPLETED"
+	TodoCancelled   = "CANCELLED"
+
+	prodID       = "-//awesomeProject//Calendar//EN"
+	dateFormat   = "20060102"
+	utcFormat    = "20060102T150405Z"
+	maxLineOctet = 75
+)
+
+// Event is the subset of a VEVENT the calendar produces.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	RRule       string
+	Status      string
+	Sequence    int
+	Stamp       time.Time
+	Organizer   Address
+	Attendees   []Attendee
+}
+
+// Address is a calendar user identified by email.
+type Address struct {
+	Email string
+	Name  string
+}
+
+// Attendee is an ATTENDEE property with its participation status.
+type Attendee struct {
+	Address
+	PartStat string
+	RSVP     bool
+}
+
+// Todo is the subset of a VTODO the calendar stores.
+type Todo struct {
+	UID         string
+	Summary     string
+	Description string
+	// Due and Completed are zero when absent.
+	Due             time.Time
+	Priority        int
+	Status          string
+	PercentComplete int
+	Completed       time.Time
+	Stamp           time.Time
+}
+
+// Calendar is a VCALENDAR object holding events and to-dos.
+type Calendar struct {
+	Method string
+	Events []Event
+	Todos  []Todo
+}
+
+// EventUID returns the stable UID used for the calendar event with the given id.
+func EventUID(id int64) string {
+	return fmt.Sprintf("event-%d@awesomeProject", id)
+}
+
+// ParseEventUID is the inverse of EventUID.
+func ParseEventUID(uid string) (int64, bool) {
+	rest, ok := strings.CutPrefix(uid, "event-")
+	if !ok {
+		return 0, false
+	}
+	digits, ok := strings.CutSuffix(rest, "@awesomeProject")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || id <= 0 || EventUID(id) != uid {
+		return 0, false
+	}
+	return id, true
+}
+
+// Encode renders the calendar as RFC 5545 text with CRLF line endings and folded lines.
+func (c *Calendar) Encode() []byte {
+	var buf bytes.Buffer
+	w := &writer{buf: &buf}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + prodID)
+	w.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD:" + c.Method)
+	}
+	for _, ev := range c.Events {
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + ev.UID)
+		w.line("DTSTAMP:" + ev.Stamp.UTC().Format(utcFormat))
+		if ev.Organizer.Email != "" {
+			w.line("ORGANIZER" + cnParam(ev.Organizer.Name) + ":mailto:" + ev.Organizer.Email)
+		}
+		for _, a := range ev.Attendees {
+			params := cnParam(a.Name)
+			if a.PartStat != "" {
+				params += ";PARTSTAT=" + a.PartStat
+			}
+			if a.RSVP {
+				params += ";RSVP=TRUE"
+			}
+			w.line("ATTENDEE" + params + ":mailto:" + a.Email)
+		}
+		if ev.AllDay {
+			w.line("DTSTART;VALUE=DATE:" + ev.Start.Format(dateFormat))
+			w.line("DTEND;VALUE=DATE:" + ev.End.Format(dateFormat))
+		} else {
+			w.line("DTSTART:" + ev.Start.UTC().Format(utcFormat))
+			w.line("DTEND:" + ev.End.UTC().Format(utcFormat))
+		}
+		if ev.RRule != "" {
+			w.line("RRULE:" + ev.RRule)
+		}
+		w.line("SUMMARY:" + Escape(ev.Summary))
+		if ev.Description != "" {
+			w.line("DESCRIPTION:" + Escape(ev.Description))
+		}
+		if ev.Status != "" {
+			w.line("STATUS:" + ev.Status)
+		}
+		w.line(fmt.Sprintf("SEQUENCE:%d", ev.Sequence))
+		w.line("END:VEVENT")
+	}
+	for _, todo := range c.Todos {
+		w.line("BEGIN:VTODO")
+		w.line("UID:" + todo.UID)
+		w.line("DTSTAMP:" + todo.Stamp.UTC().Format(utcFormat))
+		w.line("SUMMARY:" + Escape(todo.Summary))
+		if todo.Description != "" {
+			w.line("DESCRIPTION:" + Escape(todo.Description))
+		}
+		if !todo.Due.IsZero() {
+			w.line("DUE:" + todo.Due.UTC().Format(utcFormat))
+		}
+		if todo.Priority != 0 {
+			w.line(fmt.Sprintf("PRIORITY:%d", todo.Priority))
+		}
+		if todo.Status != "" {
+			w.line("STATUS:" + todo.Status)
+		}
+		if todo.PercentComplete != 0 {
+			w.line(fmt.Sprintf("PERCENT-COMPLETE:%d", todo.PercentComplete))
+		}
+		if !todo.Completed.IsZero() {
+			w.line("COMPLETED:" + todo.Completed.UTC().Format(utcFormat))
+		}
+		w.line("END:VTODO")
+	}
+	w.line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// Escape escapes a TEXT property value.
+func Escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+func cnParam(name string) string {
+	if name == "" {
+		return ""
+	}
+	if strings.ContainsAny(name, ";:,") {
+		return `;CN="` + strings.ReplaceAll(name, `"`, "'") + `"`
+	}
+	return ";CN=" + name
+}
+
+type writer struct {
+	buf *bytes.Buffer
+}
+
+// line writes a content line folded at 75 octets without splitting UTF-8 sequences.
+func (w *writer) line(s string) {
+	limit := maxLineOctet
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctet - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
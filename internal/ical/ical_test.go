@@ -0,0 +1,106 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeParse_RoundTrip(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	cal := &Calendar{Method: MethodRequest, Events: []Event{{
+		UID:       EventUID(42),
+		Summary:   "Planning, Q3; all hands",
+		Start:     start,
+		End:       start.Add(time.Hour),
+		Sequence:  2,
+		Stamp:     start,
+		Organizer: Address{Email: "org@example.com", Name: "Org: Team"},
+		Attendees: []Attendee{{Address: Address{Email: "guest@example.com", Name: "Guest"}, PartStat: PartStatNeedsAction, RSVP: true}},
+	}}}
+
+	got, err := Parse(cal.Encode())
+	require.NoError(t, err)
+	require.Equal(t, MethodRequest, got.Method)
+	require.Len(t, got.Events, 1)
+	ev := got.Events[0]
+	require.Equal(t, "Planning, Q3; all hands", ev.Summary)
+	require.True(t, ev.Start.Equal(start))
+	require.Equal(t, 2, ev.Sequence)
+	require.Equal(t, Address{Email: "org@example.com", Name: "Org: Team"}, ev.Organizer)
+	require.Equal(t, cal.Events[0].Attendees, ev.Attendees)
+}
+
+func TestParse_ReplyWithFoldingAndAlarm(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"METHOD:REPLY",
+		"BEGIN:VEVENT",
+		"UID:event-7@awe",
+		" someProject",
+		"SEQUENCE:1",
+		"ATTENDEE;PARTSTAT=accepted;CN=\"Doe, Jane\":MAILTO:Jane@Example.com",
+		"BEGIN:VALARM",
+		"UID:ignored",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+
+	cal, err := Parse([]byte(data))
+	require.NoError(t, err)
+	require.Equal(t, MethodReply, cal.Method)
+	require.Len(t, cal.Events, 1)
+	id, ok := ParseEventUID(cal.Events[0].UID)
+	require.True(t, ok)
+	require.Equal(t, int64(7), id)
+	require.Equal(t, []Attendee{{Address: Address{Email: "jane@example.com", Name: "Doe, Jane"}, PartStat: PartStatAccepted}}, cal.Events[0].Attendees)
+}
+
+func TestParse_Malformed(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"BEGIN:VEVENT\nEND:VEVENT",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\n",
+		"BEGIN:VCALENDAR\nnot a property\nEND:VCALENDAR",
+	} {
+		_, err := Parse([]byte(data))
+		require.ErrorIs(t, err, ErrMalformed, data)
+	}
+}
+
+func TestParseEventUID(t *testing.T) {
+	for _, uid := range []string{"event-0@awesomeProject", "event-x@awesomeProject", "event-01@awesomeProject", "other-1@awesomeProject"} {
+		_, ok := ParseEventUID(uid)
+		require.False(t, ok, uid)
+	}
+}
+
+func TestEncodeParse_Todo(t *testing.T) {
+	due := time.Date(2025, 6, 6, 17, 0, 0, 0, time.UTC)
+	cal := &Calendar{Todos: []Todo{{
+		UID:             "task-1@awesomeProject",
+		Summary:         "Send report",
+		Due:             due,
+		Priority:        1,
+		Status:          TodoCompleted,
+		PercentComplete: 100,
+		Completed:       due.Add(-time.Hour),
+		Stamp:           due,
+	}}}
+
+	got, err := Parse(cal.Encode())
+	require.NoError(t, err)
+	require.Empty(t, got.Events)
+	require.Len(t, got.Todos, 1)
+	todo := got.Todos[0]
+	require.Equal(t, "Send report", todo.Summary)
+	require.True(t, todo.Due.Equal(due))
+	require.Equal(t, 1, todo.Priority)
+	require.Equal(t, TodoCompleted, todo.Status)
+	require.Equal(t, 100, todo.PercentComplete)
+	require.True(t, todo.Completed.Equal(due.Add(-time.Hour)))
+}
@@ -0,0 +1,226 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse reads a VCALENDAR object. Only the properties the calendar produces or needs
+// for scheduling replies and task import are kept; nested components such as VALARM are skipped.
+func Parse(data []byte) (*Calendar, error) {
+	lines := unfold(data)
+	cal := &Calendar{}
+	var ev *Event
+	var todo *Todo
+	depth, inCalendar, seenCalendar := 0, false, false
+	for _, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		name, params, value, err := parseLine(raw)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case name == "BEGIN" && !inCalendar:
+			if !strings.EqualFold(value, "VCALENDAR") {
+				return nil, fmt.Errorf("%w: expected VCALENDAR, got %s", ErrMalformed, value)
+			}
+			inCalendar, seenCalendar = true, true
+			continue
+		case !inCalendar:
+			return nil, fmt.Errorf("%w: content outside VCALENDAR", ErrMalformed)
+		case name == "BEGIN":
+			if depth == 0 && strings.EqualFold(value, "VEVENT") {
+				ev = &Event{}
+			}
+			if depth == 0 && strings.EqualFold(value, "VTODO") {
+				todo = &Todo{}
+			}
+			depth++
+			continue
+		case name == "END":
+			if depth == 0 {
+				inCalendar = false
+				continue
+			}
+			depth--
+			if depth == 0 && ev != nil {
+				cal.Events = append(cal.Events, *ev)
+				ev = nil
+			}
+			if depth == 0 && todo != nil {
+				cal.Todos = append(cal.Todos, *todo)
+				todo = nil
+			}
+			continue
+		}
+		if depth == 0 {
+			if name == "METHOD" {
+				cal.Method = strings.ToUpper(value)
+			}
+			continue
+		}
+		if depth == 1 && ev != nil {
+			if err := ev.set(name, params, value); err != nil {
+				return nil, err
+			}
+		}
+		if depth == 1 && todo != nil {
+			if err := todo.set(name, params, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !seenCalendar || inCalendar || depth != 0 {
+		return nil, fmt.Errorf("%w: unterminated VCALENDAR", ErrMalformed)
+	}
+	return cal, nil
+}
+
+func (ev *Event) set(name string, params map[string]string, value string) error {
+	var err error
+	switch name {
+	case "UID":
+		ev.UID = value
+	case "SUMMARY":
+		ev.Summary = unescape(value)
+	case "DESCRIPTION":
+		ev.Description = unescape(value)
+	case "STATUS":
+		ev.Status = strings.ToUpper(value)
+	case "RRULE":
+		ev.RRule = value
+	case "SEQUENCE":
+		if ev.Sequence, err = strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%w: bad SEQUENCE %q", ErrMalformed, value)
+		}
+	case "DTSTAMP":
+		ev.Stamp, _, err = parseTime(value, params)
+	case "DTSTART":
+		ev.Start, ev.AllDay, err = parseTime(value, params)
+	case "DTEND":
+		ev.End, _, err = parseTime(value, params)
+	case "ORGANIZER":
+		ev.Organizer = address(value, params)
+	case "ATTENDEE":
+		ev.Attendees = append(ev.Attendees, Attendee{
+			Address:  address(value, params),
+			PartStat: strings.ToUpper(params["PARTSTAT"]),
+			RSVP:     strings.EqualFold(params["RSVP"], "TRUE"),
+		})
+	}
+	return err
+}
+
+func (todo *Todo) set(name string, params map[string]string, value string) error {
+	var err error
+	switch name {
+	case "UID":
+		todo.UID = value
+	case "SUMMARY":
+		todo.Summary = unescape(value)
+	case "DESCRIPTION":
+		todo.Description = unescape(value)
+	case "STATUS":
+		todo.Status = strings.ToUpper(value)
+	case "DTSTAMP":
+		todo.Stamp, _, err = parseTime(value, params)
+	case "DUE":
+		todo.Due, _, err = parseTime(value, params)
+	case "COMPLETED":
+		todo.Completed, _, err = parseTime(value, params)
+	case "PRIORITY":
+		if todo.Priority, err = strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%w: bad PRIORITY %q", ErrMalformed, value)
+		}
+	case "PERCENT-COMPLETE":
+		if todo.PercentComplete, err = strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%w: bad PERCENT-COMPLETE %q", ErrMalformed, value)
+		}
+	}
+	return err
+}
+
+func address(value string, params map[string]string) Address {
+	email := value
+	if len(email) >= 7 && strings.EqualFold(email[:7], "mailto:") {
+		email = email[7:]
+	}
+	return Address{Email: strings.ToLower(email), Name: params["CN"]}
+}
+
+func parseTime(value string, params map[string]string) (time.Time, bool, error) {
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err := time.Parse(dateFormat, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: bad date %q", ErrMalformed, value)
+		}
+		return t, true, nil
+	}
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" && !strings.HasSuffix(value, "Z") {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", strings.TrimSuffix(value, "Z"), loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: bad date-time %q", ErrMalformed, value)
+	}
+	return t, false, nil
+}
+
+// unfold joins folded content lines and accepts both CRLF and bare LF endings.
+func unfold(data []byte) []string {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	var lines []string
+	for _, l := range strings.Split(string(data), "\n") {
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimSuffix(l, "\r"))
+	}
+	return lines
+}
+
+// parseLine splits "NAME;PARAM=value;...:value", honouring quoted parameter values.
+func parseLine(line string) (string, map[string]string, string, error) {
+	params := map[string]string{}
+	quoted := false
+	start := 0
+	var name string
+	var key string
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '=' && name != "" && key == "":
+			key = strings.ToUpper(line[start:i])
+			start = i + 1
+		case c == ';' || c == ':':
+			part := line[start:i]
+			if name == "" {
+				name = strings.ToUpper(part)
+			} else if key != "" {
+				params[key] = strings.Trim(part, `"`)
+				key = ""
+			}
+			start = i + 1
+			if c == ':' {
+				return name, params, line[i+1:], nil
+			}
+		}
+	}
+	return "", nil, "", fmt.Errorf("%w: line without value: %q", ErrMalformed, line)
+}
+
+func unescape(s string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return r.Replace(s)
+}
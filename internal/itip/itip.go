@@ -0,0 +1,77 @@
+package itip
+
+import (
+	"awesomeProject/internal/ical"
+	"awesomeProject/internal/models"
+	"time"
+)
+
+var partStats = map[string]string{
+	models.AttendeeNeedsAction: ical.PartStatNeedsAction,
+	models.AttendeeAccepted:    ical.PartStatAccepted,
+	models.AttendeeDeclined:    ical.PartStatDeclined,
+	models.AttendeeTentative:   ical.PartStatTentative,
+}
+
+// Message is an iTIP scheduling message about one event for its external attendees.
+type Message struct {
+	Method  string
+	EventID int64
+	To      []string
+	Subject string
+	Text    string
+	// Calendar is the encoded VCALENDAR carrying the method.
+	Calendar []byte
+}
+
+// Build renders the iTIP object for event. emails resolves internal attendees to their
+// address; attendees without one are left out of the ATTENDEE list.
+func Build(method string, event models.Event, organizer ical.Address, emails map[int64]string, now time.Time) *ical.Calendar {
+	status, sequence := ical.StatusConfirmed, event.Sequence
+	if method == ical.MethodCancel {
+		// The cancellation supersedes the last invitation sent.
+		status, sequence = ical.StatusCancelled, event.Sequence+1
+	}
+	var attendees []ical.Attendee
+	for _, a := range event.Attendees {
+		email := a.Email
+		if a.UserID != 0 {
+			email = emails[a.UserID]
+		}
+		if email == "" {
+			continue
+		}
+		attendees = append(attendees, ical.Attendee{
+			Address:  ical.Address{Email: email},
+			PartStat: partStats[a.Status],
+			RSVP:     method == ical.MethodRequest && a.UserID == 0,
+		})
+	}
+	return &ical.Calendar{
+		Method: method,
+		Events: []ical.Event{{
+			UID:       ical.EventUID(event.ID),
+			Summary:   event.Event,
+			Start:     event.Start,
+			End:       event.End,
+			AllDay:    event.AllDay,
+			RRule:     event.RRule,
+			Status:    status,
+			Sequence:  sequence,
+			Stamp:     now,
+			Organizer: organizer,
+			Attendees: attendees,
+		}},
+	}
+}
+
+// externalEmails returns the addresses of attendees outside the system.
+func externalEmails(event models.Event) []string {
+	var emails []string
+	for _, a := range event.Attendees {
+		if a.UserID == 0 && a.Email != "" {
+			emails = append(emails, a.Email)
+		}
+	}
+	return emails
+}
@@ -0,0 +1,114 @@
+package itip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/ical"
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeSender struct {
+	sent []Message
+}
+
+func (s *fakeSender) Send(ctx context.Context, msg Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+type fakeProfiles map[int64]*models.Profile
+
+func (p fakeProfiles) GetProfile(ctx context.Context, userID int64) (*models.Profile, error) {
+	if profile, ok := p[userID]; ok {
+		return profile, nil
+	}
+	return nil, models.ErrNotFound
+}
+
+func invitedEvent() models.Event {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	return models.Event{
+		ID: 9, UserID: 1, Event: "Review", Start: start, End: start.Add(time.Hour), Sequence: 3,
+		Attendees: []models.Attendee{
+			{UserID: 2, Status: models.AttendeeAccepted},
+			{Email: "guest@example.com", Status: models.AttendeeNeedsAction},
+		},
+	}
+}
+
+func TestNotifier_SendsRequestToExternalAttendees(t *testing.T) {
+	sender := &fakeSender{}
+	profiles := fakeProfiles{
+		1: {UserID: 1, Email: "org@example.com", Name: "Org"},
+		2: {UserID: 2, Email: "colleague@example.com"},
+	}
+	n := NewNotifier(sender, profiles, "calendar@example.com", zap.NewNop())
+
+	err := n.Notify(context.Background(), models.Notification{Type: models.NotificationEventCreated, UserID: 1, Event: invitedEvent()})
+	require.NoError(t, err)
+	require.Len(t, sender.sent, 1)
+	msg := sender.sent[0]
+	require.Equal(t, ical.MethodRequest, msg.Method)
+	require.Equal(t, []string{"guest@example.com"}, msg.To)
+
+	cal, err := ical.Parse(msg.Calendar)
+	require.NoError(t, err)
+	require.Equal(t, ical.MethodRequest, cal.Method)
+	ev := cal.Events[0]
+	require.Equal(t, ical.EventUID(9), ev.UID)
+	require.Equal(t, 3, ev.Sequence)
+	require.Equal(t, ical.Address{Email: "org@example.com", Name: "Org"}, ev.Organizer)
+	require.Equal(t, []ical.Attendee{
+		{Address: ical.Address{Email: "colleague@example.com"}, PartStat: ical.PartStatAccepted},
+		{Address: ical.Address{Email: "guest@example.com"}, PartStat: ical.PartStatNeedsAction, RSVP: true},
+	}, ev.Attendees)
+}
+
+func TestNotifier_SendsCancel(t *testing.T) {
+	sender := &fakeSender{}
+	n := NewNotifier(sender, fakeProfiles{}, "calendar@example.com", zap.NewNop())
+
+	err := n.Notify(context.Background(), models.Notification{Type: models.NotificationEventDeleted, UserID: 1, Event: invitedEvent()})
+	require.NoError(t, err)
+	require.Len(t, sender.sent, 1)
+	cal, err := ical.Parse(sender.sent[0].Calendar)
+	require.NoError(t, err)
+	require.Equal(t, ical.MethodCancel, cal.Method)
+	require.Equal(t, ical.StatusCancelled, cal.Events[0].Status)
+	require.Equal(t, 4, cal.Events[0].Sequence)
+	require.Equal(t, "calendar@example.com", cal.Events[0].Organizer.Email)
+}
+
+func TestNotifier_RestoreSupersedesCancel(t *testing.T) {
+	sender := &fakeSender{}
+	n := NewNotifier(sender, fakeProfiles{}, "calendar@example.com", zap.NewNop())
+
+	event := invitedEvent()
+	require.NoError(t, n.Notify(context.Background(), models.Notification{Type: models.NotificationEventDeleted, UserID: 1, Event: event}))
+	// Restoring raises the stored sequence by two.
+	event.Sequence += 2
+	require.NoError(t, n.Notify(context.Background(), models.Notification{Type: models.NotificationEventRestored, UserID: 1, Event: event}))
+	require.Len(t, sender.sent, 2)
+	cancel, err := ical.Parse(sender.sent[0].Calendar)
+	require.NoError(t, err)
+	request, err := ical.Parse(sender.sent[1].Calendar)
+	require.NoError(t, err)
+	require.Equal(t, ical.MethodRequest, request.Method)
+	require.Greater(t, request.Events[0].Sequence, cancel.Events[0].Sequence)
+}
+
+func TestNotifier_SkipsWithoutExternalAttendees(t *testing.T) {
+	sender := &fakeSender{}
+	n := NewNotifier(sender, fakeProfiles{}, "calendar@example.com", zap.NewNop())
+
+	internal := invitedEvent()
+	internal.Attendees = internal.Attendees[:1]
+	require.NoError(t, n.Notify(context.Background(), models.Notification{Type: models.NotificationEventUpdated, UserID: 1, Event: internal}))
+	require.NoError(t, n.Notify(context.Background(), models.Notification{Type: models.NotificationEventResponded, UserID: 1, Event: invitedEvent()}))
+	require.Empty(t, sender.sent)
+}
@@ -0,0 +1,111 @@
+package itip
+
+import (
+	"awesomeProject/internal/ical"
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ProfileStore interface {
+	GetProfile(ctx context.Context, userID int64) (*models.Profile, error)
+}
+
+// Notifier turns event changes into iTIP messages for external attendees: REQUEST when an
+// event is created, updated or restored from the trash and CANCEL when it is deleted. Internal attendees see the
+// event in their own calendar and are not mailed.
+type Notifier struct {
+	sender   Sender
+	profiles ProfileStore
+	// organizer is used as ORGANIZER when the organizer has no email in their profile.
+	organizer string
+	now       func() time.Time
+	log       *zap.Logger
+}
+
+func NewNotifier(sender Sender, profiles ProfileStore, organizer string, log *zap.Logger) *Notifier {
+	return &Notifier{
+		sender:    sender,
+		profiles:  profiles,
+		organizer: organizer,
+		now:       time.Now,
+		log:       log.Named("ITIPNotifier"),
+	}
+}
+
+func (n *Notifier) Notify(ctx context.Context, notification models.Notification) error {
+	var method, subject string
+	event := notification.Event
+	switch notification.Type {
+	case models.NotificationEventCreated:
+		method, subject = ical.MethodRequest, "Invitation: "+event.Event
+	case models.NotificationEventUpdated:
+		method, subject = ical.MethodRequest, "Updated invitation: "+event.Event
+	case models.NotificationEventDeleted:
+		method, subject = ical.MethodCancel, "Cancelled: "+event.Event
+	case models.NotificationEventRestored:
+		method, subject = ical.MethodRequest, "Invitation: "+event.Event
+	default:
+		return nil
+	}
+	to := externalEmails(event)
+	if len(to) == 0 {
+		return nil
+	}
+
+	organizer := ical.Address{Email: n.organizer}
+	profile, err := n.profiles.GetProfile(ctx, event.UserID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return fmt.Errorf("failed to look up organizer: %w", err)
+	}
+	if profile != nil && profile.Email != "" {
+		organizer = ical.Address{Email: profile.Email, Name: profile.Name}
+	}
+	emails, err := n.internalEmails(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	msg := Message{
+		Method:   method,
+		EventID:  event.ID,
+		To:       to,
+		Subject:  subject,
+		Text:     describe(event),
+		Calendar: Build(method, event, organizer, emails, n.now()).Encode(),
+	}
+	if err := n.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send iTIP %s: %w", method, err)
+	}
+	n.log.Debug("iTIP message sent", zap.String("method", method), zap.Int64("event_id", event.ID), zap.Int("recipients", len(to)))
+	return nil
+}
+
+func (n *Notifier) internalEmails(ctx context.Context, event models.Event) (map[int64]string, error) {
+	emails := make(map[int64]string)
+	for _, a := range event.Attendees {
+		if a.UserID == 0 {
+			continue
+		}
+		profile, err := n.profiles.GetProfile(ctx, a.UserID)
+		if errors.Is(err, models.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up attendee: %w", err)
+		}
+		emails[a.UserID] = profile.Email
+	}
+	return emails, nil
+}
+
+func describe(event models.Event) string {
+	when := event.Start.UTC().Format("Monday, 02 January 2006 15:04 MST")
+	if event.AllDay {
+		when = event.Start.Format("Monday, 02 January 2006") + " (all day)"
+	}
+	return event.Event + "\n" + when + "\n"
+}
@@ -0,0 +1,63 @@
+package itip
+
+import (
+	"awesomeProject/internal/email"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Sender delivers iTIP messages to attendees, e.g. by email (iMIP, RFC 6047).
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// EmailSender sends messages over SMTP with the iTIP object as a text/calendar part.
+type EmailSender struct {
+	mailer *email.Mailer
+	now    func() time.Time
+}
+
+func NewEmailSender(mailer *email.Mailer) *EmailSender {
+	return &EmailSender{mailer: mailer, now: time.Now}
+}
+
+func (s *EmailSender) Send(ctx context.Context, msg Message) error {
+	m := &email.Message{
+		From:    s.mailer.From(),
+		To:      msg.To,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		Date:    s.now(),
+		Attachments: []email.Attachment{{
+			Filename:    "invite.ics",
+			ContentType: "text/calendar; charset=utf-8; method=" + msg.Method,
+			Data:        msg.Calendar,
+		}},
+	}
+	body, err := m.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build iMIP email: %w", err)
+	}
+	return s.mailer.Send(ctx, msg.To, body)
+}
+
+// LogSender only logs messages; it stands in when no mail relay is configured.
+type LogSender struct {
+	log *zap.Logger
+}
+
+func NewLogSender(log *zap.Logger) *LogSender {
+	return &LogSender{log: log.Named("ITIPLogSender")}
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	s.log.Info("iTIP message",
+		zap.String("method", msg.Method),
+		zap.Int64("event_id", msg.EventID),
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+	)
+	return nil
+}
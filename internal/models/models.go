@@ -1,15 +1,420 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflicts with existing events")
+	// ErrForbidden is returned when a user changes an event they do not organize.
+	ErrForbidden = errors.New("forbidden")
+	// ErrExpired is returned for a sync token older than the retained change history.
+	ErrExpired = errors.New("expired")
+)
+
+const (
+	TransparencyOpaque      = "opaque"
+	TransparencyTransparent = "transparent"
+
+	VisibilityDefault = "default"
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
 
 type Event struct {
+	ID     int64
 	UserID int64
 	Date   time.Time
 	Event  string
+	// Start and End bound the event; an all-day event spans its whole Date in UTC.
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Transparency string
+	Visibility   string
+	// RRule is an optional RFC 5545 recurrence rule; Start is the first occurrence.
+	RRule string
+	// Sequence counts updates, as in the iTIP SEQUENCE property.
+	Sequence int
+	// Attendees are the invitees; the organizer is UserID. A nil slice on update keeps
+	// the stored list, an empty one clears it.
+	Attendees []Attendee
+}
+
+// TrashedEvent is a deleted event that can still be restored until it is purged.
+type TrashedEvent struct {
+	Event
+	DeletedAt time.Time
+}
+
+const (
+	AttendeeNeedsAction = "needs-action"
+	AttendeeAccepted    = "accepted"
+	AttendeeDeclined    = "declined"
+	AttendeeTentative   = "tentative"
+)
+
+// Attendee is an invitee of an event: an internal user, a bookable resource or, with
+// both ids 0, an external email.
+type Attendee struct {
+	EventID    int64
+	UserID     int64
+	ResourceID int64
+	Email      string
+	Status     string
+	// Comment accompanies the response, e.g. an out-of-office message.
+	Comment string
+}
+
+type AttendeeRequest struct {
+	UserID     int64  `json:"user_id,omitempty"`
+	ResourceID int64  `json:"resource_id,omitempty"`
+	Email      string `json:"email,omitempty"`
+}
+
+const (
+	ResourceRoom      = "room"
+	ResourceProjector = "projector"
+	ResourceCar       = "car"
+)
+
+// Resource is a bookable room or piece of equipment.
+type Resource struct {
+	ID   int64
+	Kind string
+	Name string
+	// Capacity is the number of people a room or car holds; 0 when it does not apply.
+	Capacity   int
+	Attributes map[string]string
+}
+
+type ResourceRequest struct {
+	ID         int64             `json:"id,omitempty"`
+	Kind       string            `json:"kind,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Capacity   int               `json:"capacity,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// ResourceFilter selects resources; zero fields match everything.
+type ResourceFilter struct {
+	Kind        string
+	MinCapacity int
+	Attributes  map[string]string
+}
+
+type RSVPRequest struct {
+	EventID int64  `json:"event_id"`
+	UserID  int64  `json:"user_id"`
+	Status  string `json:"status"`
 }
 
 type EventRequest struct {
+	ID           int64  `json:"id,omitempty"`
+	UserID       int64  `json:"user_id"`
+	Date         string `json:"date"`
+	Event        string `json:"event,omitempty"`
+	StartTime    string `json:"start_time,omitempty"`
+	EndTime      string `json:"end_time,omitempty"`
+	Transparency string `json:"transparency,omitempty"`
+	Visibility   string `json:"visibility,omitempty"`
+	RRule        string `json:"rrule,omitempty"`
+	// ConflictPolicy is one of warn (default), reject or force.
+	ConflictPolicy string `json:"conflict_policy,omitempty"`
+	// Attendees replaces the invitee list when present.
+	Attendees []AttendeeRequest `json:"attendees,omitempty"`
+}
+
+const (
+	TaskNeedsAction = "needs-action"
+	TaskInProcess   = "in-process"
+	TaskCompleted   = "completed"
+	TaskCancelled   = "cancelled"
+)
+
+// Task is a to-do, the counterpart of an iCalendar VTODO.
+type Task struct {
+	ID     int64
+	UserID int64
+	// UID identifies the task in iCalendar exports and imports.
+	UID         string
+	Summary     string
+	Description string
+	// Due and CompletedAt are nil when unset.
+	Due *time.Time
+	// Priority is 1 (highest) to 9 (lowest); 0 means undefined.
+	Priority        int
+	Status          string
+	PercentComplete int
+	CompletedAt     *time.Time
+}
+
+type TaskRequest struct {
+	ID          int64  `json:"id,omitempty"`
+	UserID      int64  `json:"user_id"`
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Due is an RFC 3339 timestamp; empty leaves the task without a due date.
+	Due             string `json:"due,omitempty"`
+	Priority        int    `json:"priority,omitempty"`
+	Status          string `json:"status,omitempty"`
+	PercentComplete int    `json:"percent_complete,omitempty"`
+}
+
+type BusyInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type Reminder struct {
+	ID      int64
+	EventID int64
+	UserID  int64
+	Before  time.Duration
+}
+
+const (
+	ReminderPending = "pending"
+	ReminderFired   = "fired"
+	// ReminderFailed is terminal: the reminder ran out of attempts and is not retried.
+	ReminderFailed = "failed"
+)
+
+// DueReminder is a reminder handed out for firing together with its delivery state.
+type DueReminder struct {
+	Notification Notification
+	// Delivered names the channels that already delivered the reminder.
+	Delivered     []string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+type ReminderRequest struct {
+	ID      int64  `json:"id,omitempty"`
+	UserID  int64  `json:"user_id"`
+	EventID int64  `json:"event_id"`
+	Before  string `json:"before,omitempty"`
+}
+
+const (
+	NotificationReminder     = "reminder"
+	NotificationEventCreated = "event.created"
+	NotificationEventUpdated = "event.updated"
+	NotificationEventDeleted = "event.deleted"
+	// NotificationEventResponded reports an attendee's answer to an invitation.
+	NotificationEventResponded = "event.responded"
+	// NotificationEventRestored reports an event brought back from the trash.
+	NotificationEventRestored = "event.restored"
+)
+
+type Notification struct {
+	Type     string
+	UserID   int64
+	Event    Event
+	Reminder *Reminder
+}
+
+type Webhook struct {
+	ID     int64
+	UserID int64
+	URL    string
+	// Secret is only shown once, in the CreatedWebhook answer to its creation.
+	Secret    string `json:"-"`
+	CreatedAt time.Time
+}
+
+// CreatedWebhook is a new webhook together with its signing secret.
+type CreatedWebhook struct {
+	Webhook
+	Secret string
+}
+
+type WebhookRequest struct {
+	ID     int64  `json:"id,omitempty"`
 	UserID int64  `json:"user_id"`
-	Date   string `json:"date"`
-	Event  string `json:"event,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     int64
+	EventType     string
+	Payload       json.RawMessage
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	ResponseCode  int
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+type Profile struct {
+	UserID   int64
+	Email    string
+	Name     string
+	TimeZone string
+	// NoDoubleBooking forbids overlapping opaque events in the user's calendar.
+	NoDoubleBooking bool
+	// WorkingHours and OutOfOffice replace the stored settings on update unless nil.
+	// Profiles only list out-of-office periods that have not ended yet.
+	WorkingHours []WorkingHours
+	OutOfOffice  []OutOfOffice
+}
+
+type ProfileRequest struct {
+	UserID          int64                 `json:"user_id"`
+	Email           string                `json:"email"`
+	Name            string                `json:"name,omitempty"`
+	TimeZone        string                `json:"time_zone,omitempty"`
+	NoDoubleBooking bool                  `json:"no_double_booking,omitempty"`
+	WorkingHours    []WorkingHoursRequest `json:"working_hours,omitempty"`
+	OutOfOffice     []OutOfOfficeRequest  `json:"out_of_office,omitempty"`
+}
+
+type WorkingHoursRequest struct {
+	// Weekday is an English day name such as "monday".
+	Weekday string `json:"weekday"`
+	// Start and End are local HH:MM times; End may be 24:00.
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type OutOfOfficeRequest struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Message string `json:"message,omitempty"`
+}
+
+// OutOfOffice is a period in which a user declines new invitations.
+type OutOfOffice struct {
+	ID      int64
+	UserID  int64
+	Start   time.Time
+	End     time.Time
+	Message string
+}
+
+// WorkingHours is a working interval on one weekday, in minutes since local midnight.
+type WorkingHours struct {
+	Weekday     time.Weekday
+	StartMinute int
+	EndMinute   int
+}
+
+// Schedule is a user's time zone, weekly working hours and out-of-office periods.
+type Schedule struct {
+	UserID       int64
+	TimeZone     string
+	WorkingHours []WorkingHours
+	OutOfOffice  []OutOfOffice
+}
+
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Score float64   `json:"score"`
+}
+
+// OutboxMessage is a change event recorded in the same transaction as the change itself.
+type OutboxMessage struct {
+	ID        int64           `json:"id"`
+	UserID    int64           `json:"user_id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRespond = "respond"
+	// AuditBook records the answers of invited resources.
+	AuditBook    = "book"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+
+	// AuditSystem is the actor of changes no user made, such as resource answers and purges.
+	AuditSystem = "system"
+)
+
+// AuditEntry records one change of an event. Before and After are snapshots of the event,
+// null when it did not exist or is not kept; Changes maps each changed field to its old and
+// new value.
+type AuditEntry struct {
+	ID      int64 `json:"id"`
+	EventID int64 `json:"event_id"`
+	// Actor is user:ID, mailto:EMAIL for external attendees, or system.
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Changes   json.RawMessage `json:"changes"`
+	RequestID string          `json:"request_id"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type RevertRequest struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// Version is the id of the audit entry whose resulting state is restored.
+	Version int64 `json:"version"`
+}
+
+// SyncCursor is a position in a user's change feed: the transaction of a change and the
+// event it changed. The zero cursor starts a full sync.
+type SyncCursor struct {
+	XID     int64
+	EventID int64
+}
+
+// SyncPage is a page of a change feed: the current state of created and updated events and
+// the ids of events deleted or no longer shared with the user.
+type SyncPage struct {
+	Events  []Event `json:"events"`
+	Deleted []int64 `json:"deleted"`
+	// Token continues the feed after this page; More is set when the next page is ready.
+	Token string     `json:"sync_token"`
+	More  bool       `json:"more"`
+	Next  SyncCursor `json:"-"`
+}
+
+// Change tells the users who see an event that it changed. ID is the id of the outbox
+// message that reported the change.
+type Change struct {
+	ID      int64   `json:"id"`
+	Type    string  `json:"type"`
+	EventID int64   `json:"event_id"`
+	UserIDs []int64 `json:"user_ids,omitempty"`
+}
+
+// SessionCommand is a message from a client of a live session. The answer to a command
+// carries the same ID.
+type SessionCommand struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type"`
+	// Calendars are the users whose changes subscribe and unsubscribe start or stop.
+	Calendars []int64 `json:"calendars,omitempty"`
+	// Event is the event of create_event and update_event.
+	Event *EventRequest `json:"event,omitempty"`
+}
+
+// GraphQLRequest is a query or mutation posted to /graphql.
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
 }
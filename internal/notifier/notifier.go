@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"go.uber.org/zap"
+)
+
+// Notifier delivers a notification to its recipient over some channel.
+type Notifier interface {
+	Notify(ctx context.Context, n models.Notification) error
+}
+
+// LogNotifier is the default Notifier: it only writes notifications to the log.
+type LogNotifier struct {
+	log *zap.Logger
+}
+
+func NewLogNotifier(log *zap.Logger) *LogNotifier {
+	return &LogNotifier{log: log.Named("LogNotifier")}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	fields := []zap.Field{
+		zap.String("type", notification.Type),
+		zap.Int64("user_id", notification.UserID),
+		zap.Int64("event_id", notification.Event.ID),
+		zap.Time("date", notification.Event.Date),
+		zap.String("event", notification.Event.Event),
+	}
+	if notification.Reminder != nil {
+		fields = append(fields, zap.Duration("before", notification.Reminder.Before))
+	}
+	n.log.Info("Notification", fields...)
+	return nil
+}
+
+// MultiNotifier fans a notification out to several notifiers and reports the first error.
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	var firstErr error
+	for _, n := range m {
+		if err := n.Notify(ctx, notification); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,194 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/webhook"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeStore mimics the repository: messages are handed out per sink and user in id order,
+// and a failure stops that user's batch for the sink.
+type fakeStore struct {
+	messages  []models.OutboxMessage
+	delivered map[string]map[int64]bool
+	published map[int64]bool
+	pruned    []time.Time
+}
+
+func newFakeStore(messages ...models.OutboxMessage) *fakeStore {
+	return &fakeStore{messages: messages, delivered: map[string]map[int64]bool{}, published: map[int64]bool{}}
+}
+
+func (f *fakeStore) ProcessOutbox(ctx context.Context, sink string, users int, perUser int, fn func(ctx context.Context, msg models.OutboxMessage) error) (int, error) {
+	if f.delivered[sink] == nil {
+		f.delivered[sink] = map[int64]bool{}
+	}
+	n := 0
+	failed := map[int64]bool{}
+	for _, msg := range f.messages {
+		if f.delivered[sink][msg.ID] || failed[msg.UserID] {
+			continue
+		}
+		if err := fn(ctx, msg); err != nil {
+			failed[msg.UserID] = true
+			continue
+		}
+		f.delivered[sink][msg.ID] = true
+		n++
+	}
+	return n, nil
+}
+
+func (f *fakeStore) MarkOutboxPublished(ctx context.Context, now time.Time, sinks []string) (int, error) {
+	n := 0
+	for _, msg := range f.messages {
+		all := true
+		for _, sink := range sinks {
+			all = all && f.delivered[sink][msg.ID]
+		}
+		if all && !f.published[msg.ID] {
+			f.published[msg.ID] = true
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeStore) PruneOutbox(ctx context.Context, before time.Time, limit int) (int, error) {
+	f.pruned = append(f.pruned, before)
+	return 0, nil
+}
+
+// flakySink fails the first attempt for the listed message ids.
+type flakySink struct {
+	failOnce map[int64]bool
+	got      []int64
+}
+
+func (s *flakySink) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	if s.failOnce[msg.ID] {
+		delete(s.failOnce, msg.ID)
+		return errors.New("sink unavailable")
+	}
+	s.got = append(s.got, msg.ID)
+	return nil
+}
+
+func message(id, userID int64) models.OutboxMessage {
+	payload, _ := json.Marshal(models.Event{ID: id, UserID: userID, Event: "e" + strconv.FormatInt(id, 10)})
+	return models.OutboxMessage{ID: id, UserID: userID, Type: models.NotificationEventCreated, Payload: payload}
+}
+
+func TestRelay_KeepsPerUserOrderAcrossFailures(t *testing.T) {
+	store := newFakeStore(message(1, 1), message(2, 2), message(3, 1), message(4, 2))
+	sink := &flakySink{failOnce: map[int64]bool{1: true}}
+	relay := NewRelay(store, []NamedSink{{Name: "flaky", Sink: sink}}, time.Second, zap.NewNop())
+
+	relay.Tick(context.Background())
+
+	// User 2 is not held back by user 1's failure, and user 1's messages are retried in order.
+	require.Equal(t, []int64{2, 4, 1, 3}, sink.got)
+	require.Len(t, store.published, 4)
+}
+
+func TestRelay_SinksAreIndependent(t *testing.T) {
+	store := newFakeStore(message(1, 1), message(2, 1))
+	healthy := &flakySink{}
+	broken := &flakySink{failOnce: map[int64]bool{1: true}}
+	relay := NewRelay(store, []NamedSink{{Name: "broken", Sink: broken}, {Name: "healthy", Sink: healthy}}, time.Second, zap.NewNop())
+
+	// The broken sink does not hold the healthy one back...
+	relay.Tick(context.Background())
+	require.Equal(t, []int64{1, 2}, healthy.got)
+	require.Empty(t, broken.got)
+	require.Empty(t, store.published)
+
+	// ...and is retried alone once it recovers.
+	relay.Tick(context.Background())
+	require.Equal(t, []int64{1, 2}, healthy.got)
+	require.Equal(t, []int64{1, 2}, broken.got)
+	require.Len(t, store.published, 2)
+}
+
+func TestPruner_Tick_KeepsRetention(t *testing.T) {
+	store := newFakeStore()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	p := NewPruner(store, 7*24*time.Hour, time.Hour, zap.NewNop())
+	p.now = func() time.Time { return now }
+
+	p.Tick(context.Background())
+	require.Equal(t, []time.Time{now.AddDate(0, 0, -7)}, store.pruned)
+}
+
+func TestFileSink_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), message(1, 5)))
+	require.NoError(t, sink.Publish(context.Background(), message(2, 5)))
+	require.NoError(t, sink.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var ids []int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var msg models.OutboxMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		ids = append(ids, msg.ID)
+	}
+	require.Equal(t, []int64{1, 2}, ids)
+}
+
+func TestWebhookSink_SignsMessages(t *testing.T) {
+	var verified bool
+	var status = http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+		verified = webhook.Verify("downstream", ts, body, r.Header.Get(webhook.SignatureHeader))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL, "downstream", time.Second)
+	require.NoError(t, sink.Publish(context.Background(), message(1, 5)))
+	require.True(t, verified)
+
+	status = http.StatusServiceUnavailable
+	require.Error(t, sink.Publish(context.Background(), message(2, 5)))
+}
+
+type recordingNotifier struct {
+	got []models.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	n.got = append(n.got, notification)
+	return nil
+}
+
+func TestNotifierSink_DecodesEvent(t *testing.T) {
+	n := &recordingNotifier{}
+	require.NoError(t, NewNotifierSink(n).Publish(context.Background(), message(9, 3)))
+	require.Len(t, n.got, 1)
+	require.Equal(t, models.NotificationEventCreated, n.got[0].Type)
+	require.Equal(t, int64(3), n.got[0].UserID)
+	require.Equal(t, "e9", n.got[0].Event.Event)
+}
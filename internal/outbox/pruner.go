@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+const defaultPruneBatchSize = 500
+
+type PruneStore interface {
+	PruneOutbox(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// Pruner periodically deletes messages published longer ago than the retention. Messages
+// some sink has not accepted yet are kept however old they are.
+type Pruner struct {
+	store     PruneStore
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+	log       *zap.Logger
+}
+
+func NewPruner(store PruneStore, retention, interval time.Duration, log *zap.Logger) *Pruner {
+	return &Pruner{
+		store:     store,
+		retention: retention,
+		interval:  interval,
+		batchSize: defaultPruneBatchSize,
+		now:       time.Now,
+		log:       log.Named("OutboxPruner"),
+	}
+}
+
+// Run prunes published messages until ctx is cancelled.
+func (p *Pruner) Run(ctx context.Context) {
+	p.log.Info("Starting outbox pruner", zap.Duration("retention", p.retention), zap.Duration("interval", p.interval))
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			p.log.Info("Outbox pruner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick prunes all old published messages, batch by batch.
+func (p *Pruner) Tick(ctx context.Context) {
+	before := p.now().Add(-p.retention)
+	for ctx.Err() == nil {
+		pruned, err := p.store.PruneOutbox(ctx, before, p.batchSize)
+		if err != nil {
+			p.log.Error("Failed to prune outbox", zap.Error(err))
+			return
+		}
+		if pruned > 0 {
+			p.log.Info("Pruned outbox messages", zap.Int("count", pruned))
+		}
+		if pruned < p.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	defaultUsersPerBatch   = 50
+	defaultMessagesPerUser = 100
+)
+
+type Store interface {
+	ProcessOutbox(ctx context.Context, sink string, users int, perUser int, fn func(ctx context.Context, msg models.OutboxMessage) error) (int, error)
+	MarkOutboxPublished(ctx context.Context, now time.Time, sinks []string) (int, error)
+}
+
+// Relay moves outbox messages to its sinks. Every sink keeps its own record of the messages
+// it accepted, which gives each one at-least-once delivery in per-user order: a failing
+// sink is retried alone and does not hold the others back. A message is marked published
+// once all sinks accepted it.
+type Relay struct {
+	store    Store
+	sinks    []NamedSink
+	interval time.Duration
+	users    int
+	perUser  int
+	now      func() time.Time
+	log      *zap.Logger
+}
+
+func NewRelay(store Store, sinks []NamedSink, interval time.Duration, log *zap.Logger) *Relay {
+	return &Relay{
+		store:    store,
+		sinks:    sinks,
+		interval: interval,
+		users:    defaultUsersPerBatch,
+		perUser:  defaultMessagesPerUser,
+		now:      time.Now,
+		log:      log.Named("OutboxRelay"),
+	}
+}
+
+// Run relays messages until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	r.log.Info("Starting outbox relay", zap.Duration("interval", r.interval))
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			r.log.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick publishes pending messages to every sink, then marks the ones all sinks accepted.
+func (r *Relay) Tick(ctx context.Context) {
+	names := make([]string, 0, len(r.sinks))
+	for _, sink := range r.sinks {
+		r.relay(ctx, sink)
+		names = append(names, sink.Name)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if _, err := r.store.MarkOutboxPublished(ctx, r.now(), names); err != nil {
+		r.log.Error("Failed to mark outbox published", zap.Error(err))
+	}
+}
+
+// relay publishes pending messages to one sink until a pass makes no progress.
+func (r *Relay) relay(ctx context.Context, sink NamedSink) {
+	for ctx.Err() == nil {
+		n, err := r.store.ProcessOutbox(ctx, sink.Name, r.users, r.perUser, sink.Sink.Publish)
+		if err != nil {
+			r.log.Error("Failed to relay outbox", zap.String("sink", sink.Name), zap.Error(err))
+			return
+		}
+		if n == 0 {
+			return
+		}
+		r.log.Debug("Relayed outbox messages", zap.String("sink", sink.Name), zap.Int("count", n))
+	}
+}
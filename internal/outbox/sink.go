@@ -0,0 +1,168 @@
+package outbox
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/notifier"
+	"awesomeProject/internal/webhook"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Sink publishes outbox messages downstream. Publish may be called again for a message
+// that was already delivered, so consumers must tolerate duplicates (deduplicate by ID).
+type Sink interface {
+	Publish(ctx context.Context, msg models.OutboxMessage) error
+}
+
+// NamedSink is a sink of a Relay. The name records which messages the sink accepted, so it
+// must stay the same across restarts.
+type NamedSink struct {
+	Name string
+	Sink Sink
+}
+
+// WriterSink writes each message as a JSON line.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func NewStdoutSink() *WriterSink {
+	return NewWriterSink(os.Stdout)
+}
+
+func (s *WriterSink) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox message: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox message: %w", err)
+	}
+	return nil
+}
+
+// FileSink appends JSON lines to a file and syncs after every message.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox file: %w", err)
+	}
+	return &FileSink{file: f}, nil
+}
+
+func (s *FileSink) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox message: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox message: %w", err)
+	}
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// WebhookSink posts each message to a single downstream endpoint, signed like user webhooks.
+type WebhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewWebhookSink(url string, secret string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{url: url, secret: secret, client: &http.Client{Timeout: timeout}, now: time.Now}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox message: %w", err)
+	}
+	timestamp := s.now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.EventHeader, msg.Type)
+	req.Header.Set(webhook.DeliveryHeader, strconv.FormatInt(msg.ID, 10))
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(s.secret, timestamp, body))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish outbox message: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("outbox webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Producer is the minimal interface a message broker client has to offer (Kafka, NATS, RabbitMQ...).
+type Producer interface {
+	Produce(ctx context.Context, topic string, key []byte, value []byte) error
+}
+
+// BrokerSink adapts a Producer. Messages are keyed by user ID so partitioned brokers keep
+// per-user ordering.
+type BrokerSink struct {
+	producer Producer
+	topic    string
+}
+
+func NewBrokerSink(producer Producer, topic string) *BrokerSink {
+	return &BrokerSink{producer: producer, topic: topic}
+}
+
+func (s *BrokerSink) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	value, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox message: %w", err)
+	}
+	return s.producer.Produce(ctx, s.topic, []byte(strconv.FormatInt(msg.UserID, 10)), value)
+}
+
+// NotifierSink turns outbox messages back into notifications, e.g. to queue user webhooks.
+type NotifierSink struct {
+	notifier notifier.Notifier
+}
+
+func NewNotifierSink(n notifier.Notifier) *NotifierSink {
+	return &NotifierSink{notifier: n}
+}
+
+func (s *NotifierSink) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	var event models.Event
+	if err := json.Unmarshal(msg.Payload, &event); err != nil {
+		return fmt.Errorf("failed to decode outbox payload: %w", err)
+	}
+	return s.notifier.Notify(ctx, models.Notification{Type: msg.Type, UserID: msg.UserID, Event: event})
+}
@@ -0,0 +1,95 @@
+// Package realtime carries event changes from the outbox to the event streams of every
+// instance: the Broadcaster sends each change over Postgres NOTIFY and the Listener
+// publishes what it hears to the local StreamService.
+package realtime
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Notifier interface {
+	NotifyChange(ctx context.Context, payload []byte) error
+}
+
+type Hub interface {
+	Publish(change models.Change)
+	Reset()
+}
+
+type ListenRepository interface {
+	ListenChanges(ctx context.Context, listening func(), fn func(payload []byte)) error
+}
+
+// Broadcaster is an outbox sink that announces every event change to all instances. The
+// notification holds ids only, to stay well below the NOTIFY payload limit.
+type Broadcaster struct {
+	notifier Notifier
+}
+
+func NewBroadcaster(notifier Notifier) *Broadcaster {
+	return &Broadcaster{notifier: notifier}
+}
+
+func (b *Broadcaster) Publish(ctx context.Context, msg models.OutboxMessage) error {
+	var event models.Event
+	if err := json.Unmarshal(msg.Payload, &event); err != nil {
+		return fmt.Errorf("failed to decode outbox payload: %w", err)
+	}
+	change := models.Change{ID: msg.ID, Type: msg.Type, EventID: event.ID, UserIDs: []int64{msg.UserID}}
+	for _, a := range event.Attendees {
+		if a.UserID != 0 && a.UserID != msg.UserID {
+			change.UserIDs = append(change.UserIDs, a.UserID)
+		}
+	}
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to encode change: %w", err)
+	}
+	return b.notifier.NotifyChange(ctx, payload)
+}
+
+// Listener publishes the changes announced by every instance to the local hub.
+type Listener struct {
+	repo  ListenRepository
+	hub   Hub
+	retry time.Duration
+	log   *zap.Logger
+}
+
+func NewListener(repo ListenRepository, hub Hub, retry time.Duration, log *zap.Logger) *Listener {
+	return &Listener{repo: repo, hub: hub, retry: retry, log: log.Named("ChangeListener")}
+}
+
+// Run listens until ctx is cancelled, reconnecting after failures. Changes announced while
+// it was not listening are lost, so every new listen resets the hub.
+func (l *Listener) Run(ctx context.Context) {
+	l.log.Info("Starting change listener")
+	for {
+		err := l.repo.ListenChanges(ctx, l.hub.Reset, l.handle)
+		if ctx.Err() != nil {
+			l.log.Info("Change listener stopped")
+			return
+		}
+		l.log.Error("Change listener failed", zap.Error(err), zap.Duration("retry", l.retry))
+		select {
+		case <-ctx.Done():
+			l.log.Info("Change listener stopped")
+			return
+		case <-time.After(l.retry):
+		}
+	}
+}
+
+func (l *Listener) handle(payload []byte) {
+	var change models.Change
+	if err := json.Unmarshal(payload, &change); err != nil {
+		l.log.Error("Failed to decode change", zap.Error(err))
+		return
+	}
+	l.hub.Publish(change)
+}
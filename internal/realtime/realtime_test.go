@@ -0,0 +1,77 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// bus stands in for Postgres: notifications reach the listener while it listens.
+type bus struct {
+	notify chan []byte
+	fails  int
+	listen int
+}
+
+func (b *bus) NotifyChange(ctx context.Context, payload []byte) error {
+	b.notify <- payload
+	return nil
+}
+
+func (b *bus) ListenChanges(ctx context.Context, listening func(), fn func(payload []byte)) error {
+	b.listen++
+	if b.fails > 0 {
+		b.fails--
+		return errors.New("connection refused")
+	}
+	listening()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case payload := <-b.notify:
+			fn(payload)
+		}
+	}
+}
+
+type recordingHub struct {
+	changes chan models.Change
+	resets  int
+}
+
+func (h *recordingHub) Publish(change models.Change) {
+	h.changes <- change
+}
+
+func (h *recordingHub) Reset() {
+	h.resets++
+}
+
+func TestBroadcaster_ReachesListener(t *testing.T) {
+	b := &bus{notify: make(chan []byte, 1), fails: 1}
+	hub := &recordingHub{changes: make(chan models.Change)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewListener(b, hub, time.Millisecond, zap.NewNop()).Run(ctx)
+
+	payload, err := json.Marshal(models.Event{ID: 9, UserID: 1, Attendees: []models.Attendee{
+		{UserID: 2}, {Email: "guest@example.com"}, {ResourceID: 4},
+	}})
+	require.NoError(t, err)
+	msg := models.OutboxMessage{ID: 30, UserID: 1, Type: models.NotificationEventUpdated, Payload: payload}
+	require.NoError(t, NewBroadcaster(b).Publish(ctx, msg))
+
+	got := <-hub.changes
+	require.Equal(t, models.Change{ID: 30, Type: models.NotificationEventUpdated, EventID: 9, UserIDs: []int64{1, 2}}, got)
+	cancel()
+	require.Equal(t, 2, b.listen)
+	require.Equal(t, 1, hub.resets)
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+const (
+	getEventQuery = `SELECT ` + eventColumns + ` FROM calendar WHERE id = $1 AND deleted_at IS NULL`
+	// Attendees missing from the new list are removed; the rest keep their response.
+	deleteRemovedAttendeesQuery = `DELETE FROM event_attendees
+                                WHERE event_id = $1
+                                  AND NOT (COALESCE(user_id = ANY($2::bigint[]), FALSE)
+                                           OR COALESCE(resource_id = ANY($4::bigint[]), FALSE)
+                                           OR (user_id IS NULL AND resource_id IS NULL AND email = ANY($3::text[])))`
+	// New internal invitees may already be answered, e.g. declined while out of office.
+	insertUserAttendeesQuery = `INSERT INTO event_attendees (event_id, user_id, status, comment)
+                                SELECT $1, t.u, t.status, t.comment
+                                FROM unnest($2::bigint[], $3::text[], $4::text[]) AS t(u, status, comment)
+                                ON CONFLICT (event_id, user_id) WHERE user_id IS NOT NULL DO NOTHING`
+	insertEmailAttendeesQuery = `INSERT INTO event_attendees (event_id, email)
+                                SELECT $1, e FROM unnest($2::text[]) e
+                                ON CONFLICT (event_id, email) WHERE user_id IS NULL AND resource_id IS NULL DO NOTHING`
+	insertResourceAttendeesQuery = `INSERT INTO event_attendees (event_id, resource_id)
+                                SELECT $1, r FROM unnest($2::bigint[]) r
+                                ON CONFLICT (event_id, resource_id) WHERE resource_id IS NOT NULL DO NOTHING`
+	getAttendeesQuery = `SELECT event_id, COALESCE(user_id, 0), COALESCE(resource_id, 0), email, status, comment FROM event_attendees
+                                WHERE event_id = ANY($1) ORDER BY id`
+	// Internal invitees are matched by user_id, external ones by email. Resources answer
+	// through BookResources only.
+	respondQuery = `UPDATE event_attendees SET status = $4, responded_at = now()
+                                WHERE event_id = $1
+                                  AND (($2::bigint <> 0 AND user_id = $2)
+                                       OR ($2::bigint = 0 AND user_id IS NULL AND resource_id IS NULL AND email = $3))
+                                  AND EXISTS (SELECT 1 FROM calendar WHERE id = $1 AND deleted_at IS NULL)`
+)
+
+// GetEvent returns a single event with its attendees.
+func (r *Repository) GetEvent(ctx context.Context, id int64) (*models.Event, error) {
+	r.log.Debug("Getting event", zap.Int64("event_id", id))
+	event := &models.Event{}
+	err := scanEvent(r.db.QueryRow(ctx, getEventQuery, id), event)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("event %d: %w", id, models.ErrNotFound)
+	}
+	if err != nil {
+		r.log.Error("Error get event", zap.Error(err))
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	events := []models.Event{*event}
+	if err := loadAttendees(ctx, r.db, events); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+// GetAttendees returns the invitees of the events by event id, in one query. Events without
+// invitees are left out.
+func (r *Repository) GetAttendees(ctx context.Context, eventIDs []int64) (map[int64][]models.Attendee, error) {
+	r.log.Debug("Getting attendees", zap.Int64s("event_ids", eventIDs))
+	events := make([]models.Event, len(eventIDs))
+	for i, id := range eventIDs {
+		events[i].ID = id
+	}
+	if err := loadAttendees(ctx, r.db, events); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return nil, err
+	}
+	return attendeesByEvent(events), nil
+}
+
+// RespondToEvent records an invitee's response and reports the change to the organizer.
+func (r *Repository) RespondToEvent(ctx context.Context, eventID int64, attendee models.Attendee) error {
+	r.log.Debug("Responding to event", zap.Int64("event_id", eventID), zap.Int64("user_id", attendee.UserID), zap.String("status", attendee.Status))
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	// The event as it was before the response, for the audit log.
+	before, err := collectEvents(tx.Query(ctx, getEventQuery, eventID))
+	if err == nil {
+		err = loadAttendees(ctx, tx, before)
+	}
+	if err != nil {
+		r.log.Error("Error get event", zap.Error(err))
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+	if len(before) == 0 {
+		return fmt.Errorf("invitation to event %d: %w", eventID, models.ErrNotFound)
+	}
+	tag, err := tx.Exec(ctx, respondQuery, eventID, attendee.UserID, attendee.Email, attendee.Status)
+	if err != nil {
+		r.log.Error("Error respond to event", zap.Error(err))
+		return fmt.Errorf("failed to respond to event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("invitation to event %d: %w", eventID, models.ErrNotFound)
+	}
+	event := models.Event{}
+	if err := scanEvent(tx.QueryRow(ctx, getEventQuery, eventID), &event); err != nil {
+		r.log.Error("Error get event", zap.Error(err))
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+	events := []models.Event{event}
+	if err := loadAttendees(ctx, tx, events); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return err
+	}
+	if err := writeOutbox(ctx, tx, models.NotificationEventResponded, events[0]); err != nil {
+		r.log.Error("Error write outbox", zap.Error(err))
+		return err
+	}
+	if err := recordChange(ctx, tx, models.AuditRespond, attendeeActor(attendee), eventID, &before[0], &events[0]); err != nil {
+		r.log.Error("Error record change", zap.Error(err))
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	r.replicas.wrote(viewerIDs(&events[0])...)
+	return nil
+}
+
+// replaceAttendees makes the stored invitee list of an event match attendees.
+func replaceAttendees(ctx context.Context, tx pgx.Tx, eventID int64, attendees []models.Attendee) error {
+	userIDs, resourceIDs, emails := []int64{}, []int64{}, []string{}
+	statuses, comments := []string{}, []string{}
+	for _, a := range attendees {
+		switch {
+		case a.UserID != 0:
+			status := a.Status
+			if status == "" {
+				status = models.AttendeeNeedsAction
+			}
+			userIDs = append(userIDs, a.UserID)
+			statuses = append(statuses, status)
+			comments = append(comments, a.Comment)
+		case a.ResourceID != 0:
+			resourceIDs = append(resourceIDs, a.ResourceID)
+		default:
+			emails = append(emails, a.Email)
+		}
+	}
+	if _, err := tx.Exec(ctx, deleteRemovedAttendeesQuery, eventID, userIDs, emails, resourceIDs); err != nil {
+		return fmt.Errorf("failed to save attendees: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertUserAttendeesQuery, eventID, userIDs, statuses, comments); err != nil {
+		return fmt.Errorf("failed to save attendees: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertEmailAttendeesQuery, eventID, emails); err != nil {
+		return fmt.Errorf("failed to save attendees: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertResourceAttendeesQuery, eventID, resourceIDs); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			return fmt.Errorf("resource: %w", models.ErrNotFound)
+		}
+		return fmt.Errorf("failed to save attendees: %w", err)
+	}
+	return nil
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func attendeesByEvent(events []models.Event) map[int64][]models.Attendee {
+	attendees := make(map[int64][]models.Attendee, len(events))
+	for _, ev := range events {
+		if len(ev.Attendees) > 0 {
+			attendees[ev.ID] = ev.Attendees
+		}
+	}
+	return attendees
+}
+
+// loadAttendees fills in the Attendees of events in place.
+func loadAttendees(ctx context.Context, q querier, events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	index := make(map[int64][]int, len(events))
+	ids := make([]int64, 0, len(events))
+	for i, ev := range events {
+		if _, ok := index[ev.ID]; !ok {
+			ids = append(ids, ev.ID)
+		}
+		index[ev.ID] = append(index[ev.ID], i)
+	}
+	rows, err := q.Query(ctx, getAttendeesQuery, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get attendees: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a models.Attendee
+		if err := rows.Scan(&a.EventID, &a.UserID, &a.ResourceID, &a.Email, &a.Status, &a.Comment); err != nil {
+			return fmt.Errorf("failed to get attendees: %w", err)
+		}
+		for _, i := range index[a.EventID] {
+			events[i].Attendees = append(events[i].Attendees, a)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get attendees: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"awesomeProject/internal/audit"
+	"awesomeProject/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	insertAuditQuery = `INSERT INTO audit_log (event_id, actor, action, before, after, changes, request_id)
+                                VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	getHistoryQuery = `SELECT id, event_id, actor, action, before, after, changes, request_id, created_at
+                                FROM audit_log WHERE event_id = $1 ORDER BY id`
+)
+
+// writeAudit records a change of an event inside the transaction that makes the change.
+// before or after is nil when the event does not exist on that side of the change.
+func writeAudit(ctx context.Context, tx pgx.Tx, action, actor string, eventID int64, before, after *models.Event) error {
+	var beforeJSON, afterJSON []byte
+	var err error
+	if before != nil {
+		if beforeJSON, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("failed to encode audit snapshot: %w", err)
+		}
+	}
+	if after != nil {
+		if afterJSON, err = json.Marshal(after); err != nil {
+			return fmt.Errorf("failed to encode audit snapshot: %w", err)
+		}
+	}
+	diff, err := audit.Diff(beforeJSON, afterJSON)
+	if err != nil {
+		return err
+	}
+	changes, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertAuditQuery, eventID, actor, action, beforeJSON, afterJSON, changes, audit.RequestID(ctx)); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+func userActor(userID int64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// attendeeActor names an internal invitee by user and an external one by email.
+func attendeeActor(a models.Attendee) string {
+	if a.UserID != 0 {
+		return userActor(a.UserID)
+	}
+	return "mailto:" + a.Email
+}
+
+// GetHistory returns the audit log of an event, oldest change first.
+func (r *Repository) GetHistory(ctx context.Context, eventID int64) ([]models.AuditEntry, error) {
+	r.log.Debug("Getting history", zap.Int64("event_id", eventID))
+	rows, err := r.db.Query(ctx, getHistoryQuery, eventID)
+	if err != nil {
+		r.log.Error("Error get history", zap.Error(err))
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	defer rows.Close()
+	var entries []models.AuditEntry
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := rows.Scan(&e.ID, &e.EventID, &e.Actor, &e.Action, &e.Before, &e.After, &e.Changes, &e.RequestID, &e.CreatedAt); err != nil {
+			r.log.Error("Error get history", zap.Error(err))
+			return nil, fmt.Errorf("failed to get history: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get history", zap.Error(err))
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"awesomeProject/internal/audit"
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit_RecordsEveryChange(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := audit.WithRequestID(context.Background(), "req-1")
+	ev := timedEvent(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}, {Email: "guest@example.com"}}
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	moved := timedEvent(1, "Planning", date(2025, 6, 2), 14, 15)
+	moved.ID = ev.ID
+	require.NoError(t, repo.UpdateEvent(ctx, moved))
+	require.NoError(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{Email: "guest@example.com", Status: models.AttendeeDeclined}))
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: ev.ID, UserID: 1}))
+	_, err := repo.RestoreEvent(ctx, 1, ev.ID)
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: ev.ID, UserID: 1}))
+	require.NoError(t, repo.PurgeEvent(context.Background(), 1, ev.ID))
+
+	entries, err := repo.GetHistory(ctx, ev.ID)
+	require.NoError(t, err)
+	var actions, actors []string
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+		actors = append(actors, e.Actor)
+	}
+	require.Equal(t, []string{models.AuditCreate, models.AuditUpdate, models.AuditRespond, models.AuditDelete,
+		models.AuditRestore, models.AuditDelete, models.AuditPurge}, actions)
+	require.Equal(t, []string{"user:1", "user:1", "mailto:guest@example.com", "user:1", "user:1", "user:1", "user:1"}, actors)
+	require.Equal(t, "req-1", entries[0].RequestID)
+	require.Empty(t, entries[6].RequestID)
+
+	require.Nil(t, entries[0].Before)
+	var changes map[string]audit.Change
+	require.NoError(t, json.Unmarshal(entries[1].Changes, &changes))
+	require.Contains(t, changes, "Start")
+	require.Contains(t, changes, "Sequence")
+	require.NotContains(t, changes, "Event")
+	require.NoError(t, json.Unmarshal(entries[2].Changes, &changes))
+	require.Equal(t, []string{"Attendees"}, keys(changes))
+	require.Nil(t, entries[3].After)
+	require.Nil(t, entries[6].Before)
+}
+
+func TestAudit_ResourceAnswersAndUpdates(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	room := &models.Resource{Kind: models.ResourceRoom, Name: "Blue", Capacity: 4}
+	require.NoError(t, repo.CreateResource(ctx, room))
+	ev := timedEvent(1, "Sync", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{ResourceID: room.ID}}
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	other := timedEvent(1, "Lunch", date(2025, 6, 3), 12, 13)
+	require.NoError(t, repo.CreateEvent(ctx, other))
+	_, err := repo.BookResources(ctx, ev.ID, ev.Start, ev.End, func(models.Event, []models.Resource, map[int64][]models.Event) map[int64]string {
+		return map[int64]string{room.ID: models.AttendeeAccepted}
+	})
+	require.NoError(t, err)
+
+	// Each update is audited with the state it replaced.
+	for _, id := range []int64{ev.ID, other.ID} {
+		renamed := timedEvent(1, "Renamed", date(2025, 6, 4), 9, 10)
+		renamed.ID = id
+		require.NoError(t, repo.UpdateEvent(ctx, renamed))
+		entries, err := repo.GetHistory(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, models.AuditUpdate, entries[len(entries)-1].Action)
+		require.NotNil(t, entries[len(entries)-1].Before)
+	}
+	entries, err := repo.GetHistory(ctx, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.AuditBook, entries[1].Action)
+	require.Equal(t, models.AuditSystem, entries[1].Actor)
+}
+
+func TestAudit_AppendOnly(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Sync", date(2025, 6, 2), 9, 10)))
+	_, err := testDB.Exec(ctx, `UPDATE audit_log SET actor = 'user:2'`)
+	require.ErrorContains(t, err, "append-only")
+	_, err = testDB.Exec(ctx, `DELETE FROM audit_log`)
+	require.ErrorContains(t, err, "append-only")
+}
+
+func keys(m map[string]audit.Change) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,197 @@
+// Package cache is a read-through CalendarRepository decorator that caches the day, week
+// and month views by user and window. Every write invalidates the views of the users who
+// can see the changed events: the organizer and the invited users.
+package cache
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Store keeps the encoded views. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored under key; ok is false when there is none.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte) error
+	// Generation returns the counter that is part of every key of the user's views.
+	Generation(ctx context.Context, userID int64) (int64, error)
+	// Invalidate bumps the generations of the users, so their cached views are never read again.
+	Invalidate(ctx context.Context, userIDs []int64) error
+}
+
+// Repository caches the views of the wrapped repository; the other methods pass through.
+type Repository struct {
+	service.CalendarRepository
+	store Store
+	log   *zap.Logger
+}
+
+func New(repo service.CalendarRepository, store Store, log *zap.Logger) *Repository {
+	return &Repository{CalendarRepository: repo, store: store, log: log.Named("cache")}
+}
+
+func (r *Repository) CreateEvent(ctx context.Context, event *models.Event) error {
+	if err := r.CalendarRepository.CreateEvent(ctx, event); err != nil {
+		return err
+	}
+	r.invalidate(ctx, viewers(nil, *event))
+	return nil
+}
+
+// UpdateEvent also invalidates the invitees removed by the update, so it looks up the
+// events it is about to change first.
+func (r *Repository) UpdateEvent(ctx context.Context, event *models.Event) error {
+	affected, err := r.stored(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.CalendarRepository.UpdateEvent(ctx, event); err != nil {
+		return err
+	}
+	r.invalidate(ctx, viewers(viewers(nil, *event), affected...))
+	return nil
+}
+
+func (r *Repository) DeleteEvent(ctx context.Context, event *models.Event) error {
+	var affected []models.Event
+	var err error
+	if event.ID != 0 {
+		affected, err = r.stored(ctx, event.ID)
+	} else {
+		affected, err = r.CalendarRepository.GetEventsForDay(ctx, event)
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.CalendarRepository.DeleteEvent(ctx, event); err != nil {
+		return err
+	}
+	users := []int64{event.UserID}
+	for _, ev := range affected {
+		// The day view also lists invitations, which a delete by date leaves alone.
+		if ev.UserID == event.UserID {
+			users = viewers(users, ev)
+		}
+	}
+	r.invalidate(ctx, users)
+	return nil
+}
+
+// RespondToEvent changes an attendee list that every participant sees.
+func (r *Repository) RespondToEvent(ctx context.Context, eventID int64, attendee models.Attendee) error {
+	if err := r.CalendarRepository.RespondToEvent(ctx, eventID, attendee); err != nil {
+		return err
+	}
+	affected, err := r.stored(ctx, eventID)
+	if err != nil {
+		r.log.Error("Error get responded event", zap.Int64("event_id", eventID), zap.Error(err))
+		return nil
+	}
+	r.invalidate(ctx, viewers(nil, affected...))
+	return nil
+}
+
+func (r *Repository) GetEventsForDay(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	return r.view(ctx, "day", event, r.CalendarRepository.GetEventsForDay)
+}
+
+func (r *Repository) GetEventsForWeek(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	return r.view(ctx, "week", event, r.CalendarRepository.GetEventsForWeek)
+}
+
+func (r *Repository) GetEventsForMonth(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	return r.view(ctx, "month", event, r.CalendarRepository.GetEventsForMonth)
+}
+
+// Notify invalidates the views showing the notified event. Registered with the outbox
+// relay it covers changes made past this decorator, such as resource bookings or writes
+// of other instances. Failures are only logged so that they never hold up the relay.
+func (r *Repository) Notify(ctx context.Context, n models.Notification) error {
+	r.invalidate(ctx, viewers(nil, n.Event))
+	return nil
+}
+
+// view serves a view from the store or loads and stores it. The generation is read before
+// loading, so a view loaded concurrently with a write is stored under a stale key.
+// Store failures are logged and fall back to the repository.
+func (r *Repository) view(ctx context.Context, kind string, event *models.Event,
+	load func(ctx context.Context, event *models.Event) ([]models.Event, error)) ([]models.Event, error) {
+	gen, err := r.store.Generation(ctx, event.UserID)
+	if err != nil {
+		r.log.Warn("Cache unavailable", zap.Error(err))
+		return load(ctx, event)
+	}
+	key := fmt.Sprintf("calendar:%d:%d:%s:%s", event.UserID, gen, kind, event.Date.Format(time.DateOnly))
+	data, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		r.log.Warn("Error get cached view", zap.String("key", key), zap.Error(err))
+	}
+	if ok {
+		var events []models.Event
+		if err := json.Unmarshal(data, &events); err == nil {
+			return events, nil
+		}
+		r.log.Warn("Error decode cached view", zap.String("key", key), zap.Error(err))
+	}
+
+	events, err := load(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = json.Marshal(events); err != nil {
+		r.log.Warn("Error encode view", zap.Error(err))
+		return events, nil
+	}
+	if err := r.store.Set(ctx, key, data); err != nil {
+		r.log.Warn("Error cache view", zap.String("key", key), zap.Error(err))
+	}
+	return events, nil
+}
+
+// stored returns the event with the given id, or none when it does not exist.
+func (r *Repository) stored(ctx context.Context, id int64) ([]models.Event, error) {
+	event, err := r.CalendarRepository.GetEvent(ctx, id)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []models.Event{*event}, nil
+}
+
+// invalidate reports failures without failing the write, which has already been applied;
+// the stale views expire with their TTL.
+func (r *Repository) invalidate(ctx context.Context, userIDs []int64) {
+	if err := r.store.Invalidate(ctx, userIDs); err != nil {
+		r.log.Error("Error invalidate cached views", zap.Int64s("user_ids", userIDs), zap.Error(err))
+	}
+}
+
+// viewers appends the organizer and the invited users of the events to users, once each.
+func viewers(users []int64, events ...models.Event) []int64 {
+	add := func(id int64) {
+		if id == 0 {
+			return
+		}
+		for _, u := range users {
+			if u == id {
+				return
+			}
+		}
+		users = append(users, id)
+	}
+	for _, ev := range events {
+		add(ev.UserID)
+		for _, a := range ev.Attendees {
+			add(a.UserID)
+		}
+	}
+	return users
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/repository/memory"
+	"awesomeProject/internal/repository/repotest"
+	"awesomeProject/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRepository_Conformance(t *testing.T) {
+	repotest.RunCalendarRepository(t, func(t *testing.T) service.CalendarRepository {
+		return New(memory.NewRepository(zap.NewNop()), NewLRU(100, time.Minute), zap.NewNop())
+	})
+}
+
+// countingRepo counts the view queries reaching the wrapped repository.
+type countingRepo struct {
+	service.CalendarRepository
+	views int
+}
+
+func (r *countingRepo) GetEventsForDay(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	r.views++
+	return r.CalendarRepository.GetEventsForDay(ctx, event)
+}
+
+func (r *countingRepo) GetEventsForMonth(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	r.views++
+	return r.CalendarRepository.GetEventsForMonth(ctx, event)
+}
+
+func newTestCache(t *testing.T) (*Repository, *countingRepo) {
+	inner := &countingRepo{CalendarRepository: memory.NewRepository(zap.NewNop())}
+	return New(inner, NewLRU(100, time.Minute), zap.NewNop()), inner
+}
+
+func day(d int) time.Time {
+	return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC)
+}
+
+func event(user int64, name string, d int, invitees ...int64) *models.Event {
+	ev := &models.Event{UserID: user, Event: name, Date: day(d), Start: day(d).Add(9 * time.Hour), End: day(d).Add(10 * time.Hour)}
+	for _, id := range invitees {
+		ev.Attendees = append(ev.Attendees, models.Attendee{UserID: id})
+	}
+	return ev
+}
+
+// month returns the names in the user's month view starting June 1.
+func month(t *testing.T, repo service.CalendarRepository, user int64) []string {
+	t.Helper()
+	events, err := repo.GetEventsForMonth(context.Background(), &models.Event{UserID: user, Date: day(1)})
+	require.NoError(t, err)
+	var names []string
+	for _, ev := range events {
+		names = append(names, ev.Event)
+	}
+	return names
+}
+
+func TestRepository_ServesRepeatedViewsFromCache(t *testing.T) {
+	repo, inner := newTestCache(t)
+	require.NoError(t, repo.CreateEvent(context.Background(), event(1, "Sync", 2)))
+
+	require.Equal(t, []string{"Sync"}, month(t, repo, 1))
+	require.Equal(t, []string{"Sync"}, month(t, repo, 1))
+	require.Equal(t, 1, inner.views)
+
+	// Windows and view kinds are cached separately.
+	_, err := repo.GetEventsForDay(context.Background(), &models.Event{UserID: 1, Date: day(1)})
+	require.NoError(t, err)
+	_, err = repo.GetEventsForMonth(context.Background(), &models.Event{UserID: 1, Date: day(2)})
+	require.NoError(t, err)
+	require.Equal(t, 3, inner.views)
+}
+
+func TestRepository_InvalidatesOrganizerAndInvitees(t *testing.T) {
+	repo, inner := newTestCache(t)
+	ctx := context.Background()
+	for _, user := range []int64{1, 2, 3, 4} {
+		require.Empty(t, month(t, repo, user))
+	}
+
+	ev := event(1, "Sync", 2, 2, 3)
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	require.Equal(t, []string{"Sync"}, month(t, repo, 1))
+	require.Equal(t, []string{"Sync"}, month(t, repo, 2))
+	require.Equal(t, []string{"Sync"}, month(t, repo, 3))
+	require.Empty(t, month(t, repo, 4))
+	require.Equal(t, 7, inner.views)
+
+	// Dropping invitee 3 and adding 4 refreshes everybody who saw or now sees the event.
+	ev.Event = "Sync moved"
+	ev.Attendees = []models.Attendee{{UserID: 2}, {UserID: 4}}
+	require.NoError(t, repo.UpdateEvent(ctx, ev))
+	require.Equal(t, []string{"Sync moved"}, month(t, repo, 1))
+	require.Equal(t, []string{"Sync moved"}, month(t, repo, 2))
+	require.Empty(t, month(t, repo, 3))
+	require.Equal(t, []string{"Sync moved"}, month(t, repo, 4))
+	require.Equal(t, 11, inner.views)
+
+	require.NoError(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{UserID: 4, Status: models.AttendeeAccepted}))
+	events, err := repo.GetEventsForMonth(ctx, &models.Event{UserID: 1, Date: day(1)})
+	require.NoError(t, err)
+	require.Equal(t, models.AttendeeAccepted, events[0].Attendees[1].Status)
+	require.Empty(t, month(t, repo, 3))
+	require.Equal(t, 12, inner.views)
+
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: ev.ID, UserID: 1}))
+	for _, user := range []int64{1, 2, 4} {
+		require.Empty(t, month(t, repo, user))
+	}
+}
+
+func TestRepository_BulkChanges(t *testing.T) {
+	repo, _ := newTestCache(t)
+	ctx := context.Background()
+	require.NoError(t, repo.CreateEvent(ctx, event(1, "Sync", 2, 2)))
+	require.NoError(t, repo.CreateEvent(ctx, event(3, "Invitation", 2, 1)))
+	require.Equal(t, []string{"Sync", "Invitation"}, month(t, repo, 1))
+	require.Equal(t, []string{"Sync"}, month(t, repo, 2))
+	require.Equal(t, []string{"Invitation"}, month(t, repo, 3))
+
+	// Deleting by date leaves the invitation of user 3 alone.
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{UserID: 1, Date: day(2)}))
+	require.Equal(t, []string{"Invitation"}, month(t, repo, 1))
+	require.Empty(t, month(t, repo, 2))
+	require.Equal(t, []string{"Invitation"}, month(t, repo, 3))
+}
+
+func TestRepository_NotifyInvalidatesExternalChanges(t *testing.T) {
+	inner := memory.NewRepository(zap.NewNop())
+	repo := New(inner, NewLRU(100, time.Minute), zap.NewNop())
+	ctx := context.Background()
+	require.Empty(t, month(t, repo, 2))
+
+	// A change made past the decorator stays invisible until it is notified.
+	ev := event(1, "Sync", 2, 2)
+	require.NoError(t, inner.CreateEvent(ctx, ev))
+	require.Empty(t, month(t, repo, 2))
+	require.NoError(t, repo.Notify(ctx, models.Notification{Type: models.NotificationEventCreated, UserID: 1, Event: *ev}))
+	require.Equal(t, []string{"Sync"}, month(t, repo, 2))
+}
+
+// failingStore is unavailable.
+type failingStore struct{}
+
+func (failingStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("down")
+}
+
+func (failingStore) Set(ctx context.Context, key string, value []byte) error {
+	return errors.New("down")
+}
+
+func (failingStore) Generation(ctx context.Context, userID int64) (int64, error) {
+	return 0, errors.New("down")
+}
+
+func (failingStore) Invalidate(ctx context.Context, userIDs []int64) error {
+	return errors.New("down")
+}
+
+func TestRepository_StoreFailuresFallBack(t *testing.T) {
+	inner := &countingRepo{CalendarRepository: memory.NewRepository(zap.NewNop())}
+	repo := New(inner, failingStore{}, zap.NewNop())
+	require.NoError(t, repo.CreateEvent(context.Background(), event(1, "Sync", 2)))
+	require.Equal(t, []string{"Sync"}, month(t, repo, 1))
+	require.Equal(t, []string{"Sync"}, month(t, repo, 1))
+	require.Equal(t, 2, inner.views)
+}
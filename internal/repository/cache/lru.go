@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU is an in-process Store holding up to size entries for ttl each. The generations of
+// users are entries like the views, so they are evicted too; a user whose generation is gone
+// gets a new one that no user had before, and the views stored under the old one are never
+// read again.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	order   *list.List // most recently used first
+	entries map[string]*list.Element
+	// lastGeneration is the latest generation handed out to any user.
+	lastGeneration int64
+}
+
+type lruEntry struct {
+	key        string
+	value      []byte
+	generation int64
+	expires    time.Time
+}
+
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.lookup(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (c *LRU) Set(ctx context.Context, key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(&lruEntry{key: key, value: value})
+	return nil
+}
+
+func (c *LRU) Generation(ctx context.Context, userID int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.lookup(generationKey(userID)); ok {
+		return entry.generation, nil
+	}
+	return c.bump(userID), nil
+}
+
+func (c *LRU) Invalidate(ctx context.Context, userIDs []int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range userIDs {
+		c.bump(id)
+	}
+	return nil
+}
+
+// lookup returns the live entry under key and marks it as used; expired entries are dropped.
+func (c *LRU) lookup(key string) (*lruEntry, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry, true
+}
+
+// put stores entry for ttl, replacing the one under its key, and evicts the least recently
+// used entries beyond size.
+func (c *LRU) put(entry *lruEntry) {
+	entry.expires = c.now().Add(c.ttl)
+	if el, ok := c.entries[entry.key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// bump gives the user a generation no user has had before.
+func (c *LRU) bump(userID int64) int64 {
+	c.lastGeneration++
+	c.put(&lruEntry{key: generationKey(userID), generation: c.lastGeneration})
+	return c.lastGeneration
+}
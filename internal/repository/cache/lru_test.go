@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2, time.Minute)
+	require.NoError(t, c.Set(ctx, "a", []byte("1")))
+	require.NoError(t, c.Set(ctx, "b", []byte("2")))
+	_, ok, _ := c.Get(ctx, "a")
+	require.True(t, ok)
+	require.NoError(t, c.Set(ctx, "c", []byte("3")))
+
+	_, ok, _ = c.Get(ctx, "b")
+	require.False(t, ok)
+	value, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), value)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("4")))
+	value, _, _ = c.Get(ctx, "a")
+	require.Equal(t, []byte("4"), value)
+	require.Equal(t, 2, c.order.Len())
+}
+
+func TestLRU_Expires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	c := NewLRU(10, time.Minute)
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.Set(ctx, "a", []byte("1")))
+
+	now = now.Add(59 * time.Second)
+	_, ok, _ := c.Get(ctx, "a")
+	require.True(t, ok)
+	now = now.Add(time.Second)
+	_, ok, _ = c.Get(ctx, "a")
+	require.False(t, ok)
+	require.Zero(t, c.order.Len())
+}
+
+func TestLRU_Generations(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10, time.Minute)
+	first, err := c.Generation(ctx, 1)
+	require.NoError(t, err)
+	gen, _ := c.Generation(ctx, 1)
+	require.Equal(t, first, gen)
+
+	require.NoError(t, c.Invalidate(ctx, []int64{1, 2}))
+	bumped, _ := c.Generation(ctx, 1)
+	require.NotEqual(t, first, bumped)
+	other, _ := c.Generation(ctx, 2)
+	require.NotEqual(t, bumped, other)
+}
+
+func TestLRU_EvictedGenerationIsNew(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2, time.Minute)
+	seen := map[int64]bool{}
+	for i := 0; i < 3; i++ {
+		gen, err := c.Generation(ctx, 1)
+		require.NoError(t, err)
+		require.False(t, seen[gen], "generation %d handed out twice", gen)
+		seen[gen] = true
+		// Two views push the generation out of the list.
+		require.NoError(t, c.Set(ctx, "a", []byte("1")))
+		require.NoError(t, c.Set(ctx, "b", []byte("2")))
+	}
+	require.Equal(t, 2, c.order.Len())
+	require.Len(t, c.entries, 2)
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Redis is a Store speaking the Redis protocol to a single server, so views are shared by
+// every instance. Generations are plain counters without expiry; views expire after ttl.
+type Redis struct {
+	addr    string
+	ttl     time.Duration
+	timeout time.Duration
+
+	// mu serializes commands on the one connection, which is redialled after an error.
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+func NewRedis(addr string, ttl, timeout time.Duration) *Redis {
+	return &Redis{addr: addr, ttl: ttl, timeout: timeout}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return value, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte) error {
+	_, err := c.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(c.ttl.Milliseconds(), 10))
+	return err
+}
+
+func (c *Redis) Generation(ctx context.Context, userID int64) (int64, error) {
+	value, ok, err := c.Get(ctx, generationKey(userID))
+	if err != nil || !ok {
+		return 0, err
+	}
+	gen, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("redis: invalid generation %q", value)
+	}
+	return gen, nil
+}
+
+func (c *Redis) Invalidate(ctx context.Context, userIDs []int64) error {
+	for _, id := range userIDs {
+		if _, err := c.do(ctx, "INCR", generationKey(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Redis) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func generationKey(userID int64) string {
+	return fmt.Sprintf("calendar:gen:%d", userID)
+}
+
+// errorReply is an error returned by the server; the connection stays usable.
+type errorReply string
+
+func (e errorReply) Error() string {
+	return "redis: " + string(e)
+}
+
+// do sends a command and reads its reply: a status string, an int64, []byte or nil.
+func (c *Redis) do(ctx context.Context, args ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		dialer := net.Dialer{Timeout: c.timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+		if err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		c.conn, c.rd = conn, bufio.NewReader(conn)
+	}
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	c.conn.SetDeadline(deadline)
+
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	_, err := c.conn.Write(buf)
+	var reply any
+	if err == nil {
+		reply, err = readReply(c.rd)
+	}
+	var serverErr errorReply
+	if err != nil && !errors.As(err, &serverErr) {
+		c.conn.Close()
+		c.conn = nil
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return reply, err
+}
+
+func readReply(rd *bufio.Reader) (any, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, errorReply(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		value := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, value); err != nil {
+			return nil, err
+		}
+		return value[:n], nil
+	}
+	return nil, fmt.Errorf("malformed reply %q", line)
+}
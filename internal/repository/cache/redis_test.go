@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/repository/memory"
+	"awesomeProject/internal/repository/repotest"
+	"awesomeProject/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRedis is a local stand-in for a Redis server that understands GET, SET ... PX and INCR.
+type fakeRedis struct {
+	ln     net.Listener
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]string
+	conns  []net.Conn
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeRedis{ln: ln, values: map[string]string{}, ttls: map[string]string{}}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// dropConnections closes every client connection, as a server restart would.
+func (s *fakeRedis) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(rd)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		var reply string
+		switch {
+		case args[0] == "GET" && len(args) == 2:
+			if v, ok := s.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case args[0] == "SET" && len(args) == 5 && args[3] == "PX":
+			s.values[args[1]] = args[2]
+			s.ttls[args[1]] = args[4]
+			reply = "+OK\r\n"
+		case args[0] == "INCR" && len(args) == 2:
+			n, _ := strconv.ParseInt(s.values[args[1]], 10, 64)
+			s.values[args[1]] = strconv.FormatInt(n+1, 10)
+			reply = fmt.Sprintf(":%d\r\n", n+1)
+		default:
+			reply = "-ERR unknown command '" + args[0] + "'\r\n"
+		}
+		s.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = rd.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestRedis_GetSetGenerations(t *testing.T) {
+	server := newFakeRedis(t)
+	ctx := context.Background()
+	c := NewRedis(server.ln.Addr().String(), 90*time.Second, time.Second)
+	defer c.Close()
+
+	_, ok, err := c.Get(ctx, "missing")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	value := "line one\r\nline two with $5 and *2"
+	require.NoError(t, c.Set(ctx, "key", []byte(value)))
+	got, ok, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, value, string(got))
+	require.Equal(t, "90000", server.ttls["key"])
+
+	gen, err := c.Generation(ctx, 7)
+	require.NoError(t, err)
+	require.Zero(t, gen)
+	require.NoError(t, c.Invalidate(ctx, []int64{7, 8}))
+	require.NoError(t, c.Invalidate(ctx, []int64{7}))
+	gen, err = c.Generation(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), gen)
+	require.Equal(t, "1", server.values["calendar:gen:8"])
+}
+
+func TestRedis_ErrorsAndReconnect(t *testing.T) {
+	server := newFakeRedis(t)
+	ctx := context.Background()
+	c := NewRedis(server.ln.Addr().String(), time.Minute, time.Second)
+	defer c.Close()
+
+	_, err := c.do(ctx, "PING")
+	require.EqualError(t, err, "redis: ERR unknown command 'PING'")
+	// A server error leaves the connection usable.
+	require.NoError(t, c.Set(ctx, "key", []byte("1")))
+
+	server.dropConnections()
+	_, _, err = c.Get(ctx, "key")
+	require.Error(t, err)
+	got, ok, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "1", string(got))
+
+	server.ln.Close()
+	server.dropConnections()
+	_, _, err = c.Get(ctx, "key")
+	require.Error(t, err)
+	_, err = c.Generation(ctx, 1)
+	require.Error(t, err)
+}
+
+func TestRedis_Conformance(t *testing.T) {
+	repotest.RunCalendarRepository(t, func(t *testing.T) service.CalendarRepository {
+		store := NewRedis(newFakeRedis(t).ln.Addr().String(), time.Minute, time.Second)
+		t.Cleanup(func() { store.Close() })
+		return New(memory.NewRepository(zap.NewNop()), store, zap.NewNop())
+	})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+)
+
+// changesChannel is the LISTEN/NOTIFY channel that fans changes out to every instance.
+const changesChannel = "calendar_changes"
+
+const notifyChangeQuery = `SELECT pg_notify('` + changesChannel + `', $1)`
+
+// NotifyChange sends the encoded change to every instance listening for changes.
+func (r *Repository) NotifyChange(ctx context.Context, payload []byte) error {
+	if _, err := r.db.Exec(ctx, notifyChangeQuery, string(payload)); err != nil {
+		r.log.Error("Error notify change", zap.Error(err))
+		return fmt.Errorf("failed to notify change: %w", err)
+	}
+	return nil
+}
+
+// ListenChanges holds a connection listening for changes and passes each one to fn until
+// ctx is cancelled or the connection fails. listening is called once the listen is active.
+func (r *Repository) ListenChanges(ctx context.Context, listening func(), fn func(payload []byte)) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		r.log.Error("Error acquire connection", zap.Error(err))
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// The connection keeps listening, so it never goes back to the pool.
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+changesChannel); err != nil {
+		r.log.Error("Error listen for changes", zap.Error(err))
+		return fmt.Errorf("failed to listen for changes: %w", err)
+	}
+	listening()
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for changes: %w", err)
+		}
+		fn([]byte(n.Payload))
+	}
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testDB is the migrated integration database, or nil when no Postgres is available.
+var testDB *pgxpool.Pool
+
+// TestMain runs the integration tests against TEST_DATABASE_URL or, failing that, a
+// throwaway cluster started with initdb/pg_ctl from PATH or /usr/lib/postgresql. Tests
+// skip when neither is available, except in CI (CI set), where the SQL must be exercised.
+func TestMain(m *testing.M) {
+	connStr, stop, err := startPostgres()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to start postgres:", err)
+		os.Exit(1)
+	}
+	if connStr == "" && os.Getenv("CI") != "" {
+		fmt.Fprintln(os.Stderr, "no postgres in CI: set TEST_DATABASE_URL")
+		os.Exit(1)
+	}
+	if connStr != "" {
+		if err := runMigrations(connStr); err != nil {
+			stop()
+			fmt.Fprintln(os.Stderr, "failed to migrate:", err)
+			os.Exit(1)
+		}
+		if testDB, err = pgxpool.New(context.Background(), connStr); err != nil {
+			stop()
+			fmt.Fprintln(os.Stderr, "failed to connect:", err)
+			os.Exit(1)
+		}
+	}
+	code := m.Run()
+	if testDB != nil {
+		testDB.Close()
+	}
+	stop()
+	os.Exit(code)
+}
+
+// newTestRepo empties every table and returns a repository on the integration database.
+func newTestRepo(t *testing.T) *Repository {
+	t.Helper()
+	if testDB == nil {
+		t.Skip("no postgres: set TEST_DATABASE_URL or put initdb and pg_ctl on PATH")
+	}
+	_, err := testDB.Exec(context.Background(), `TRUNCATE calendar, event_attendees, outbox, reminders, webhooks,
+		webhook_deliveries, user_profiles, working_hours, out_of_office, resources, tasks, audit_log, sync_changes RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+	_, err = testDB.Exec(context.Background(), `UPDATE sync_watermark SET pruned_xid = 0`)
+	require.NoError(t, err)
+	return &Repository{db: testDB, log: zap.NewNop()}
+}
+
+// startPostgres returns the connection string of the test database and a function that
+// tears it down. An empty string means no Postgres is available.
+func startPostgres() (string, func(), error) {
+	noop := func() {}
+	if connStr := os.Getenv("TEST_DATABASE_URL"); connStr != "" {
+		return connStr, noop, nil
+	}
+	initdb, pgCtl := findPostgresBinary("initdb"), findPostgresBinary("pg_ctl")
+	// initdb refuses to run as root.
+	if initdb == "" || pgCtl == "" || os.Geteuid() == 0 {
+		return "", noop, nil
+	}
+	dir, err := os.MkdirTemp("", "calendar-pg-")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	data := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput(); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("initdb: %v: %s", err, out)
+	}
+	port, err := freePort()
+	if err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	opts := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -F", port, dir)
+	if out, err := exec.Command(pgCtl, "-D", data, "-o", opts, "-l", filepath.Join(dir, "postgres.log"), "-w", "-t", "60", "start").CombinedOutput(); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("pg_ctl start: %v: %s", err, out)
+	}
+	stop := func() {
+		exec.Command(pgCtl, "-D", data, "-m", "immediate", "-w", "stop").Run()
+		cleanup()
+	}
+	return fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", port), stop, nil
+}
+
+func findPostgresBinary(name string) string {
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	// Debian and Ubuntu keep the server binaries out of PATH.
+	matches, _ := filepath.Glob(filepath.Join("/usr/lib/postgresql", "*", "bin", name))
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1]
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
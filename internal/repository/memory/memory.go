@@ -0,0 +1,342 @@
+// Package memory is an in-process CalendarRepository for local runs and tests. It mirrors
+// the semantics of the Postgres repository, minus the features backed by other tables:
+// there are no resources, profiles or outbox.
+package memory
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Repository struct {
+	mu     sync.RWMutex
+	nextID int64
+	events map[int64]*models.Event
+	// byDate holds the event ids ordered by date, then id, for the day/week/month views.
+	byDate []int64
+	log    *zap.Logger
+}
+
+func NewRepository(log *zap.Logger) *Repository {
+	return &Repository{events: make(map[int64]*models.Event), log: log.Named("memory")}
+}
+
+func (r *Repository) CreateEvent(ctx context.Context, event *models.Event) error {
+	r.log.Debug("Creating Event", zap.Any("event", event))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attendees, err := mergeAttendees(nil, event.Attendees)
+	if err != nil {
+		return err
+	}
+	r.nextID++
+	event.ID = r.nextID
+	stored := *event
+	stored.Date = day(event.Date)
+	stored.Sequence = 0
+	stored.Attendees = attendees
+	r.events[stored.ID] = &stored
+	r.insertIndex(stored.ID)
+	return nil
+}
+
+// UpdateEvent changes the user's event with the given id. Like the SQL update it is not an
+// error when nothing matches.
+func (r *Repository) UpdateEvent(ctx context.Context, event *models.Event) error {
+	r.log.Debug("Updating Event", zap.Any("event", event))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matched []*models.Event
+	for _, id := range r.byDate {
+		ev := r.events[id]
+		if ev.UserID == event.UserID && ev.ID == event.ID {
+			matched = append(matched, ev)
+		}
+	}
+	if event.Attendees != nil && len(matched) > 0 {
+		attendees, err := mergeAttendees(matched[0].Attendees, event.Attendees)
+		if err != nil {
+			return err
+		}
+		matched[0].Attendees = attendees
+	}
+	for _, ev := range matched {
+		r.removeIndex(ev.ID)
+		ev.Date = day(event.Date)
+		ev.Event = event.Event
+		ev.Start = event.Start
+		ev.End = event.End
+		ev.AllDay = event.AllDay
+		ev.Transparency = event.Transparency
+		ev.Visibility = event.Visibility
+		ev.RRule = event.RRule
+		ev.Sequence++
+		r.insertIndex(ev.ID)
+	}
+	return nil
+}
+
+// DeleteEvent removes the event with the given id or, without an id, every event of the
+// user on the date.
+func (r *Repository) DeleteEvent(ctx context.Context, event *models.Event) error {
+	r.log.Debug("Deleting Event", zap.Any("event", event))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	date := day(event.Date)
+	for _, id := range append([]int64(nil), r.byDate...) {
+		ev := r.events[id]
+		if ev.UserID != event.UserID {
+			continue
+		}
+		if (event.ID == 0 && ev.Date.Equal(date)) || ev.ID == event.ID {
+			r.removeIndex(id)
+			delete(r.events, id)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) GetEventsForDay(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	r.log.Debug("Getting Events for Day", zap.Any("event", event))
+	from := day(event.Date)
+	return r.view(event.UserID, from, from.AddDate(0, 0, 1)), nil
+}
+
+func (r *Repository) GetEventsForWeek(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	r.log.Debug("Getting Events for Week", zap.Any("event", event))
+	from := day(event.Date)
+	return r.view(event.UserID, from, from.AddDate(0, 0, 7)), nil
+}
+
+// GetEventsForMonth lists the month up to, not including, the same day a month later, as the
+// SQL query does.
+func (r *Repository) GetEventsForMonth(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	r.log.Debug("Getting Events for Month", zap.Any("event", event))
+	from := day(event.Date)
+	return r.view(event.UserID, from, addMonth(from)), nil
+}
+
+// GetEventsInRange returns the events of the users overlapping [from, to), plus recurring
+// series starting before to, ordered by organizer and start. Invitations count unless declined.
+func (r *Repository) GetEventsInRange(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Event, error) {
+	r.log.Debug("Getting events in range", zap.Int64s("user_ids", userIDs), zap.Time("from", from), zap.Time("to", to))
+	users := make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		users[id] = true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var events []models.Event
+	for _, ev := range r.events {
+		if !(users[ev.UserID] || attending(ev, users)) || !ev.Start.Before(to) {
+			continue
+		}
+		if ev.End.After(from) && ev.End.After(ev.Start) || ev.RRule != "" {
+			events = append(events, copyEvent(*ev))
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].UserID != events[j].UserID {
+			return events[i].UserID < events[j].UserID
+		}
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// attending reports whether one of the users is invited to ev and has not declined.
+func attending(ev *models.Event, users map[int64]bool) bool {
+	for _, a := range ev.Attendees {
+		if a.UserID != 0 && users[a.UserID] && a.Status != models.AttendeeDeclined {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEvent returns a single event with its attendees.
+func (r *Repository) GetEvent(ctx context.Context, id int64) (*models.Event, error) {
+	r.log.Debug("Getting event", zap.Int64("event_id", id))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ev, ok := r.events[id]
+	if !ok {
+		return nil, fmt.Errorf("event %d: %w", id, models.ErrNotFound)
+	}
+	event := copyEvent(*ev)
+	return &event, nil
+}
+
+// GetAttendees returns the invitees of the events by event id. Events without invitees are
+// left out.
+func (r *Repository) GetAttendees(ctx context.Context, eventIDs []int64) (map[int64][]models.Attendee, error) {
+	r.log.Debug("Getting attendees", zap.Int64s("event_ids", eventIDs))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	attendees := make(map[int64][]models.Attendee, len(eventIDs))
+	for _, id := range eventIDs {
+		if ev, ok := r.events[id]; ok && len(ev.Attendees) > 0 {
+			attendees[id] = copyEvent(*ev).Attendees
+		}
+	}
+	return attendees, nil
+}
+
+// GetOutOfOffice returns no periods: profiles are not kept in memory.
+func (r *Repository) GetOutOfOffice(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.OutOfOffice, error) {
+	return nil, nil
+}
+
+// GetTimeZones returns no time zones: profiles are not kept in memory.
+func (r *Repository) GetTimeZones(ctx context.Context, userIDs []int64) (map[int64]string, error) {
+	return nil, nil
+}
+
+// RespondToEvent records an invitee's response. Internal invitees are matched by user id,
+// external ones by email.
+func (r *Repository) RespondToEvent(ctx context.Context, eventID int64, attendee models.Attendee) error {
+	r.log.Debug("Responding to event", zap.Int64("event_id", eventID), zap.Int64("user_id", attendee.UserID), zap.String("status", attendee.Status))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ev, ok := r.events[eventID]; ok {
+		for i := range ev.Attendees {
+			a := &ev.Attendees[i]
+			if (attendee.UserID != 0 && a.UserID == attendee.UserID) ||
+				(attendee.UserID == 0 && a.UserID == 0 && a.ResourceID == 0 && a.Email == attendee.Email) {
+				a.Status = attendee.Status
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("invitation to event %d: %w", eventID, models.ErrNotFound)
+}
+
+func (r *Repository) Close() {
+	r.log.Info("Closing repository")
+}
+
+// view returns the user's own events and the events they are invited to dated within [from, to).
+func (r *Repository) view(userID int64, from, to time.Time) []models.Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	start := sort.Search(len(r.byDate), func(i int) bool { return !r.events[r.byDate[i]].Date.Before(from) })
+	var events []models.Event
+	for _, id := range r.byDate[start:] {
+		ev := r.events[id]
+		if !ev.Date.Before(to) {
+			break
+		}
+		if ev.UserID == userID || invited(ev, userID) {
+			events = append(events, copyEvent(*ev))
+		}
+	}
+	return events
+}
+
+func (r *Repository) insertIndex(id int64) {
+	i := sort.Search(len(r.byDate), func(i int) bool { return r.less(id, r.byDate[i]) })
+	r.byDate = append(r.byDate, 0)
+	copy(r.byDate[i+1:], r.byDate[i:])
+	r.byDate[i] = id
+}
+
+func (r *Repository) removeIndex(id int64) {
+	i := sort.Search(len(r.byDate), func(i int) bool { return !r.less(r.byDate[i], id) })
+	if i < len(r.byDate) && r.byDate[i] == id {
+		r.byDate = append(r.byDate[:i], r.byDate[i+1:]...)
+	}
+}
+
+// less orders events by date, then id.
+func (r *Repository) less(a, b int64) bool {
+	da, db := r.events[a].Date, r.events[b].Date
+	if !da.Equal(db) {
+		return da.Before(db)
+	}
+	return a < b
+}
+
+// mergeAttendees returns the invitee list after replacing current with attendees: removed
+// invitees are dropped, remaining ones keep their response and new ones are appended
+// users first, then emails. Resources are not kept in memory and are reported missing.
+func mergeAttendees(current, attendees []models.Attendee) ([]models.Attendee, error) {
+	var users, emails []models.Attendee
+	for _, a := range attendees {
+		switch {
+		case a.UserID != 0:
+			if a.Status == "" {
+				a.Status = models.AttendeeNeedsAction
+			}
+			users = append(users, models.Attendee{UserID: a.UserID, Status: a.Status, Comment: a.Comment})
+		case a.ResourceID != 0:
+			return nil, fmt.Errorf("resource: %w", models.ErrNotFound)
+		default:
+			emails = append(emails, models.Attendee{Email: a.Email, Status: models.AttendeeNeedsAction})
+		}
+	}
+	same := func(a, b models.Attendee) bool {
+		return a.UserID == b.UserID && (a.UserID != 0 || a.Email == b.Email)
+	}
+	var merged []models.Attendee
+	for _, c := range current {
+		for _, a := range append(users, emails...) {
+			if same(c, a) {
+				merged = append(merged, c)
+				break
+			}
+		}
+	}
+	for _, a := range append(users, emails...) {
+		found := false
+		for _, m := range merged {
+			found = found || same(m, a)
+		}
+		if !found {
+			merged = append(merged, a)
+		}
+	}
+	return merged, nil
+}
+
+func invited(ev *models.Event, userID int64) bool {
+	for _, a := range ev.Attendees {
+		if a.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// copyEvent detaches an event from the store, filling in the attendees' event id.
+func copyEvent(ev models.Event) models.Event {
+	attendees := ev.Attendees
+	ev.Attendees = nil
+	for _, a := range attendees {
+		a.EventID = ev.ID
+		ev.Attendees = append(ev.Attendees, a)
+	}
+	return ev
+}
+
+// day truncates t to its calendar date, as a DATE column stores it.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// addMonth adds a calendar month, clamping to the last day of a shorter month like
+// Postgres interval arithmetic does: January 31 plus a month is February 28 or 29.
+func addMonth(t time.Time) time.Time {
+	next := t.AddDate(0, 1, 0)
+	if next.Day() != t.Day() {
+		next = next.AddDate(0, 0, -next.Day())
+	}
+	return next
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/repository/repotest"
+	"awesomeProject/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRepository_Conformance(t *testing.T) {
+	repotest.RunCalendarRepository(t, func(t *testing.T) service.CalendarRepository {
+		return NewRepository(zap.NewNop())
+	})
+}
+
+func TestRepository_ConcurrentWrites(t *testing.T) {
+	repo := NewRepository(zap.NewNop())
+	ctx := context.Background()
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d := day.AddDate(0, 0, i%7)
+			ev := &models.Event{UserID: 1, Event: "E", Date: d, Start: d, End: d.Add(time.Hour)}
+			require.NoError(t, repo.CreateEvent(ctx, ev))
+			_, err := repo.GetEventsForWeek(ctx, &models.Event{UserID: 1, Date: day})
+			require.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	events, err := repo.GetEventsForWeek(ctx, &models.Event{UserID: 1, Date: day})
+	require.NoError(t, err)
+	require.Len(t, events, 50)
+	for i := 1; i < len(events); i++ {
+		require.False(t, events[i].Date.Before(events[i-1].Date))
+	}
+}
+
+func TestAddMonth(t *testing.T) {
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), addMonth(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC), addMonth(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), addMonth(time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// Ids come from a sequence when the row is inserted, not when it commits. Holding this
+	// lock until commit keeps a user's writers in line, so a later id never becomes visible
+	// before an earlier one of the same user.
+	lockOutboxWriterQuery  = `SELECT pg_advisory_xact_lock(hashtext('outbox:write'), $1)`
+	insertOutboxQuery      = `INSERT INTO outbox (user_id, event_type, payload) VALUES ($1, $2, $3)`
+	pendingOutboxUserQuery = `
+		SELECT user_id FROM outbox WHERE published_at IS NULL AND NOT $2::text = ANY(published_sinks)
+		GROUP BY user_id
+		ORDER BY min(id)
+		LIMIT $1`
+	// The advisory lock is held until commit, so only one relay publishes a given user's
+	// messages to a sink at a time.
+	lockOutboxUserQuery = `SELECT pg_try_advisory_xact_lock(hashtext('outbox:' || $2::text), $1)`
+	userOutboxQuery     = `
+		SELECT id, user_id, event_type, payload, created_at FROM outbox
+		WHERE user_id = $1 AND published_at IS NULL AND NOT $3::text = ANY(published_sinks)
+		ORDER BY id
+		LIMIT $2`
+	markOutboxDeliveredQuery = `UPDATE outbox SET published_sinks = array_append(published_sinks, $1) WHERE id = ANY($2)`
+	markOutboxPublishedQuery = `UPDATE outbox SET published_at = $1 WHERE published_at IS NULL AND published_sinks @> $2`
+	pruneOutboxQuery         = `
+		DELETE FROM outbox WHERE id IN (
+			SELECT id FROM outbox WHERE published_at < $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED
+		)`
+)
+
+// writeOutbox records a change event inside the transaction that makes the change.
+func writeOutbox(ctx context.Context, tx pgx.Tx, eventType string, event models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox payload: %w", err)
+	}
+	if _, err := tx.Exec(ctx, lockOutboxWriterQuery, event.UserID); err != nil {
+		return fmt.Errorf("failed to lock outbox: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertOutboxQuery, event.UserID, eventType, payload); err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+	return nil
+}
+
+// ProcessOutbox hands the messages sink has not delivered yet to fn in id order, user by
+// user. Users locked by another relay are skipped. A failure stops that user's batch so later
+// messages never overtake an undelivered earlier one; everything fn accepted is marked
+// delivered to sink.
+func (r *Repository) ProcessOutbox(ctx context.Context, sink string, users int, perUser int, fn func(ctx context.Context, msg models.OutboxMessage) error) (int, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, pendingOutboxUserQuery, users, sink)
+	if err != nil {
+		r.log.Error("Error get outbox users", zap.Error(err))
+		return 0, fmt.Errorf("failed to get outbox users: %w", err)
+	}
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		r.log.Error("Error get outbox users", zap.Error(err))
+		return 0, fmt.Errorf("failed to get outbox users: %w", err)
+	}
+
+	var delivered []int64
+	for _, userID := range userIDs {
+		var locked bool
+		if err := tx.QueryRow(ctx, lockOutboxUserQuery, userID, sink).Scan(&locked); err != nil {
+			r.log.Error("Error lock outbox user", zap.Error(err))
+			return 0, fmt.Errorf("failed to lock outbox user: %w", err)
+		}
+		if !locked {
+			continue
+		}
+		rows, err := tx.Query(ctx, userOutboxQuery, userID, perUser, sink)
+		if err != nil {
+			r.log.Error("Error get outbox messages", zap.Error(err))
+			return 0, fmt.Errorf("failed to get outbox messages: %w", err)
+		}
+		messages, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.OutboxMessage, error) {
+			var msg models.OutboxMessage
+			err := row.Scan(&msg.ID, &msg.UserID, &msg.Type, &msg.Payload, &msg.CreatedAt)
+			return msg, err
+		})
+		if err != nil {
+			r.log.Error("Error get outbox messages", zap.Error(err))
+			return 0, fmt.Errorf("failed to get outbox messages: %w", err)
+		}
+		for _, msg := range messages {
+			if err := fn(ctx, msg); err != nil {
+				r.log.Warn("Outbox publish failed", zap.String("sink", sink), zap.Int64("outbox_id", msg.ID), zap.Int64("user_id", userID), zap.Error(err))
+				break
+			}
+			delivered = append(delivered, msg.ID)
+		}
+	}
+
+	if len(delivered) > 0 {
+		if _, err := tx.Exec(ctx, markOutboxDeliveredQuery, sink, delivered); err != nil {
+			r.log.Error("Error mark outbox delivered", zap.Error(err))
+			return 0, fmt.Errorf("failed to mark outbox delivered: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("Error commit transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(delivered), nil
+}
+
+// MarkOutboxPublished marks the messages every one of sinks delivered as published.
+func (r *Repository) MarkOutboxPublished(ctx context.Context, now time.Time, sinks []string) (int, error) {
+	tag, err := r.db.Exec(ctx, markOutboxPublishedQuery, now, sinks)
+	if err != nil {
+		r.log.Error("Error mark outbox published", zap.Error(err))
+		return 0, fmt.Errorf("failed to mark outbox published: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// PruneOutbox deletes up to limit messages published before the given time.
+func (r *Repository) PruneOutbox(ctx context.Context, before time.Time, limit int) (int, error) {
+	tag, err := r.db.Exec(ctx, pruneOutboxQuery, before, limit)
+	if err != nil {
+		r.log.Error("Error prune outbox", zap.Error(err))
+		return 0, fmt.Errorf("failed to prune outbox: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
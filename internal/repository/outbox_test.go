@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessOutbox_FailureStopsOnlyThatUser(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "First", date(2025, 6, 2), 9, 10)))
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(2, "Other", date(2025, 6, 2), 9, 10)))
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Second", date(2025, 6, 3), 9, 10)))
+
+	var seen []int64
+	published, err := repo.ProcessOutbox(ctx, "broker", 10, 10, func(ctx context.Context, msg models.OutboxMessage) error {
+		seen = append(seen, msg.ID)
+		if msg.UserID == 1 {
+			return errors.New("broker down")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	// User 1's second message is never offered once the first one fails.
+	require.Equal(t, []int64{1, 2}, seen)
+
+	messages := publishedOutbox(t, repo)
+	require.Len(t, messages, 2)
+	require.Equal(t, []int64{1, 3}, []int64{messages[0].ID, messages[1].ID})
+	require.Empty(t, publishedOutbox(t, repo))
+}
+
+func TestProcessOutbox_Limits(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	for user := int64(1); user <= 3; user++ {
+		for i := 0; i < 3; i++ {
+			require.NoError(t, repo.CreateEvent(ctx, timedEvent(user, "Event", date(2025, 6, 2+i), 9, 10)))
+		}
+	}
+
+	var users []int64
+	published, err := repo.ProcessOutbox(ctx, "broker", 2, 2, func(ctx context.Context, msg models.OutboxMessage) error {
+		users = append(users, msg.UserID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, published)
+	require.Equal(t, []int64{1, 1, 2, 2}, users)
+	require.Len(t, publishedOutbox(t, repo), 5)
+}
+
+func TestProcessOutbox_SinksMarkPublishedAndPrune(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "First", date(2025, 6, 2), 9, 10)))
+
+	accept := func(ctx context.Context, msg models.OutboxMessage) error { return nil }
+	fail := func(ctx context.Context, msg models.OutboxMessage) error { return errors.New("down") }
+	published, err := repo.ProcessOutbox(ctx, "webhooks", 10, 10, accept)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	published, err = repo.ProcessOutbox(ctx, "itip", 10, 10, fail)
+	require.NoError(t, err)
+	require.Zero(t, published)
+
+	// A sink is not offered what it already accepted.
+	published, err = repo.ProcessOutbox(ctx, "webhooks", 10, 10, fail)
+	require.NoError(t, err)
+	require.Zero(t, published)
+
+	now := time.Now()
+	marked, err := repo.MarkOutboxPublished(ctx, now, []string{"webhooks", "itip"})
+	require.NoError(t, err)
+	require.Zero(t, marked)
+	published, err = repo.ProcessOutbox(ctx, "itip", 10, 10, accept)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	marked, err = repo.MarkOutboxPublished(ctx, now, []string{"webhooks", "itip"})
+	require.NoError(t, err)
+	require.Equal(t, 1, marked)
+
+	pruned, err := repo.PruneOutbox(ctx, now, 10)
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+	pruned, err = repo.PruneOutbox(ctx, now.Add(time.Second), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, pruned)
+}
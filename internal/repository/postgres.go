@@ -3,37 +3,76 @@ package repository
 import (
 	"awesomeProject/internal/models"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
+	"time"
 )
 
 type Repository struct {
-	db  *pgxpool.Pool
-	log *zap.Logger
+	db *pgxpool.Pool
+	// replicas serve reads of views and free/busy; nil without replicas.
+	replicas *replicaSet
+	log      *zap.Logger
 }
 
 func (s *Storage) NewRepository() *Repository {
-	return &Repository{db: s.db, log: s.log.Named("repository")}
+	return &Repository{db: s.db, replicas: s.replicas, log: s.log.Named("repository")}
 }
 
+const (
+	// exclusionViolation is the SQLSTATE raised when an exclusion constraint rejects a row.
+	exclusionViolation = "23P01"
+	// foreignKeyViolation is raised when a row references a missing parent.
+	foreignKeyViolation = "23503"
+)
+
+const eventColumns = `id,user_id,date,event,start_at,end_at,all_day,transparency,visibility,rrule,sequence`
+
 const (
 	createQuery = `
-		INSERT INTO calendar (user_id, date,event) VALUES ($1,$2,$3) `
-	updateQuery     = `UPDATE calendar SET date = $1, event = $2 WHERE user_id = $3`
-	deleteQuery     = `DELETE FROM calendar WHERE user_id = $1 AND date = $2 `
-	getForDayQuery  = `SELECT user_id,date,event FROM calendar WHERE user_id = $1 AND date = $2`
-	getForWeekQuery = `SELECT user_id,date,event FROM calendar WHERE user_id = $1 
+		INSERT INTO calendar (user_id, date,event,start_at,end_at,all_day,transparency,visibility,rrule,exclusive)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,
+		        COALESCE((SELECT no_double_booking FROM user_profiles WHERE user_id = $1), FALSE))
+		RETURNING id`
+	// The event about to be updated is locked and read first for the audit log.
+	selectForUpdateQuery = `SELECT ` + eventColumns + ` FROM calendar
+                                WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL
+                                FOR UPDATE`
+	updateQuery = `UPDATE calendar SET date = $1, event = $2, start_at = $4, end_at = $5, all_day = $6,
+                    transparency = $7, visibility = $8, rrule = $9, sequence = sequence + 1
+                WHERE user_id = $3 AND id = $10 AND deleted_at IS NULL RETURNING ` + eventColumns
+	// Without an id every event of the user on the date is deleted. Rows are locked first so
+	// the attendees can still be read for the outbox message.
+	selectForDeleteQuery = `SELECT ` + eventColumns + ` FROM calendar
+                                WHERE user_id = $1 AND (($3::bigint = 0 AND date = $2) OR id = $3) AND deleted_at IS NULL
+                                FOR UPDATE`
+	// Deleted events move to the trash; see trash.go.
+	deleteQuery = `UPDATE calendar SET deleted_at = now() WHERE id = ANY($1)`
+	// Views list the user's own events and the events they are invited to, leaving out the trash.
+	ownOrInvited    = `(user_id = $1 OR id IN (SELECT event_id FROM event_attendees WHERE user_id = $1)) AND deleted_at IS NULL`
+	getForDayQuery  = `SELECT ` + eventColumns + ` FROM calendar WHERE ` + ownOrInvited + ` AND date = $2`
+	getForWeekQuery = `SELECT ` + eventColumns + ` FROM calendar WHERE ` + ownOrInvited + ` 
                                     AND date >= $2::date 
                                     AND date < $2::date + INTERVAL '7 day' 
                                 ORDER BY date;`
-	getForMouthQuery = `    SELECT user_id,date, event 
+	getForMouthQuery = `    SELECT ` + eventColumns + ` 
     FROM calendar 
-    WHERE user_id = $1 
+    WHERE ` + ownOrInvited + ` 
       AND date >= $2::date 
-      AND date <= $2::date + INTERVAL '1 month'
+      AND date < $2::date + INTERVAL '1 month'
     ORDER BY date;`
+	// Recurring series are returned whenever they start before the window ends; callers expand them.
+	// Invitations count unless declined, as invitees are busy during meetings they go to.
+	getInRangeQuery = `SELECT ` + eventColumns + ` FROM calendar
+                                WHERE (user_id = ANY($1) OR id IN (SELECT event_id FROM event_attendees
+                                                                   WHERE user_id = ANY($1) AND status <> 'declined'))
+                                  AND deleted_at IS NULL
+                                  AND (during && tstzrange($2, $3) OR (rrule <> '' AND start_at < $3))
+                                ORDER BY user_id, start_at`
 )
 
 func (r *Repository) CreateEvent(ctx context.Context, event *models.Event) error {
@@ -49,18 +88,41 @@ func (r *Repository) CreateEvent(ctx context.Context, event *models.Event) error
 		}
 	}()
 
-	_, err = tx.Exec(ctx, createQuery,
+	err = tx.QueryRow(ctx, createQuery,
 		event.UserID,
 		event.Date,
 		event.Event,
-	)
+		event.Start,
+		event.End,
+		event.AllDay,
+		event.Transparency,
+		event.Visibility,
+		event.RRule,
+	).Scan(&event.ID)
 	if err != nil {
 		r.log.Error("Error create event", zap.Error(err))
-		return fmt.Errorf("failed to create event: %w", err)
+		return fmt.Errorf("failed to create event: %w", conflictErr(err))
+	}
+	if len(event.Attendees) > 0 {
+		if err = replaceAttendees(ctx, tx, event.ID, event.Attendees); err != nil {
+			r.log.Error("Error save attendees", zap.Error(err))
+			return err
+		}
+	}
+	if err = writeOutbox(ctx, tx, models.NotificationEventCreated, *event); err != nil {
+		r.log.Error("Error write outbox", zap.Error(err))
+		return err
+	}
+	if err = recordChange(ctx, tx, models.AuditCreate, userActor(event.UserID), event.ID, nil, event); err != nil {
+		r.log.Error("Error record change", zap.Error(err))
+		return err
 	}
 	r.log.Debug("Created event", zap.Any("event", event))
-
-	return tx.Commit(ctx)
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+	r.replicas.wrote(viewerIDs(event)...)
+	return nil
 }
 func (r *Repository) UpdateEvent(ctx context.Context, event *models.Event) error {
 	r.log.Debug("Updating Event", zap.Any("event", event))
@@ -74,17 +136,61 @@ func (r *Repository) UpdateEvent(ctx context.Context, event *models.Event) error
 			tx.Rollback(ctx)
 		}
 	}()
-	_, err = tx.Exec(ctx, updateQuery,
+	previous, err := collectEvents(tx.Query(ctx, selectForUpdateQuery, event.UserID, event.ID))
+	if err == nil {
+		err = loadAttendees(ctx, tx, previous)
+	}
+	if err != nil {
+		r.log.Error("Error get events to update", zap.Error(err))
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	before := make(map[int64]*models.Event, len(previous))
+	for i := range previous {
+		before[previous[i].ID] = &previous[i]
+	}
+	updated, err := collectEvents(tx.Query(ctx, updateQuery,
 		event.Date,
 		event.Event,
 		event.UserID,
-	)
+		event.Start,
+		event.End,
+		event.AllDay,
+		event.Transparency,
+		event.Visibility,
+		event.RRule,
+		event.ID,
+	))
 	if err != nil {
 		r.log.Error("Error update event", zap.Error(err))
-		return fmt.Errorf("failed to update event: %w", err)
+		return fmt.Errorf("failed to update event: %w", conflictErr(err))
+	}
+	if event.Attendees != nil && len(updated) > 0 {
+		if err = replaceAttendees(ctx, tx, event.ID, event.Attendees); err != nil {
+			r.log.Error("Error save attendees", zap.Error(err))
+			return err
+		}
+	}
+	if err = loadAttendees(ctx, tx, updated); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return err
+	}
+	for _, ev := range updated {
+		if err = writeOutbox(ctx, tx, models.NotificationEventUpdated, ev); err != nil {
+			r.log.Error("Error write outbox", zap.Error(err))
+			return err
+		}
+		if err = recordChange(ctx, tx, models.AuditUpdate, userActor(event.UserID), ev.ID, before[ev.ID], &ev); err != nil {
+			r.log.Error("Error record change", zap.Error(err))
+			return err
+		}
 	}
 	r.log.Debug("Updated event", zap.Any("event", event))
-	return tx.Commit(ctx)
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+	// Invitees dropped by the update see the change as much as those added by it.
+	r.replicas.wrote(append(viewersOf(previous...), viewersOf(updated...)...)...)
+	return nil
 }
 func (r *Repository) DeleteEvent(ctx context.Context, event *models.Event) error {
 	r.log.Debug("Deleting Event", zap.Any("event", event))
@@ -98,46 +204,48 @@ func (r *Repository) DeleteEvent(ctx context.Context, event *models.Event) error
 			tx.Rollback(ctx)
 		}
 	}()
-	_, err = tx.Exec(ctx, deleteQuery,
+	deleted, err := collectEvents(tx.Query(ctx, selectForDeleteQuery,
 		event.UserID,
 		event.Date,
-	)
+		event.ID,
+	))
 	if err != nil {
 		r.log.Error("Error delete event", zap.Error(err))
 		return fmt.Errorf("failed to delete event: %w", err)
 	}
+	if err = loadAttendees(ctx, tx, deleted); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return err
+	}
+	ids := make([]int64, 0, len(deleted))
+	for _, ev := range deleted {
+		ids = append(ids, ev.ID)
+	}
+	if _, err = tx.Exec(ctx, deleteQuery, ids); err != nil {
+		r.log.Error("Error delete event", zap.Error(err))
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	for _, ev := range deleted {
+		if err = writeOutbox(ctx, tx, models.NotificationEventDeleted, ev); err != nil {
+			r.log.Error("Error write outbox", zap.Error(err))
+			return err
+		}
+		if err = recordChange(ctx, tx, models.AuditDelete, userActor(event.UserID), ev.ID, &ev, nil); err != nil {
+			r.log.Error("Error record change", zap.Error(err))
+			return err
+		}
+	}
 	r.log.Debug("Deleted event", zap.Any("event", event))
-	return tx.Commit(ctx)
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+	r.replicas.wrote(viewersOf(deleted...)...)
+	return nil
 }
 func (r *Repository) GetEventsForDay(ctx context.Context, event *models.Event) ([]models.Event, error) {
 	r.log.Debug("Getting Events for Day", zap.Any("event", event))
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	events, err := r.view(ctx, getForDayQuery, event)
 	if err != nil {
-		r.log.Error("Error begin transaction", zap.Error(err))
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		}
-	}()
-	queryEvents, err := tx.Query(ctx, getForDayQuery, event.UserID, event.Date)
-	if err != nil {
-		r.log.Error("Error get events for day", zap.Error(err))
-		return nil, fmt.Errorf("failed to get events for day: %w", err)
-	}
-	var events []models.Event
-	for queryEvents.Next() {
-		var ev models.Event
-		err = queryEvents.Scan(&ev.UserID, &ev.Date, &ev.Event)
-		if err != nil {
-			r.log.Error("Error get events for day", zap.Error(err))
-			return nil, fmt.Errorf("failed to get events for day: %w", err)
-		}
-		events = append(events, ev)
-	}
-	queryEvents.Close()
-	if err := queryEvents.Err(); err != nil {
 		r.log.Error("Error get events for day", zap.Error(err))
 		return nil, fmt.Errorf("failed to get events for day: %w", err)
 	}
@@ -146,74 +254,84 @@ func (r *Repository) GetEventsForDay(ctx context.Context, event *models.Event) (
 }
 func (r *Repository) GetEventsForWeek(ctx context.Context, event *models.Event) ([]models.Event, error) {
 	r.log.Debug("Getting Events for Week", zap.Any("event", event))
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
-		r.log.Error("Error begin transaction", zap.Error(err))
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		}
-	}()
-	queryEvents, err := tx.Query(ctx, getForWeekQuery, event.UserID, event.Date)
+	events, err := r.view(ctx, getForWeekQuery, event)
 	if err != nil {
 		r.log.Error("Error get events for week", zap.Error(err))
 		return nil, fmt.Errorf("failed to get events for week: %w", err)
 	}
-	var events []models.Event
-	for queryEvents.Next() {
-		var ev models.Event
-		err = queryEvents.Scan(&ev.UserID, &ev.Date, &ev.Event)
-		if err != nil {
-			r.log.Error("Error get events for week", zap.Error(err))
-			return nil, fmt.Errorf("failed to get events for week: %w", err)
-		}
-		events = append(events, ev)
-	}
-	queryEvents.Close()
-	if err := queryEvents.Err(); err != nil {
-		r.log.Error("Error get events for week", zap.Error(err))
-		return nil, fmt.Errorf("failed to get events for week: %w", err)
-	}
 	return events, nil
 }
 func (r *Repository) GetEventsForMonth(ctx context.Context, event *models.Event) ([]models.Event, error) {
 	r.log.Debug("Getting Events for Month", zap.Any("event", event))
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	events, err := r.view(ctx, getForMouthQuery, event)
 	if err != nil {
-		r.log.Error("Error begin transaction", zap.Error(err))
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		r.log.Error("Error get events for month", zap.Error(err))
+		return nil, fmt.Errorf("failed to get events for month: %w", err)
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
+	return events, nil
+}
+
+// view runs a day, week or month query of the user and loads the attendees of the events.
+func (r *Repository) view(ctx context.Context, query string, event *models.Event) ([]models.Event, error) {
+	var events []models.Event
+	err := r.read(ctx, []int64{event.UserID}, func(q querier) error {
+		var err error
+		if events, err = collectEvents(q.Query(ctx, query, event.UserID, event.Date)); err != nil {
+			return err
 		}
-	}()
-	queryEvents, err := tx.Query(ctx, getForMouthQuery, event.UserID, event.Date)
+		return loadAttendees(ctx, q, events)
+	})
+	return events, err
+}
+
+func (r *Repository) GetEventsInRange(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Event, error) {
+	r.log.Debug("Getting events in range", zap.Int64s("user_ids", userIDs), zap.Time("from", from), zap.Time("to", to))
+	var events []models.Event
+	err := r.read(ctx, userIDs, func(q querier) error {
+		var err error
+		events, err = collectEvents(q.Query(ctx, getInRangeQuery, userIDs, from, to))
+		return err
+	})
 	if err != nil {
-		r.log.Error("Error get events for month", zap.Error(err))
-		return nil, fmt.Errorf("failed to get events for month: %w", err)
+		r.log.Error("Error get events in range", zap.Error(err))
+		return nil, fmt.Errorf("failed to get events in range: %w", err)
 	}
+	r.log.Debug("Got events in range", zap.Int("events", len(events)))
+	return events, nil
+}
+
+// conflictErr maps violations of the no-double-booking exclusion constraint to models.ErrConflict.
+func conflictErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+		return models.ErrConflict
+	}
+	return err
+}
+
+func scanEvent(row pgx.Row, ev *models.Event) error {
+	return row.Scan(&ev.ID, &ev.UserID, &ev.Date, &ev.Event, &ev.Start, &ev.End, &ev.AllDay, &ev.Transparency, &ev.Visibility, &ev.RRule, &ev.Sequence)
+}
+
+// collectEvents drains rows of eventColumns into events.
+func collectEvents(rows pgx.Rows, err error) ([]models.Event, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 	var events []models.Event
-	for queryEvents.Next() {
+	for rows.Next() {
 		var ev models.Event
-		err = queryEvents.Scan(&ev.UserID, &ev.Date, &ev.Event)
-		if err != nil {
-			r.log.Error("Error get events for mouth", zap.Error(err))
-			return nil, fmt.Errorf("failed to get events for month: %w", err)
+		if err := scanEvent(rows, &ev); err != nil {
+			return nil, err
 		}
 		events = append(events, ev)
 	}
-	queryEvents.Close()
-	if err := queryEvents.Err(); err != nil {
-		r.log.Error("Error get events for month", zap.Error(err))
-		return nil, fmt.Errorf("failed to get events for month: %w", err)
-	}
-	return events, nil
+	return events, rows.Err()
 }
 
 func (r *Repository) Close() {
 	r.log.Info("Closing repository")
 	r.db.Close()
+	r.replicas.close()
 }
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/repository/repotest"
+	"awesomeProject/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Conformance(t *testing.T) {
+	repotest.RunCalendarRepository(t, func(t *testing.T) service.CalendarRepository {
+		return newTestRepo(t)
+	})
+}
+
+func timedEvent(user int64, name string, day time.Time, start, end int) *models.Event {
+	return &models.Event{
+		UserID:       user,
+		Event:        name,
+		Date:         day,
+		Start:        day.Add(time.Duration(start) * time.Hour),
+		End:          day.Add(time.Duration(end) * time.Hour),
+		Transparency: models.TransparencyOpaque,
+		Visibility:   models.VisibilityDefault,
+	}
+}
+
+// publishedOutbox drains the outbox and returns the messages in publication order.
+func publishedOutbox(t *testing.T, repo *Repository) []models.OutboxMessage {
+	t.Helper()
+	var messages []models.OutboxMessage
+	_, err := repo.ProcessOutbox(context.Background(), "broker", 100, 100, func(ctx context.Context, msg models.OutboxMessage) error {
+		messages = append(messages, msg)
+		return nil
+	})
+	require.NoError(t, err)
+	return messages
+}
+
+func TestRepository_EventChangesWriteOutbox(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	ev := timedEvent(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}}
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	ev.Event = "Planning moved"
+	ev.Attendees = nil
+	require.NoError(t, repo.UpdateEvent(ctx, ev))
+	require.NoError(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{UserID: 2, Status: models.AttendeeAccepted}))
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: ev.ID, UserID: 1}))
+
+	messages := publishedOutbox(t, repo)
+	require.Len(t, messages, 4)
+	types := []string{}
+	for _, msg := range messages {
+		require.Equal(t, int64(1), msg.UserID)
+		types = append(types, msg.Type)
+	}
+	require.Equal(t, []string{
+		models.NotificationEventCreated,
+		models.NotificationEventUpdated,
+		models.NotificationEventResponded,
+		models.NotificationEventDeleted,
+	}, types)
+
+	var deleted models.Event
+	require.NoError(t, json.Unmarshal(messages[3].Payload, &deleted))
+	require.Equal(t, "Planning moved", deleted.Event)
+	require.Equal(t, 1, deleted.Sequence)
+	require.Len(t, deleted.Attendees, 1)
+	require.Equal(t, models.AttendeeAccepted, deleted.Attendees[0].Status)
+}
+
+func TestRepository_NoMatchWritesNoOutbox(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	missing := timedEvent(1, "Ghost", date(2025, 6, 2), 9, 10)
+	missing.ID = 99
+	require.NoError(t, repo.UpdateEvent(ctx, missing))
+	require.NoError(t, repo.DeleteEvent(ctx, missing))
+	require.Empty(t, publishedOutbox(t, repo))
+}
+
+func TestRepository_FailedCreateRollsBack(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	ev := timedEvent(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}, {ResourceID: 42}}
+	require.ErrorIs(t, repo.CreateEvent(ctx, ev), models.ErrNotFound)
+
+	events, err := repo.GetEventsForDay(ctx, &models.Event{UserID: 1, Date: date(2025, 6, 2)})
+	require.NoError(t, err)
+	require.Empty(t, events)
+	require.Empty(t, publishedOutbox(t, repo))
+}
+
+func TestRepository_MonthViewLeapYear(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	for _, ev := range []*models.Event{
+		timedEvent(1, "Start", date(2024, 1, 31), 9, 10),
+		timedEvent(1, "Leap day", date(2024, 2, 29), 9, 10),
+		timedEvent(1, "After", date(2024, 3, 1), 9, 10),
+		timedEvent(1, "Leap month end", date(2024, 3, 29), 9, 10),
+		timedEvent(1, "Too late", date(2024, 3, 30), 9, 10),
+	} {
+		require.NoError(t, repo.CreateEvent(ctx, ev))
+	}
+
+	// January 31 plus a month is February 29 in a leap year, the first day left out.
+	events, err := repo.GetEventsForMonth(ctx, &models.Event{UserID: 1, Date: date(2024, 1, 31)})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "Start", events[0].Event)
+
+	// February 29 plus a month is March 29.
+	events, err = repo.GetEventsForMonth(ctx, &models.Event{UserID: 1, Date: date(2024, 2, 29)})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "After", events[1].Event)
+}
+
+func TestRepository_EmptyResults(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	query := &models.Event{UserID: 1, Date: date(2025, 6, 2)}
+	day, err := repo.GetEventsForDay(ctx, query)
+	require.NoError(t, err)
+	require.Empty(t, day)
+	week, err := repo.GetEventsForWeek(ctx, query)
+	require.NoError(t, err)
+	require.Empty(t, week)
+	month, err := repo.GetEventsForMonth(ctx, query)
+	require.NoError(t, err)
+	require.Empty(t, month)
+	inRange, err := repo.GetEventsInRange(ctx, []int64{1}, date(2025, 6, 2), date(2025, 6, 3))
+	require.NoError(t, err)
+	require.Empty(t, inRange)
+}
+
+func TestRepository_NoDoubleBooking(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	require.NoError(t, repo.UpsertProfile(ctx, &models.Profile{UserID: 1, TimeZone: "UTC", NoDoubleBooking: true}))
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Sync", date(2025, 6, 2), 9, 10)))
+
+	err := repo.CreateEvent(ctx, timedEvent(1, "Overlap", date(2025, 6, 2), 9, 11))
+	require.ErrorIs(t, err, models.ErrConflict)
+
+	transparent := timedEvent(1, "Focus", date(2025, 6, 2), 9, 11)
+	transparent.Transparency = models.TransparencyTransparent
+	require.NoError(t, repo.CreateEvent(ctx, transparent))
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Adjacent", date(2025, 6, 2), 10, 11)))
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(2, "Other user", date(2025, 6, 2), 9, 10)))
+}
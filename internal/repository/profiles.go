@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	upsertProfileQuery = `
+		INSERT INTO user_profiles (user_id, email, name, time_zone, no_double_booking) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET email = EXCLUDED.email, name = EXCLUDED.name, time_zone = EXCLUDED.time_zone,
+		                                    no_double_booking = EXCLUDED.no_double_booking`
+	// Propagating the flag onto existing events fails with an exclusion violation if they already overlap.
+	syncExclusiveQuery = `UPDATE calendar SET exclusive = $2 WHERE user_id = $1 AND exclusive <> $2`
+	getProfileQuery    = `SELECT user_id, email, name, time_zone, no_double_booking FROM user_profiles WHERE user_id = $1`
+	getTimeZonesQuery  = `SELECT user_id, time_zone FROM user_profiles WHERE user_id = ANY($1)`
+	getWorkingHours    = `SELECT user_id, weekday, start_minute, end_minute FROM working_hours
+                                WHERE user_id = ANY($1) ORDER BY user_id, weekday`
+	deleteWorkingHoursQuery = `DELETE FROM working_hours WHERE user_id = $1`
+	insertWorkingHoursQuery = `INSERT INTO working_hours (user_id, weekday, start_minute, end_minute) VALUES ($1, $2, $3, $4)`
+	deleteOutOfOfficeQuery  = `DELETE FROM out_of_office WHERE user_id = $1`
+	insertOutOfOfficeQuery  = `INSERT INTO out_of_office (user_id, start_at, end_at, message) VALUES ($1, $2, $3, $4)`
+	getOutOfOfficeQuery     = `SELECT id, user_id, start_at, end_at, message FROM out_of_office
+                                WHERE user_id = ANY($1) AND start_at < $3 AND end_at > $2
+                                ORDER BY user_id, start_at`
+)
+
+// upcoming is the window used to list out-of-office periods that have not ended yet.
+var upcoming = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (r *Repository) UpsertProfile(ctx context.Context, profile *models.Profile) error {
+	r.log.Debug("Saving profile", zap.Int64("user_id", profile.UserID))
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, upsertProfileQuery, profile.UserID, profile.Email, profile.Name, profile.TimeZone, profile.NoDoubleBooking); err != nil {
+		r.log.Error("Error save profile", zap.Error(err))
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+	if _, err := tx.Exec(ctx, syncExclusiveQuery, profile.UserID, profile.NoDoubleBooking); err != nil {
+		r.log.Error("Error sync double booking flag", zap.Error(err))
+		return fmt.Errorf("failed to save profile: %w", conflictErr(err))
+	}
+	if profile.WorkingHours != nil {
+		if _, err := tx.Exec(ctx, deleteWorkingHoursQuery, profile.UserID); err != nil {
+			r.log.Error("Error save working hours", zap.Error(err))
+			return fmt.Errorf("failed to save working hours: %w", err)
+		}
+		for _, wh := range profile.WorkingHours {
+			if _, err := tx.Exec(ctx, insertWorkingHoursQuery, profile.UserID, int(wh.Weekday), wh.StartMinute, wh.EndMinute); err != nil {
+				r.log.Error("Error save working hours", zap.Error(err))
+				return fmt.Errorf("failed to save working hours: %w", err)
+			}
+		}
+	}
+	if profile.OutOfOffice != nil {
+		if _, err := tx.Exec(ctx, deleteOutOfOfficeQuery, profile.UserID); err != nil {
+			r.log.Error("Error save out of office", zap.Error(err))
+			return fmt.Errorf("failed to save out of office: %w", err)
+		}
+		for i := range profile.OutOfOffice {
+			ooo := &profile.OutOfOffice[i]
+			ooo.UserID = profile.UserID
+			if _, err := tx.Exec(ctx, insertOutOfOfficeQuery, ooo.UserID, ooo.Start, ooo.End, ooo.Message); err != nil {
+				r.log.Error("Error save out of office", zap.Error(err))
+				return fmt.Errorf("failed to save out of office: %w", err)
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *Repository) GetProfile(ctx context.Context, userID int64) (*models.Profile, error) {
+	r.log.Debug("Getting profile", zap.Int64("user_id", userID))
+	profile := &models.Profile{}
+	err := r.db.QueryRow(ctx, getProfileQuery, userID).Scan(&profile.UserID, &profile.Email, &profile.Name, &profile.TimeZone, &profile.NoDoubleBooking)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("profile %d: %w", userID, models.ErrNotFound)
+	}
+	if err != nil {
+		r.log.Error("Error get profile", zap.Error(err))
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	schedules, err := r.GetSchedules(ctx, []int64{userID}, time.Now(), upcoming)
+	if err != nil {
+		return nil, err
+	}
+	profile.WorkingHours = schedules[0].WorkingHours
+	profile.OutOfOffice = schedules[0].OutOfOffice
+	return profile, nil
+}
+
+// GetOutOfOffice returns the out-of-office periods of the users overlapping [from, to).
+func (r *Repository) GetOutOfOffice(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.OutOfOffice, error) {
+	r.log.Debug("Getting out of office", zap.Int64s("user_ids", userIDs), zap.Time("from", from), zap.Time("to", to))
+	rows, err := r.db.Query(ctx, getOutOfOfficeQuery, userIDs, from, to)
+	if err != nil {
+		r.log.Error("Error get out of office", zap.Error(err))
+		return nil, fmt.Errorf("failed to get out of office: %w", err)
+	}
+	defer rows.Close()
+	var periods []models.OutOfOffice
+	for rows.Next() {
+		var ooo models.OutOfOffice
+		if err := rows.Scan(&ooo.ID, &ooo.UserID, &ooo.Start, &ooo.End, &ooo.Message); err != nil {
+			r.log.Error("Error get out of office", zap.Error(err))
+			return nil, fmt.Errorf("failed to get out of office: %w", err)
+		}
+		periods = append(periods, ooo)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get out of office", zap.Error(err))
+		return nil, fmt.Errorf("failed to get out of office: %w", err)
+	}
+	return periods, nil
+}
+
+// GetTimeZones returns the stored time zones of the users by user id. Users without a
+// profile are left out.
+func (r *Repository) GetTimeZones(ctx context.Context, userIDs []int64) (map[int64]string, error) {
+	r.log.Debug("Getting time zones", zap.Int64s("user_ids", userIDs))
+	rows, err := r.db.Query(ctx, getTimeZonesQuery, userIDs)
+	if err != nil {
+		r.log.Error("Error get time zones", zap.Error(err))
+		return nil, fmt.Errorf("failed to get time zones: %w", err)
+	}
+	defer rows.Close()
+	zones := make(map[int64]string, len(userIDs))
+	for rows.Next() {
+		var userID int64
+		var tz string
+		if err := rows.Scan(&userID, &tz); err != nil {
+			r.log.Error("Error get time zones", zap.Error(err))
+			return nil, fmt.Errorf("failed to get time zones: %w", err)
+		}
+		zones[userID] = tz
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get time zones", zap.Error(err))
+		return nil, fmt.Errorf("failed to get time zones: %w", err)
+	}
+	return zones, nil
+}
+
+// GetSchedules returns the stored time zone, working hours and the out-of-office periods
+// overlapping [from, to) of the given users. Users without a profile or working hours are
+// returned with empty fields.
+func (r *Repository) GetSchedules(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Schedule, error) {
+	r.log.Debug("Getting schedules", zap.Int64s("user_ids", userIDs))
+	schedules := make([]models.Schedule, len(userIDs))
+	index := make(map[int64]int, len(userIDs))
+	for i, id := range userIDs {
+		schedules[i].UserID = id
+		index[id] = i
+	}
+
+	zones, err := r.GetTimeZones(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for id, tz := range zones {
+		schedules[index[id]].TimeZone = tz
+	}
+
+	rows, err := r.db.Query(ctx, getWorkingHours, userIDs)
+	if err != nil {
+		r.log.Error("Error get working hours", zap.Error(err))
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID int64
+		var wh models.WorkingHours
+		if err := rows.Scan(&userID, &wh.Weekday, &wh.StartMinute, &wh.EndMinute); err != nil {
+			r.log.Error("Error get working hours", zap.Error(err))
+			return nil, fmt.Errorf("failed to get working hours: %w", err)
+		}
+		s := &schedules[index[userID]]
+		s.WorkingHours = append(s.WorkingHours, wh)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get working hours", zap.Error(err))
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
+	}
+
+	periods, err := r.GetOutOfOffice(ctx, userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, ooo := range periods {
+		s := &schedules[index[ooo.UserID]]
+		s.OutOfOffice = append(s.OutOfOffice, ooo)
+	}
+	return schedules, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles_UpsertAndGet(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	_, err := repo.GetProfile(ctx, 1)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	vacation := time.Date(2099, 8, 1, 0, 0, 0, 0, time.UTC)
+	profile := &models.Profile{
+		UserID:   1,
+		Email:    "ann@example.com",
+		Name:     "Ann",
+		TimeZone: "Europe/Berlin",
+		WorkingHours: []models.WorkingHours{
+			{Weekday: time.Tuesday, StartMinute: 9 * 60, EndMinute: 17 * 60},
+			{Weekday: time.Monday, StartMinute: 10 * 60, EndMinute: 18 * 60},
+		},
+		OutOfOffice: []models.OutOfOffice{
+			{Start: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC)},
+			{Start: vacation, End: vacation.AddDate(0, 0, 14), Message: "Hiking"},
+		},
+	}
+	require.NoError(t, repo.UpsertProfile(ctx, profile))
+
+	got, err := repo.GetProfile(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "ann@example.com", got.Email)
+	require.Equal(t, "Europe/Berlin", got.TimeZone)
+	require.Equal(t, []models.WorkingHours{
+		{Weekday: time.Monday, StartMinute: 10 * 60, EndMinute: 18 * 60},
+		{Weekday: time.Tuesday, StartMinute: 9 * 60, EndMinute: 17 * 60},
+	}, got.WorkingHours)
+	// Periods that already ended are not listed.
+	require.Len(t, got.OutOfOffice, 1)
+	require.Equal(t, "Hiking", got.OutOfOffice[0].Message)
+	require.True(t, vacation.Equal(got.OutOfOffice[0].Start))
+
+	// Nil settings are kept, empty ones clear them.
+	require.NoError(t, repo.UpsertProfile(ctx, &models.Profile{UserID: 1, Email: "ann@example.org", TimeZone: "UTC"}))
+	got, err = repo.GetProfile(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "ann@example.org", got.Email)
+	require.Len(t, got.WorkingHours, 2)
+	require.Len(t, got.OutOfOffice, 1)
+
+	require.NoError(t, repo.UpsertProfile(ctx, &models.Profile{UserID: 1, TimeZone: "UTC",
+		WorkingHours: []models.WorkingHours{}, OutOfOffice: []models.OutOfOffice{}}))
+	got, err = repo.GetProfile(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, got.WorkingHours)
+	require.Empty(t, got.OutOfOffice)
+}
+
+func TestProfiles_EnablingNoDoubleBookingWithOverlaps(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Sync", date(2025, 6, 2), 9, 10)))
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Overlap", date(2025, 6, 2), 9, 11)))
+
+	err := repo.UpsertProfile(ctx, &models.Profile{UserID: 1, TimeZone: "UTC", NoDoubleBooking: true})
+	require.ErrorIs(t, err, models.ErrConflict)
+	_, err = repo.GetProfile(ctx, 1)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: 2, UserID: 1}))
+	require.NoError(t, repo.UpsertProfile(ctx, &models.Profile{UserID: 1, TimeZone: "UTC", NoDoubleBooking: true}))
+	got, err := repo.GetProfile(ctx, 1)
+	require.NoError(t, err)
+	require.True(t, got.NoDoubleBooking)
+}
+
+func TestProfiles_SchedulesAndOutOfOffice(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	june := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, repo.UpsertProfile(ctx, &models.Profile{
+		UserID:       1,
+		TimeZone:     "America/New_York",
+		WorkingHours: []models.WorkingHours{{Weekday: time.Friday, StartMinute: 8 * 60, EndMinute: 12 * 60}},
+		OutOfOffice: []models.OutOfOffice{
+			{Start: june(2), End: june(4)},
+			{Start: june(10), End: june(12)},
+		},
+	}))
+	require.NoError(t, repo.UpsertProfile(ctx, &models.Profile{
+		UserID:      2,
+		TimeZone:    "UTC",
+		OutOfOffice: []models.OutOfOffice{{Start: june(1), End: june(4), Message: "Conference"}},
+	}))
+
+	schedules, err := repo.GetSchedules(ctx, []int64{3, 1, 2}, june(3), june(10))
+	require.NoError(t, err)
+	require.Len(t, schedules, 3)
+	require.Equal(t, models.Schedule{UserID: 3}, schedules[0])
+	require.Equal(t, "America/New_York", schedules[1].TimeZone)
+	require.Equal(t, []models.WorkingHours{{Weekday: time.Friday, StartMinute: 8 * 60, EndMinute: 12 * 60}}, schedules[1].WorkingHours)
+	// The window is half-open: the period starting June 10 is outside.
+	require.Len(t, schedules[1].OutOfOffice, 1)
+	require.True(t, june(2).Equal(schedules[1].OutOfOffice[0].Start))
+	require.Empty(t, schedules[2].WorkingHours)
+	require.Len(t, schedules[2].OutOfOffice, 1)
+
+	periods, err := repo.GetOutOfOffice(ctx, []int64{1, 2}, june(2), june(11))
+	require.NoError(t, err)
+	require.Len(t, periods, 3)
+	require.Equal(t, []int64{1, 1, 2}, []int64{periods[0].UserID, periods[1].UserID, periods[2].UserID})
+	require.Equal(t, "Conference", periods[2].Message)
+
+	periods, err = repo.GetOutOfOffice(ctx, []int64{1, 2}, june(4), june(10))
+	require.NoError(t, err)
+	require.Empty(t, periods)
+}
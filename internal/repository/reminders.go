@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	createReminderQuery = `
+		INSERT INTO reminders (event_id, user_id, before_minutes)
+		SELECT id, user_id, $3 FROM calendar WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING id`
+	deleteReminderQuery = `DELETE FROM reminders WHERE id = $1 AND user_id = $2`
+	getRemindersQuery   = `SELECT id, event_id, user_id, before_minutes FROM reminders
+                                WHERE user_id = $1 AND ($2 = 0 OR event_id = $2)
+                                ORDER BY event_id, before_minutes DESC`
+	getEventsRemindersQuery = `SELECT r.id, r.event_id, r.user_id, r.before_minutes FROM reminders r
+                                JOIN unnest($1::bigint[], $2::bigint[]) AS k(user_id, event_id) USING (user_id, event_id)
+                                ORDER BY r.user_id, r.event_id, r.before_minutes DESC`
+	// A reminder is due once the event start minus the offset has passed; reminders backing
+	// off after a failed attempt wait for next_attempt_at, and failed ones are never picked again.
+	// SKIP LOCKED lets several instances poll concurrently without firing the same reminder twice.
+	dueRemindersQuery = `
+		SELECT r.id, r.event_id, r.user_id, r.before_minutes, r.delivered_channels, r.attempts, r.last_error,
+		       c.date, c.event, c.start_at, c.end_at, c.all_day
+		FROM reminders r
+		JOIN calendar c ON c.id = r.event_id
+		WHERE r.fired_at IS NULL AND r.failed_at IS NULL
+		  AND (r.next_attempt_at IS NULL OR r.next_attempt_at <= $1)
+		  AND c.deleted_at IS NULL
+		  AND c.start_at - r.before_minutes * INTERVAL '1 minute' <= $1
+		ORDER BY r.id
+		LIMIT $2
+		FOR UPDATE OF r SKIP LOCKED`
+	updateReminderQuery = `
+		UPDATE reminders
+		SET delivered_channels = $2, attempts = $3, next_attempt_at = $4, last_error = $5, fired_at = $6, failed_at = $7
+		WHERE id = $1`
+)
+
+func (r *Repository) CreateReminder(ctx context.Context, reminder *models.Reminder) error {
+	r.log.Debug("Creating reminder", zap.Any("reminder", reminder))
+	err := r.db.QueryRow(ctx, createReminderQuery,
+		reminder.EventID,
+		reminder.UserID,
+		int64(reminder.Before/time.Minute),
+	).Scan(&reminder.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		r.log.Debug("Event for reminder not found", zap.Int64("event_id", reminder.EventID))
+		return fmt.Errorf("event %d: %w", reminder.EventID, models.ErrNotFound)
+	}
+	if err != nil {
+		r.log.Error("Error create reminder", zap.Error(err))
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+	r.log.Debug("Created reminder", zap.Int64("id", reminder.ID))
+	return nil
+}
+
+func (r *Repository) DeleteReminder(ctx context.Context, reminder *models.Reminder) error {
+	r.log.Debug("Deleting reminder", zap.Any("reminder", reminder))
+	tag, err := r.db.Exec(ctx, deleteReminderQuery, reminder.ID, reminder.UserID)
+	if err != nil {
+		r.log.Error("Error delete reminder", zap.Error(err))
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("reminder %d: %w", reminder.ID, models.ErrNotFound)
+	}
+	r.log.Debug("Deleted reminder", zap.Int64("id", reminder.ID))
+	return nil
+}
+
+func (r *Repository) GetReminders(ctx context.Context, userID int64, eventID int64) ([]models.Reminder, error) {
+	r.log.Debug("Getting reminders", zap.Int64("user_id", userID), zap.Int64("event_id", eventID))
+	reminders, err := collectReminders(r.db.Query(ctx, getRemindersQuery, userID, eventID))
+	if err != nil {
+		r.log.Error("Error get reminders", zap.Error(err))
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// GetRemindersForEvents returns the reminders userIDs[i] set on eventIDs[i], for every i,
+// in one query.
+func (r *Repository) GetRemindersForEvents(ctx context.Context, userIDs, eventIDs []int64) ([]models.Reminder, error) {
+	r.log.Debug("Getting reminders for events", zap.Int64s("user_ids", userIDs), zap.Int64s("event_ids", eventIDs))
+	reminders, err := collectReminders(r.db.Query(ctx, getEventsRemindersQuery, userIDs, eventIDs))
+	if err != nil {
+		r.log.Error("Error get reminders", zap.Error(err))
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func collectReminders(rows pgx.Rows, err error) ([]models.Reminder, error) {
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	defer rows.Close()
+	var reminders []models.Reminder
+	for rows.Next() {
+		var rem models.Reminder
+		var minutes int64
+		if err := rows.Scan(&rem.ID, &rem.EventID, &rem.UserID, &minutes); err != nil {
+			return nil, fmt.Errorf("failed to get reminders: %w", err)
+		}
+		rem.Before = time.Duration(minutes) * time.Minute
+		reminders = append(reminders, rem)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// ProcessDueReminders locks up to limit due reminders, lets fn attempt each one and persists
+// the delivery state fn leaves behind. It returns how many reminders were handed to fn.
+func (r *Repository) ProcessDueReminders(ctx context.Context, now time.Time, limit int, fn func(ctx context.Context, d *models.DueReminder)) (int, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, dueRemindersQuery, now, limit)
+	if err != nil {
+		r.log.Error("Error get due reminders", zap.Error(err))
+		return 0, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+	var due []models.DueReminder
+	for rows.Next() {
+		rem := &models.Reminder{}
+		var minutes int64
+		ev := models.Event{}
+		d := models.DueReminder{Status: models.ReminderPending}
+		if err := rows.Scan(&rem.ID, &rem.EventID, &rem.UserID, &minutes, &d.Delivered, &d.Attempts, &d.LastError,
+			&ev.Date, &ev.Event, &ev.Start, &ev.End, &ev.AllDay); err != nil {
+			rows.Close()
+			r.log.Error("Error get due reminders", zap.Error(err))
+			return 0, fmt.Errorf("failed to get due reminders: %w", err)
+		}
+		rem.Before = time.Duration(minutes) * time.Minute
+		ev.ID = rem.EventID
+		ev.UserID = rem.UserID
+		d.Notification = models.Notification{
+			Type:     models.NotificationReminder,
+			UserID:   rem.UserID,
+			Event:    ev,
+			Reminder: rem,
+		}
+		due = append(due, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get due reminders", zap.Error(err))
+		return 0, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+
+	for i := range due {
+		d := &due[i]
+		fn(ctx, d)
+		var nextAttemptAt, firedAt, failedAt *time.Time
+		switch d.Status {
+		case models.ReminderFired:
+			firedAt = &now
+		case models.ReminderFailed:
+			failedAt = &now
+		default:
+			nextAttemptAt = &d.NextAttemptAt
+		}
+		// A nil slice would be sent as NULL.
+		if d.Delivered == nil {
+			d.Delivered = []string{}
+		}
+		_, err := tx.Exec(ctx, updateReminderQuery, d.Notification.Reminder.ID, d.Delivered, d.Attempts, nextAttemptAt, d.LastError, firedAt, failedAt)
+		if err != nil {
+			r.log.Error("Error update reminder", zap.Error(err))
+			return 0, fmt.Errorf("failed to update reminder: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("Error commit transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(due), nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminders_CreateGetDelete(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	ev := timedEvent(1, "Standup", date(2025, 6, 2), 9, 10)
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	other := timedEvent(1, "Review", date(2025, 6, 3), 9, 10)
+	require.NoError(t, repo.CreateEvent(ctx, other))
+
+	short := &models.Reminder{EventID: ev.ID, UserID: 1, Before: 10 * time.Minute}
+	long := &models.Reminder{EventID: ev.ID, UserID: 1, Before: time.Hour}
+	later := &models.Reminder{EventID: other.ID, UserID: 1, Before: 5 * time.Minute}
+	for _, rem := range []*models.Reminder{short, long, later} {
+		require.NoError(t, repo.CreateReminder(ctx, rem))
+		require.NotZero(t, rem.ID)
+	}
+
+	err := repo.CreateReminder(ctx, &models.Reminder{EventID: ev.ID, UserID: 2, Before: time.Minute})
+	require.ErrorIs(t, err, models.ErrNotFound)
+	err = repo.CreateReminder(ctx, &models.Reminder{EventID: 99, UserID: 1, Before: time.Minute})
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	all, err := repo.GetReminders(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, []models.Reminder{*long, *short, *later}, all)
+	forEvent, err := repo.GetReminders(ctx, 1, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Reminder{*long, *short}, forEvent)
+	forEvents, err := repo.GetRemindersForEvents(ctx, []int64{1, 1, 2, 1}, []int64{ev.ID, other.ID, ev.ID, 99})
+	require.NoError(t, err)
+	require.Equal(t, all, forEvents)
+	forEvents, err = repo.GetRemindersForEvents(ctx, []int64{2}, []int64{ev.ID})
+	require.NoError(t, err)
+	require.Empty(t, forEvents)
+
+	require.ErrorIs(t, repo.DeleteReminder(ctx, &models.Reminder{ID: short.ID, UserID: 2}), models.ErrNotFound)
+	require.NoError(t, repo.DeleteReminder(ctx, &models.Reminder{ID: short.ID, UserID: 1}))
+	require.ErrorIs(t, repo.DeleteReminder(ctx, &models.Reminder{ID: short.ID, UserID: 1}), models.ErrNotFound)
+	forEvent, err = repo.GetReminders(ctx, 1, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Reminder{*long}, forEvent)
+}
+
+func TestReminders_ProcessDue(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	ev := timedEvent(1, "Standup", date(2025, 6, 2), 9, 10)
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	rem := &models.Reminder{EventID: ev.ID, UserID: 1, Before: 30 * time.Minute}
+	require.NoError(t, repo.CreateReminder(ctx, rem))
+
+	var fired []models.Notification
+	collect := func(ctx context.Context, d *models.DueReminder) {
+		fired = append(fired, d.Notification)
+		d.Attempts++
+		d.Status = models.ReminderFired
+	}
+	due := ev.Start.Add(-30 * time.Minute)
+
+	n, err := repo.ProcessDueReminders(ctx, due.Add(-time.Second), 10, collect)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// A failed attempt backs the reminder off until its next attempt.
+	n, err = repo.ProcessDueReminders(ctx, due, 10, func(ctx context.Context, d *models.DueReminder) {
+		require.Zero(t, d.Attempts)
+		require.Empty(t, d.Delivered)
+		d.Delivered = append(d.Delivered, "webhook")
+		d.Attempts++
+		d.LastError = "smtp down"
+		d.NextAttemptAt = due.Add(time.Minute)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	n, err = repo.ProcessDueReminders(ctx, due.Add(time.Second), 10, collect)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	n, err = repo.ProcessDueReminders(ctx, due.Add(time.Minute), 10, func(ctx context.Context, d *models.DueReminder) {
+		require.Equal(t, 1, d.Attempts)
+		require.Equal(t, []string{"webhook"}, d.Delivered)
+		require.Equal(t, "smtp down", d.LastError)
+		collect(ctx, d)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, fired, 1)
+	require.Equal(t, models.NotificationReminder, fired[0].Type)
+	require.Equal(t, int64(1), fired[0].UserID)
+	require.Equal(t, rem.ID, fired[0].Reminder.ID)
+	require.Equal(t, "Standup", fired[0].Event.Event)
+	require.True(t, ev.Start.Equal(fired[0].Event.Start))
+
+	n, err = repo.ProcessDueReminders(ctx, due.Add(time.Hour), 10, collect)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// Failed reminders are never picked up again.
+	failed := &models.Reminder{EventID: ev.ID, UserID: 1, Before: 10 * time.Minute}
+	require.NoError(t, repo.CreateReminder(ctx, failed))
+	n, err = repo.ProcessDueReminders(ctx, due.Add(time.Hour), 10, func(ctx context.Context, d *models.DueReminder) {
+		d.Status = models.ReminderFailed
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	n, err = repo.ProcessDueReminders(ctx, due.Add(2*time.Hour), 10, collect)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// replicaSet spreads reads over the replicas and remembers recent writes by user, so that
+// a user keeps reading from the primary until the replicas have caught up with their write.
+// The memory is per process: another instance may still serve that user from a replica.
+type replicaSet struct {
+	pools  []*pgxpool.Pool
+	next   atomic.Uint64
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	writes    map[int64]time.Time
+	lastSweep time.Time
+}
+
+func newReplicaSet(pools []*pgxpool.Pool, window time.Duration) *replicaSet {
+	return &replicaSet{pools: pools, window: window, now: time.Now, writes: make(map[int64]time.Time)}
+}
+
+// wrote records a committed write seen by the users. The window starts at the commit, as
+// replication lag does. Entries older than the window are swept at most once per window.
+func (s *replicaSet) wrote(userIDs ...int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for _, id := range userIDs {
+		s.writes[id] = now
+	}
+	if now.Sub(s.lastSweep) < s.window {
+		return
+	}
+	for id, at := range s.writes {
+		if now.Sub(at) >= s.window {
+			delete(s.writes, id)
+		}
+	}
+	s.lastSweep = now
+}
+
+// pick returns the replica to read for the users from, or nil when the read has to go to
+// the primary because there are no replicas or one of the users wrote within the window.
+func (s *replicaSet) pick(userIDs []int64) *pgxpool.Pool {
+	if s == nil || len(s.pools) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	now := s.now()
+	for _, id := range userIDs {
+		if at, ok := s.writes[id]; ok && now.Sub(at) < s.window {
+			s.mu.Unlock()
+			return nil
+		}
+	}
+	s.mu.Unlock()
+	return s.pools[s.next.Add(1)%uint64(len(s.pools))]
+}
+
+func (s *replicaSet) close() {
+	if s == nil {
+		return
+	}
+	for _, pool := range s.pools {
+		pool.Close()
+	}
+}
+
+// read runs fn on a replica when the users may be served from one, falling back to the
+// primary when the replica fails.
+func (r *Repository) read(ctx context.Context, userIDs []int64, fn func(q querier) error) error {
+	if replica := r.replicas.pick(userIDs); replica != nil {
+		err := fn(replica)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		r.log.Warn("Replica read failed, retrying on primary", zap.Error(err))
+	}
+	return fn(r.db)
+}
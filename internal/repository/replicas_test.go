@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestReplicaSet_RoundRobinAndReadYourWrites(t *testing.T) {
+	a, b := &pgxpool.Pool{}, &pgxpool.Pool{}
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	s := newReplicaSet([]*pgxpool.Pool{a, b}, 5*time.Second)
+	s.now = func() time.Time { return now }
+
+	first, second := s.pick([]int64{1}), s.pick([]int64{1})
+	require.NotSame(t, first, second)
+	require.Same(t, first, s.pick([]int64{1}))
+
+	s.wrote(1)
+	require.Nil(t, s.pick([]int64{1}))
+	require.Nil(t, s.pick([]int64{2, 1}))
+	require.NotNil(t, s.pick([]int64{2}))
+
+	now = now.Add(5 * time.Second)
+	require.NotNil(t, s.pick([]int64{1}))
+}
+
+func TestReplicaSet_SweepsOldWrites(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	s := newReplicaSet([]*pgxpool.Pool{{}}, time.Second)
+	s.now = func() time.Time { return now }
+	s.wrote(1)
+	s.wrote(2)
+	require.Len(t, s.writes, 2)
+
+	now = now.Add(2 * time.Second)
+	s.wrote(3)
+	require.Len(t, s.writes, 1)
+}
+
+func TestReplicaSet_WithoutReplicas(t *testing.T) {
+	var s *replicaSet
+	s.wrote(1)
+	require.Nil(t, s.pick([]int64{1}))
+	require.Nil(t, newReplicaSet(nil, time.Second).pick([]int64{1}))
+}
+
+func TestRepository_ReadsFallBackToPrimary(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	// Nothing listens on port 1, so every replica read fails.
+	replica, err := pgxpool.New(ctx, "postgres://postgres@127.0.0.1:1/calendar?connect_timeout=1")
+	require.NoError(t, err)
+	defer replica.Close()
+	core, logs := observer.New(zapcore.WarnLevel)
+	repo.log = zap.New(core)
+	repo.replicas = newReplicaSet([]*pgxpool.Pool{replica}, time.Minute)
+
+	events, err := repo.GetEventsInRange(ctx, []int64{1}, date(2025, 6, 2), date(2025, 6, 3))
+	require.NoError(t, err)
+	require.Empty(t, events)
+	require.Equal(t, 1, logs.FilterMessage("Replica read failed, retrying on primary").Len())
+
+	// After a write the organizer and the invitees read from the primary right away.
+	ev := timedEvent(1, "Sync", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}}
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	for _, user := range []int64{1, 2} {
+		events, err = repo.GetEventsForDay(ctx, timedEvent(user, "", date(2025, 6, 2), 0, 0))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+	}
+	require.Equal(t, 1, logs.FilterMessage("Replica read failed, retrying on primary").Len())
+}
@@ -0,0 +1,372 @@
+// Package repotest is a conformance suite for service.CalendarRepository implementations.
+// Every storage backend runs it so that they stay interchangeable.
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RunCalendarRepository runs the suite. newRepo must return an empty repository for every call.
+func RunCalendarRepository(t *testing.T, newRepo func(t *testing.T) service.CalendarRepository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo service.CalendarRepository)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"GetMissing", testGetMissing},
+		{"UpdateByID", testUpdateByID},
+		{"UpdateOnlyByID", testUpdateOnlyByID},
+		{"DeleteByIDAndDate", testDeleteByIDAndDate},
+		{"DayView", testDayView},
+		{"WeekViewIsHalfOpen", testWeekViewIsHalfOpen},
+		{"MonthViewIsHalfOpen", testMonthViewIsHalfOpen},
+		{"ViewsIncludeInvitations", testViewsIncludeInvitations},
+		{"ReplaceAttendees", testReplaceAttendees},
+		{"GetAttendees", testGetAttendees},
+		{"RespondToEvent", testRespondToEvent},
+		{"UnknownResource", testUnknownResource},
+		{"EventsInRange", testEventsInRange},
+		{"EventsInRangeIncludeInvitations", testEventsInRangeIncludeInvitations},
+		{"NoOutOfOffice", testNoOutOfOffice},
+		{"NoTimeZones", testNoTimeZones},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t))
+		})
+	}
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// timed returns a normalized event of user on day from hour start to hour end.
+func timed(user int64, name string, day time.Time, start, end int) *models.Event {
+	return &models.Event{
+		UserID:       user,
+		Event:        name,
+		Date:         day,
+		Start:        day.Add(time.Duration(start) * time.Hour),
+		End:          day.Add(time.Duration(end) * time.Hour),
+		Transparency: models.TransparencyOpaque,
+		Visibility:   models.VisibilityDefault,
+	}
+}
+
+func create(t *testing.T, repo service.CalendarRepository, ev *models.Event) *models.Event {
+	t.Helper()
+	require.NoError(t, repo.CreateEvent(context.Background(), ev))
+	require.NotZero(t, ev.ID)
+	return ev
+}
+
+func names(events []models.Event) []string {
+	out := []string{}
+	for _, ev := range events {
+		out = append(out, ev.Event)
+	}
+	return out
+}
+
+func testCreateAndGet(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	ev := timed(1, "Sync", date(2025, 6, 2), 9, 10)
+	ev.RRule = "FREQ=WEEKLY;COUNT=3"
+	create(t, repo, ev)
+	other := create(t, repo, timed(1, "Review", date(2025, 6, 2), 11, 12))
+	require.NotEqual(t, ev.ID, other.ID)
+
+	got, err := repo.GetEvent(ctx, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, ev.ID, got.ID)
+	require.Equal(t, int64(1), got.UserID)
+	require.Equal(t, "Sync", got.Event)
+	require.True(t, got.Date.Equal(date(2025, 6, 2)))
+	require.True(t, got.Start.Equal(ev.Start))
+	require.True(t, got.End.Equal(ev.End))
+	require.Equal(t, models.TransparencyOpaque, got.Transparency)
+	require.Equal(t, models.VisibilityDefault, got.Visibility)
+	require.Equal(t, "FREQ=WEEKLY;COUNT=3", got.RRule)
+	require.Zero(t, got.Sequence)
+	require.Empty(t, got.Attendees)
+}
+
+func testGetMissing(t *testing.T, repo service.CalendarRepository) {
+	_, err := repo.GetEvent(context.Background(), 424242)
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func testUpdateByID(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	ev := create(t, repo, timed(1, "Sync", date(2025, 6, 2), 9, 10))
+	untouched := create(t, repo, timed(1, "Review", date(2025, 6, 2), 11, 12))
+
+	moved := timed(1, "Sync moved", date(2025, 6, 3), 14, 15)
+	moved.ID = ev.ID
+	require.NoError(t, repo.UpdateEvent(ctx, moved))
+	got, err := repo.GetEvent(ctx, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Sync moved", got.Event)
+	require.True(t, got.Date.Equal(date(2025, 6, 3)))
+	require.True(t, got.Start.Equal(moved.Start))
+	require.Equal(t, 1, got.Sequence)
+
+	got, err = repo.GetEvent(ctx, untouched.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Review", got.Event)
+	require.Zero(t, got.Sequence)
+
+	// Another user's id matches nothing and is not an error.
+	foreign := timed(2, "Hijack", date(2025, 6, 3), 14, 15)
+	foreign.ID = ev.ID
+	require.NoError(t, repo.UpdateEvent(ctx, foreign))
+	got, err = repo.GetEvent(ctx, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Sync moved", got.Event)
+}
+
+func testUpdateOnlyByID(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	a := create(t, repo, timed(1, "A", date(2025, 6, 2), 9, 10))
+	b := create(t, repo, timed(1, "B", date(2025, 6, 5), 9, 10))
+
+	// An update without an id matches nothing.
+	require.NoError(t, repo.UpdateEvent(ctx, timed(1, "Renamed", date(2025, 6, 9), 8, 9)))
+	// Another user's id matches nothing either.
+	foreign := timed(2, "Renamed", date(2025, 6, 9), 8, 9)
+	foreign.ID = a.ID
+	require.NoError(t, repo.UpdateEvent(ctx, foreign))
+	for _, ev := range []*models.Event{a, b} {
+		got, err := repo.GetEvent(ctx, ev.ID)
+		require.NoError(t, err)
+		require.Equal(t, ev.Event, got.Event)
+		require.Equal(t, 0, got.Sequence)
+	}
+}
+
+func testDeleteByIDAndDate(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	a := create(t, repo, timed(1, "A", date(2025, 6, 2), 9, 10))
+	b := create(t, repo, timed(1, "B", date(2025, 6, 2), 11, 12))
+	c := create(t, repo, timed(1, "C", date(2025, 6, 3), 9, 10))
+	other := create(t, repo, timed(2, "D", date(2025, 6, 2), 9, 10))
+
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: c.ID, UserID: 1}))
+	_, err := repo.GetEvent(ctx, c.ID)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{UserID: 1, Date: date(2025, 6, 2)}))
+	for _, id := range []int64{a.ID, b.ID} {
+		_, err := repo.GetEvent(ctx, id)
+		require.ErrorIs(t, err, models.ErrNotFound)
+	}
+	_, err = repo.GetEvent(ctx, other.ID)
+	require.NoError(t, err)
+
+	// Deleting nothing is not an error.
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: other.ID, UserID: 1}))
+}
+
+func testDayView(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	create(t, repo, timed(1, "Leap", date(2024, 2, 29), 9, 10))
+	create(t, repo, timed(1, "Next", date(2024, 3, 1), 9, 10))
+	create(t, repo, timed(2, "Other", date(2024, 2, 29), 9, 10))
+
+	events, err := repo.GetEventsForDay(ctx, &models.Event{UserID: 1, Date: date(2024, 2, 29)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Leap"}, names(events))
+
+	events, err = repo.GetEventsForDay(ctx, &models.Event{UserID: 1, Date: date(2024, 2, 28)})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func testWeekViewIsHalfOpen(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	create(t, repo, timed(1, "Before", date(2025, 6, 1), 9, 10))
+	create(t, repo, timed(1, "First", date(2025, 6, 2), 9, 10))
+	create(t, repo, timed(1, "Last", date(2025, 6, 8), 9, 10))
+	create(t, repo, timed(1, "After", date(2025, 6, 9), 9, 10))
+
+	events, err := repo.GetEventsForWeek(ctx, &models.Event{UserID: 1, Date: date(2025, 6, 2)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"First", "Last"}, names(events))
+}
+
+func testMonthViewIsHalfOpen(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	create(t, repo, timed(1, "Mid", date(2025, 6, 15), 9, 10))
+	create(t, repo, timed(1, "Last", date(2025, 6, 30), 9, 10))
+	create(t, repo, timed(1, "Boundary", date(2025, 7, 1), 9, 10))
+	events, err := repo.GetEventsForMonth(ctx, &models.Event{UserID: 1, Date: date(2025, 6, 1)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Mid", "Last"}, names(events))
+
+	// January 31 plus a month is February 28, the first day left out.
+	create(t, repo, timed(1, "Start", date(2025, 1, 31), 9, 10))
+	create(t, repo, timed(1, "Before", date(2025, 2, 27), 9, 10))
+	create(t, repo, timed(1, "Clamped", date(2025, 2, 28), 9, 10))
+	events, err = repo.GetEventsForMonth(ctx, &models.Event{UserID: 1, Date: date(2025, 1, 31)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Start", "Before"}, names(events))
+}
+
+func testViewsIncludeInvitations(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	ev := timed(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}}
+	create(t, repo, ev)
+
+	events, err := repo.GetEventsForDay(ctx, &models.Event{UserID: 2, Date: date(2025, 6, 2)})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, int64(1), events[0].UserID)
+	require.Equal(t, []models.Attendee{{EventID: ev.ID, UserID: 2, Status: models.AttendeeNeedsAction}}, events[0].Attendees)
+
+	events, err = repo.GetEventsForDay(ctx, &models.Event{UserID: 3, Date: date(2025, 6, 2)})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func testReplaceAttendees(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	ev := timed(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}, {UserID: 3, Status: models.AttendeeDeclined, Comment: "Away"}, {Email: "guest@example.com"}}
+	create(t, repo, ev)
+	require.NoError(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{UserID: 2, Status: models.AttendeeAccepted}))
+
+	update := timed(1, "Planning", date(2025, 6, 2), 9, 10)
+	update.ID = ev.ID
+	update.Attendees = []models.Attendee{{Email: "guest@example.com"}, {UserID: 4}, {UserID: 2}}
+	require.NoError(t, repo.UpdateEvent(ctx, update))
+	got, err := repo.GetEvent(ctx, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Attendee{
+		{EventID: ev.ID, UserID: 2, Status: models.AttendeeAccepted},
+		{EventID: ev.ID, Email: "guest@example.com", Status: models.AttendeeNeedsAction},
+		{EventID: ev.ID, UserID: 4, Status: models.AttendeeNeedsAction},
+	}, got.Attendees)
+
+	// A nil list keeps the attendees, an empty one clears them.
+	update.Attendees = nil
+	require.NoError(t, repo.UpdateEvent(ctx, update))
+	got, err = repo.GetEvent(ctx, ev.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Attendees, 3)
+
+	update.Attendees = []models.Attendee{}
+	require.NoError(t, repo.UpdateEvent(ctx, update))
+	got, err = repo.GetEvent(ctx, ev.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.Attendees)
+}
+
+func testGetAttendees(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	ev := timed(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}, {Email: "guest@example.com"}}
+	create(t, repo, ev)
+	plain := create(t, repo, timed(1, "Focus", date(2025, 6, 2), 11, 12))
+	stored, err := repo.GetEvent(ctx, ev.ID)
+	require.NoError(t, err)
+
+	attendees, err := repo.GetAttendees(ctx, []int64{ev.ID, plain.ID, 99})
+	require.NoError(t, err)
+	require.Equal(t, map[int64][]models.Attendee{ev.ID: stored.Attendees}, attendees)
+}
+
+func testRespondToEvent(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	ev := timed(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}, {Email: "guest@example.com"}}
+	create(t, repo, ev)
+
+	require.NoError(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{UserID: 2, Status: models.AttendeeTentative}))
+	require.NoError(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{Email: "guest@example.com", Status: models.AttendeeDeclined}))
+	got, err := repo.GetEvent(ctx, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.AttendeeTentative, got.Attendees[0].Status)
+	require.Equal(t, models.AttendeeDeclined, got.Attendees[1].Status)
+
+	err = repo.RespondToEvent(ctx, ev.ID, models.Attendee{UserID: 3, Status: models.AttendeeAccepted})
+	require.ErrorIs(t, err, models.ErrNotFound)
+	err = repo.RespondToEvent(ctx, 424242, models.Attendee{UserID: 2, Status: models.AttendeeAccepted})
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func testUnknownResource(t *testing.T, repo service.CalendarRepository) {
+	ev := timed(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{ResourceID: 424242}}
+	err := repo.CreateEvent(context.Background(), ev)
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func testEventsInRange(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	day := date(2025, 6, 2)
+	create(t, repo, timed(2, "Second user", day, 10, 11))
+	create(t, repo, timed(1, "Overlap", day, 8, 10))
+	create(t, repo, timed(1, "Adjacent", day, 12, 13))
+	create(t, repo, timed(1, "Earlier", day, 7, 9))
+	create(t, repo, timed(3, "Other user", day, 10, 11))
+	series := timed(1, "Series", date(2025, 5, 1), 9, 10)
+	series.RRule = "FREQ=DAILY"
+	create(t, repo, series)
+	create(t, repo, timed(1, "Later series", date(2025, 7, 1), 9, 10))
+
+	events, err := repo.GetEventsInRange(ctx, []int64{1, 2}, day.Add(9*time.Hour), day.Add(12*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Series", "Overlap", "Second user"}, names(events))
+
+	events, err = repo.GetEventsInRange(ctx, []int64{2}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Second user"}, names(events))
+
+	events, err = repo.GetEventsInRange(ctx, []int64{4}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func testEventsInRangeIncludeInvitations(t *testing.T, repo service.CalendarRepository) {
+	ctx := context.Background()
+	day := date(2025, 6, 2)
+	ev := timed(1, "Planning", day, 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}, {UserID: 3}}
+	create(t, repo, ev)
+	require.NoError(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{UserID: 2, Status: models.AttendeeAccepted}))
+	require.NoError(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{UserID: 3, Status: models.AttendeeDeclined}))
+
+	events, err := repo.GetEventsInRange(ctx, []int64{2}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Planning"}, names(events))
+
+	// Declined invitations and events organized by both are returned once.
+	events, err = repo.GetEventsInRange(ctx, []int64{1, 2, 3}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Planning"}, names(events))
+
+	events, err = repo.GetEventsInRange(ctx, []int64{3}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func testNoOutOfOffice(t *testing.T, repo service.CalendarRepository) {
+	periods, err := repo.GetOutOfOffice(context.Background(), []int64{1}, date(2025, 6, 1), date(2025, 7, 1))
+	require.NoError(t, err)
+	require.Empty(t, periods)
+}
+
+func testNoTimeZones(t *testing.T, repo service.CalendarRepository) {
+	zones, err := repo.GetTimeZones(context.Background(), []int64{1})
+	require.NoError(t, err)
+	require.Empty(t, zones)
+}
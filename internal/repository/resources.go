@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	resourceColumns     = `id, kind, name, capacity, attributes`
+	createResourceQuery = `INSERT INTO resources (kind, name, capacity, attributes) VALUES ($1, $2, $3, $4) RETURNING id`
+	deleteResourceQuery = `DELETE FROM resources WHERE id = $1`
+	getResourcesQuery   = `SELECT ` + resourceColumns + ` FROM resources
+                                WHERE ($1 = '' OR kind = $1) AND capacity >= $2 AND attributes @> $3
+                                ORDER BY id`
+	// Locking the resources serializes concurrent bookings of the same resource.
+	lockResourcesQuery = `SELECT ` + resourceColumns + ` FROM resources WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	// Accepted bookings overlapping the window; recurring series are expanded by the caller.
+	getBookingsQuery = `SELECT a.resource_id, c.id, c.user_id, c.date, c.event, c.start_at, c.end_at, c.all_day,
+                                       c.transparency, c.visibility, c.rrule, c.sequence
+                                FROM event_attendees a
+                                JOIN calendar c ON c.id = a.event_id
+                                WHERE a.resource_id = ANY($1)
+                                  AND a.status = 'accepted'
+                                  AND a.event_id <> $4
+                                  AND c.deleted_at IS NULL
+                                  AND (c.during && tstzrange($2, $3) OR (c.rrule <> '' AND c.start_at < $3))
+                                ORDER BY a.resource_id, c.start_at`
+	setResourceStatusQuery = `UPDATE event_attendees SET status = $3, responded_at = now()
+                                WHERE event_id = $1 AND resource_id = $2 AND status <> $3`
+)
+
+func (r *Repository) CreateResource(ctx context.Context, resource *models.Resource) error {
+	r.log.Debug("Creating resource", zap.String("kind", resource.Kind), zap.String("name", resource.Name))
+	err := r.db.QueryRow(ctx, createResourceQuery, resource.Kind, resource.Name, resource.Capacity, resource.Attributes).Scan(&resource.ID)
+	if err != nil {
+		r.log.Error("Error create resource", zap.Error(err))
+		return fmt.Errorf("failed to create resource: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteResource(ctx context.Context, id int64) error {
+	r.log.Debug("Deleting resource", zap.Int64("id", id))
+	tag, err := r.db.Exec(ctx, deleteResourceQuery, id)
+	if err != nil {
+		r.log.Error("Error delete resource", zap.Error(err))
+		return fmt.Errorf("failed to delete resource: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("resource %d: %w", id, models.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) GetResources(ctx context.Context, filter models.ResourceFilter) ([]models.Resource, error) {
+	r.log.Debug("Getting resources", zap.String("kind", filter.Kind), zap.Int("min_capacity", filter.MinCapacity))
+	attributes := filter.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	resources, err := collectResources(r.db.Query(ctx, getResourcesQuery, filter.Kind, filter.MinCapacity, attributes))
+	if err != nil {
+		r.log.Error("Error get resources", zap.Error(err))
+		return nil, fmt.Errorf("failed to get resources: %w", err)
+	}
+	return resources, nil
+}
+
+// GetBookings returns the accepted bookings of the resources overlapping [from, to), by resource.
+func (r *Repository) GetBookings(ctx context.Context, resourceIDs []int64, from, to time.Time) (map[int64][]models.Event, error) {
+	r.log.Debug("Getting bookings", zap.Int64s("resource_ids", resourceIDs), zap.Time("from", from), zap.Time("to", to))
+	bookings, err := collectBookings(r.db.Query(ctx, getBookingsQuery, resourceIDs, from, to, int64(0)))
+	if err != nil {
+		r.log.Error("Error get bookings", zap.Error(err))
+		return nil, fmt.Errorf("failed to get bookings: %w", err)
+	}
+	return bookings, nil
+}
+
+// BookResources settles the status of every resource invited to the event. decide receives the
+// event, its resources and their other accepted bookings in [from, to) and returns a status per
+// resource. The resources stay locked meanwhile, so two events are never both accepted for a slot.
+func (r *Repository) BookResources(ctx context.Context, eventID int64, from, to time.Time,
+	decide func(event models.Event, resources []models.Resource, bookings map[int64][]models.Event) map[int64]string) (*models.Event, error) {
+	r.log.Debug("Booking resources", zap.Int64("event_id", eventID))
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	events, err := collectEvents(tx.Query(ctx, getEventQuery, eventID))
+	if err == nil && len(events) == 0 {
+		return nil, fmt.Errorf("event %d: %w", eventID, models.ErrNotFound)
+	}
+	if err == nil {
+		err = loadAttendees(ctx, tx, events)
+	}
+	if err != nil {
+		r.log.Error("Error get event", zap.Error(err))
+		return nil, fmt.Errorf("failed to book resources: %w", err)
+	}
+	event := events[0]
+	var resourceIDs []int64
+	for _, a := range event.Attendees {
+		if a.ResourceID != 0 {
+			resourceIDs = append(resourceIDs, a.ResourceID)
+		}
+	}
+	if len(resourceIDs) == 0 {
+		return &event, nil
+	}
+	resources, err := collectResources(tx.Query(ctx, lockResourcesQuery, resourceIDs))
+	if err != nil {
+		r.log.Error("Error lock resources", zap.Error(err))
+		return nil, fmt.Errorf("failed to book resources: %w", err)
+	}
+	bookings, err := collectBookings(tx.Query(ctx, getBookingsQuery, resourceIDs, from, to, eventID))
+	if err != nil {
+		r.log.Error("Error get bookings", zap.Error(err))
+		return nil, fmt.Errorf("failed to book resources: %w", err)
+	}
+
+	changed := false
+	for resourceID, status := range decide(event, resources, bookings) {
+		tag, err := tx.Exec(ctx, setResourceStatusQuery, eventID, resourceID, status)
+		if err != nil {
+			r.log.Error("Error set resource status", zap.Error(err))
+			return nil, fmt.Errorf("failed to book resources: %w", err)
+		}
+		changed = changed || tag.RowsAffected() > 0
+	}
+	if !changed {
+		return &event, tx.Commit(ctx)
+	}
+	before := event
+	events[0].Attendees = nil
+	if err := loadAttendees(ctx, tx, events); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return nil, err
+	}
+	event = events[0]
+	if err := writeOutbox(ctx, tx, models.NotificationEventResponded, event); err != nil {
+		r.log.Error("Error write outbox", zap.Error(err))
+		return nil, err
+	}
+	if err := recordChange(ctx, tx, models.AuditBook, models.AuditSystem, eventID, &before, &event); err != nil {
+		r.log.Error("Error record change", zap.Error(err))
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	r.replicas.wrote(viewerIDs(&event)...)
+	return &event, nil
+}
+
+func collectResources(rows pgx.Rows, err error) ([]models.Resource, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var resources []models.Resource
+	for rows.Next() {
+		var res models.Resource
+		if err := rows.Scan(&res.ID, &res.Kind, &res.Name, &res.Capacity, &res.Attributes); err != nil {
+			return nil, err
+		}
+		resources = append(resources, res)
+	}
+	return resources, rows.Err()
+}
+
+func collectBookings(rows pgx.Rows, err error) (map[int64][]models.Event, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	bookings := make(map[int64][]models.Event)
+	for rows.Next() {
+		var resourceID int64
+		var ev models.Event
+		if err := rows.Scan(&resourceID, &ev.ID, &ev.UserID, &ev.Date, &ev.Event, &ev.Start, &ev.End, &ev.AllDay,
+			&ev.Transparency, &ev.Visibility, &ev.RRule, &ev.Sequence); err != nil {
+			return nil, err
+		}
+		bookings[resourceID] = append(bookings[resourceID], ev)
+	}
+	return bookings, rows.Err()
+}
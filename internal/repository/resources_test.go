@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResources_CreateFilterDelete(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	small := &models.Resource{Kind: models.ResourceRoom, Name: "Cell", Capacity: 4, Attributes: map[string]string{"floor": "1"}}
+	large := &models.Resource{Kind: models.ResourceRoom, Name: "Hall", Capacity: 40, Attributes: map[string]string{"floor": "2", "video": "yes"}}
+	beamer := &models.Resource{Kind: models.ResourceProjector, Name: "Beamer", Attributes: map[string]string{}}
+	for _, res := range []*models.Resource{small, large, beamer} {
+		require.NoError(t, repo.CreateResource(ctx, res))
+		require.NotZero(t, res.ID)
+	}
+
+	all, err := repo.GetResources(ctx, models.ResourceFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []models.Resource{*small, *large, *beamer}, all)
+
+	rooms, err := repo.GetResources(ctx, models.ResourceFilter{Kind: models.ResourceRoom, MinCapacity: 5})
+	require.NoError(t, err)
+	require.Equal(t, []models.Resource{*large}, rooms)
+
+	video, err := repo.GetResources(ctx, models.ResourceFilter{Attributes: map[string]string{"video": "yes"}})
+	require.NoError(t, err)
+	require.Equal(t, []models.Resource{*large}, video)
+
+	none, err := repo.GetResources(ctx, models.ResourceFilter{Kind: models.ResourceCar})
+	require.NoError(t, err)
+	require.Empty(t, none)
+
+	require.NoError(t, repo.DeleteResource(ctx, beamer.ID))
+	require.ErrorIs(t, repo.DeleteResource(ctx, beamer.ID), models.ErrNotFound)
+}
+
+func TestResources_Booking(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	room := &models.Resource{Kind: models.ResourceRoom, Name: "Hall", Capacity: 40, Attributes: map[string]string{}}
+	require.NoError(t, repo.CreateResource(ctx, room))
+
+	first := timedEvent(1, "All hands", date(2025, 6, 2), 9, 11)
+	first.Attendees = []models.Attendee{{ResourceID: room.ID}}
+	require.NoError(t, repo.CreateEvent(ctx, first))
+	second := timedEvent(2, "Workshop", date(2025, 6, 2), 10, 12)
+	second.Attendees = []models.Attendee{{ResourceID: room.ID}}
+	require.NoError(t, repo.CreateEvent(ctx, second))
+	publishedOutbox(t, repo)
+
+	// Accept a resource only when it has no other accepted booking in the window.
+	decide := func(event models.Event, resources []models.Resource, bookings map[int64][]models.Event) map[int64]string {
+		statuses := make(map[int64]string)
+		for _, res := range resources {
+			statuses[res.ID] = models.AttendeeAccepted
+			if len(bookings[res.ID]) > 0 {
+				statuses[res.ID] = models.AttendeeDeclined
+			}
+		}
+		return statuses
+	}
+
+	booked, err := repo.BookResources(ctx, first.ID, first.Start, first.End, decide)
+	require.NoError(t, err)
+	require.Len(t, booked.Attendees, 1)
+	require.Equal(t, models.AttendeeAccepted, booked.Attendees[0].Status)
+
+	declined, err := repo.BookResources(ctx, second.ID, second.Start, second.End, decide)
+	require.NoError(t, err)
+	require.Equal(t, models.AttendeeDeclined, declined.Attendees[0].Status)
+
+	// Settling an event again without a change writes nothing.
+	_, err = repo.BookResources(ctx, first.ID, first.Start, first.End, decide)
+	require.NoError(t, err)
+	messages := publishedOutbox(t, repo)
+	require.Len(t, messages, 2)
+	require.Equal(t, models.NotificationEventResponded, messages[0].Type)
+
+	bookings, err := repo.GetBookings(ctx, []int64{room.ID}, date(2025, 6, 2), date(2025, 6, 3))
+	require.NoError(t, err)
+	require.Len(t, bookings[room.ID], 1)
+	require.Equal(t, first.ID, bookings[room.ID][0].ID)
+
+	bookings, err = repo.GetBookings(ctx, []int64{room.ID}, first.End, first.End.Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, bookings[room.ID])
+
+	_, err = repo.BookResources(ctx, 99, first.Start, first.End, decide)
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
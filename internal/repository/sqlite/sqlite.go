@@ -0,0 +1,390 @@
+package sqlite
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+type Repository struct {
+	db  *sql.DB
+	log *zap.Logger
+}
+
+const dateFormat = "2006-01-02"
+
+const eventColumns = `id,user_id,date,event,start_at,end_at,all_day,transparency,visibility,rrule,sequence`
+
+const (
+	createQuery = `INSERT INTO calendar (user_id,date,event,start_at,end_at,all_day,transparency,visibility,rrule)
+                                VALUES (?,?,?,?,?,?,?,?,?) RETURNING id`
+	updateQuery = `UPDATE calendar SET date = ?1, event = ?2, start_at = ?4, end_at = ?5, all_day = ?6,
+                                transparency = ?7, visibility = ?8, rrule = ?9, sequence = sequence + 1
+                                WHERE user_id = ?3 AND id = ?10 RETURNING ` + eventColumns
+	// Without an id every event of the user on the date is deleted.
+	deleteQuery = `DELETE FROM calendar WHERE user_id = ?1 AND ((?3 = 0 AND date = ?2) OR id = ?3)`
+	// Views list the user's own events and the events they are invited to. The window is
+	// computed by the caller so that month arithmetic matches Postgres.
+	ownOrInvited = `(user_id = ?1 OR id IN (SELECT event_id FROM event_attendees WHERE user_id = ?1))`
+	getViewQuery = `SELECT ` + eventColumns + ` FROM calendar WHERE ` + ownOrInvited + `
+                                AND date >= ?2 AND date < ?3
+                                ORDER BY date, id`
+	// Recurring series are returned whenever they start before the window ends; callers expand them.
+	// Invitations count unless declined.
+	getInRangeQuery = `SELECT ` + eventColumns + ` FROM calendar
+                                WHERE (user_id IN (SELECT value FROM json_each(?1))
+                                       OR id IN (SELECT event_id FROM event_attendees
+                                                 WHERE user_id IN (SELECT value FROM json_each(?1)) AND status <> 'declined'))
+                                  AND ((start_at < ?3 AND end_at > ?2 AND end_at > start_at) OR (rrule <> '' AND start_at < ?3))
+                                ORDER BY user_id, start_at, id`
+	getEventQuery = `SELECT ` + eventColumns + ` FROM calendar WHERE id = ?`
+	// Attendees missing from the new list are removed; the rest keep their response.
+	deleteRemovedAttendeesQuery = `DELETE FROM event_attendees
+                                WHERE event_id = ?1
+                                  AND NOT (COALESCE(user_id IN (SELECT value FROM json_each(?2)), 0)
+                                           OR (user_id IS NULL AND email IN (SELECT value FROM json_each(?3))))`
+	insertUserAttendeeQuery  = `INSERT INTO event_attendees (event_id, user_id, status, comment) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`
+	insertEmailAttendeeQuery = `INSERT INTO event_attendees (event_id, email) VALUES (?, ?) ON CONFLICT DO NOTHING`
+	getAttendeesQuery        = `SELECT event_id, COALESCE(user_id, 0), email, status, comment FROM event_attendees
+                                WHERE event_id IN (SELECT value FROM json_each(?)) ORDER BY id`
+	// Internal invitees are matched by user_id, external ones by email.
+	respondQuery = `UPDATE event_attendees SET status = ?4, responded_at = ?5
+                                WHERE event_id = ?1
+                                  AND ((?2 <> 0 AND user_id = ?2) OR (?2 = 0 AND user_id IS NULL AND email = ?3))`
+)
+
+func (r *Repository) CreateEvent(ctx context.Context, event *models.Event) error {
+	r.log.Debug("Creating Event", zap.Any("event", event))
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, createQuery,
+		event.UserID,
+		event.Date.Format(dateFormat),
+		event.Event,
+		event.Start.UnixMicro(),
+		event.End.UnixMicro(),
+		event.AllDay,
+		event.Transparency,
+		event.Visibility,
+		event.RRule,
+	).Scan(&event.ID)
+	if err != nil {
+		r.log.Error("Error create event", zap.Error(err))
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	if len(event.Attendees) > 0 {
+		if err := replaceAttendees(ctx, tx, event.ID, event.Attendees); err != nil {
+			r.log.Error("Error save attendees", zap.Error(err))
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		r.log.Error("Error commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	r.log.Debug("Created event", zap.Int64("id", event.ID))
+	return nil
+}
+
+func (r *Repository) UpdateEvent(ctx context.Context, event *models.Event) error {
+	r.log.Debug("Updating Event", zap.Any("event", event))
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	updated, err := collectEvents(tx.QueryContext(ctx, updateQuery,
+		event.Date.Format(dateFormat),
+		event.Event,
+		event.UserID,
+		event.Start.UnixMicro(),
+		event.End.UnixMicro(),
+		event.AllDay,
+		event.Transparency,
+		event.Visibility,
+		event.RRule,
+		event.ID,
+	))
+	if err != nil {
+		r.log.Error("Error update event", zap.Error(err))
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	if event.Attendees != nil && len(updated) > 0 {
+		if err := replaceAttendees(ctx, tx, event.ID, event.Attendees); err != nil {
+			r.log.Error("Error save attendees", zap.Error(err))
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		r.log.Error("Error commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	r.log.Debug("Updated event", zap.Int("events", len(updated)))
+	return nil
+}
+
+func (r *Repository) DeleteEvent(ctx context.Context, event *models.Event) error {
+	r.log.Debug("Deleting Event", zap.Any("event", event))
+	if _, err := r.db.ExecContext(ctx, deleteQuery, event.UserID, event.Date.Format(dateFormat), event.ID); err != nil {
+		r.log.Error("Error delete event", zap.Error(err))
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetEventsForDay(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	r.log.Debug("Getting Events for Day", zap.Any("event", event))
+	from := day(event.Date)
+	return r.view(ctx, "day", event.UserID, from, from.AddDate(0, 0, 1))
+}
+
+func (r *Repository) GetEventsForWeek(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	r.log.Debug("Getting Events for Week", zap.Any("event", event))
+	from := day(event.Date)
+	return r.view(ctx, "week", event.UserID, from, from.AddDate(0, 0, 7))
+}
+
+// GetEventsForMonth lists the month up to, not including, the same day a month later, as the
+// Postgres query does.
+func (r *Repository) GetEventsForMonth(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	r.log.Debug("Getting Events for Month", zap.Any("event", event))
+	from := day(event.Date)
+	return r.view(ctx, "month", event.UserID, from, addMonth(from))
+}
+
+func (r *Repository) GetEventsInRange(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Event, error) {
+	r.log.Debug("Getting events in range", zap.Int64s("user_ids", userIDs), zap.Time("from", from), zap.Time("to", to))
+	ids, err := json.Marshal(userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events in range: %w", err)
+	}
+	events, err := collectEvents(r.db.QueryContext(ctx, getInRangeQuery, string(ids), from.UnixMicro(), to.UnixMicro()))
+	if err != nil {
+		r.log.Error("Error get events in range", zap.Error(err))
+		return nil, fmt.Errorf("failed to get events in range: %w", err)
+	}
+	return events, nil
+}
+
+// GetEvent returns a single event with its attendees.
+func (r *Repository) GetEvent(ctx context.Context, id int64) (*models.Event, error) {
+	r.log.Debug("Getting event", zap.Int64("event_id", id))
+	event := &models.Event{}
+	err := scanEvent(r.db.QueryRowContext(ctx, getEventQuery, id), event)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("event %d: %w", id, models.ErrNotFound)
+	}
+	if err != nil {
+		r.log.Error("Error get event", zap.Error(err))
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	events := []models.Event{*event}
+	if err := loadAttendees(ctx, r.db, events); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+// GetAttendees returns the invitees of the events by event id, in one query. Events without
+// invitees are left out.
+func (r *Repository) GetAttendees(ctx context.Context, eventIDs []int64) (map[int64][]models.Attendee, error) {
+	r.log.Debug("Getting attendees", zap.Int64s("event_ids", eventIDs))
+	events := make([]models.Event, len(eventIDs))
+	for i, id := range eventIDs {
+		events[i].ID = id
+	}
+	if err := loadAttendees(ctx, r.db, events); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return nil, err
+	}
+	attendees := make(map[int64][]models.Attendee, len(events))
+	for _, ev := range events {
+		if len(ev.Attendees) > 0 {
+			attendees[ev.ID] = ev.Attendees
+		}
+	}
+	return attendees, nil
+}
+
+// GetOutOfOffice returns no periods: profiles are not kept in SQLite.
+func (r *Repository) GetOutOfOffice(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.OutOfOffice, error) {
+	return nil, nil
+}
+
+// GetTimeZones returns no time zones: profiles are not kept in SQLite.
+func (r *Repository) GetTimeZones(ctx context.Context, userIDs []int64) (map[int64]string, error) {
+	return nil, nil
+}
+
+// RespondToEvent records an invitee's response.
+func (r *Repository) RespondToEvent(ctx context.Context, eventID int64, attendee models.Attendee) error {
+	r.log.Debug("Responding to event", zap.Int64("event_id", eventID), zap.Int64("user_id", attendee.UserID), zap.String("status", attendee.Status))
+	res, err := r.db.ExecContext(ctx, respondQuery, eventID, attendee.UserID, attendee.Email, attendee.Status, time.Now().UnixMicro())
+	if err != nil {
+		r.log.Error("Error respond to event", zap.Error(err))
+		return fmt.Errorf("failed to respond to event: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("invitation to event %d: %w", eventID, models.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) Close() {
+	r.log.Info("Closing repository")
+	r.db.Close()
+}
+
+func (r *Repository) view(ctx context.Context, name string, userID int64, from, to time.Time) ([]models.Event, error) {
+	events, err := collectEvents(r.db.QueryContext(ctx, getViewQuery, userID, from.Format(dateFormat), to.Format(dateFormat)))
+	if err != nil {
+		r.log.Error("Error get events for "+name, zap.Error(err))
+		return nil, fmt.Errorf("failed to get events for %s: %w", name, err)
+	}
+	if err := loadAttendees(ctx, r.db, events); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return nil, err
+	}
+	return events, nil
+}
+
+// replaceAttendees makes the stored invitee list of an event match attendees. Resources
+// are not kept in SQLite and are reported missing.
+func replaceAttendees(ctx context.Context, tx *sql.Tx, eventID int64, attendees []models.Attendee) error {
+	userIDs, emails := []int64{}, []string{}
+	for _, a := range attendees {
+		switch {
+		case a.UserID != 0:
+			userIDs = append(userIDs, a.UserID)
+		case a.ResourceID != 0:
+			return fmt.Errorf("resource: %w", models.ErrNotFound)
+		default:
+			emails = append(emails, a.Email)
+		}
+	}
+	users, _ := json.Marshal(userIDs)
+	addresses, _ := json.Marshal(emails)
+	if _, err := tx.ExecContext(ctx, deleteRemovedAttendeesQuery, eventID, string(users), string(addresses)); err != nil {
+		return fmt.Errorf("failed to save attendees: %w", err)
+	}
+	// Users are inserted before emails so that new invitees keep the Postgres order.
+	for _, a := range attendees {
+		if a.UserID == 0 {
+			continue
+		}
+		status := a.Status
+		if status == "" {
+			status = models.AttendeeNeedsAction
+		}
+		if _, err := tx.ExecContext(ctx, insertUserAttendeeQuery, eventID, a.UserID, status, a.Comment); err != nil {
+			return fmt.Errorf("failed to save attendees: %w", err)
+		}
+	}
+	for _, email := range emails {
+		if _, err := tx.ExecContext(ctx, insertEmailAttendeeQuery, eventID, email); err != nil {
+			return fmt.Errorf("failed to save attendees: %w", err)
+		}
+	}
+	return nil
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// loadAttendees fills in the Attendees of events in place.
+func loadAttendees(ctx context.Context, q querier, events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	index := make(map[int64][]int, len(events))
+	ids := make([]int64, 0, len(events))
+	for i, ev := range events {
+		if _, ok := index[ev.ID]; !ok {
+			ids = append(ids, ev.ID)
+		}
+		index[ev.ID] = append(index[ev.ID], i)
+	}
+	eventIDs, _ := json.Marshal(ids)
+	rows, err := q.QueryContext(ctx, getAttendeesQuery, string(eventIDs))
+	if err != nil {
+		return fmt.Errorf("failed to get attendees: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a models.Attendee
+		if err := rows.Scan(&a.EventID, &a.UserID, &a.Email, &a.Status, &a.Comment); err != nil {
+			return fmt.Errorf("failed to get attendees: %w", err)
+		}
+		for _, i := range index[a.EventID] {
+			events[i].Attendees = append(events[i].Attendees, a)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get attendees: %w", err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEvent(row scanner, ev *models.Event) error {
+	var date string
+	var start, end int64
+	err := row.Scan(&ev.ID, &ev.UserID, &date, &ev.Event, &start, &end, &ev.AllDay, &ev.Transparency, &ev.Visibility, &ev.RRule, &ev.Sequence)
+	if err != nil {
+		return err
+	}
+	if ev.Date, err = time.Parse(dateFormat, date); err != nil {
+		return err
+	}
+	ev.Start = time.UnixMicro(start).UTC()
+	ev.End = time.UnixMicro(end).UTC()
+	return nil
+}
+
+// collectEvents drains rows of eventColumns into events.
+func collectEvents(rows *sql.Rows, err error) ([]models.Event, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []models.Event
+	for rows.Next() {
+		var ev models.Event
+		if err := scanEvent(rows, &ev); err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// day truncates t to its calendar date, as the date column stores it.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// addMonth adds a calendar month, clamping to the last day of a shorter month like
+// Postgres interval arithmetic; SQLite's date modifiers would overflow into the next month.
+func addMonth(t time.Time) time.Time {
+	next := t.AddDate(0, 1, 0)
+	if next.Day() != t.Day() {
+		next = next.AddDate(0, 0, -next.Day())
+	}
+	return next
+}
@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/repository/repotest"
+	"awesomeProject/internal/service"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRepository_Conformance(t *testing.T) {
+	repotest.RunCalendarRepository(t, func(t *testing.T) service.CalendarRepository {
+		storage, err := NewStorage(context.Background(), filepath.Join(t.TempDir(), "calendar.db"), true, zap.NewNop())
+		require.NoError(t, err)
+		repo := storage.NewRepository()
+		t.Cleanup(repo.Close)
+		return repo
+	})
+}
+
+func TestMigrator_DownAndUp(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "calendar.db")
+	m, err := NewMigrator(ctx, path)
+	require.NoError(t, err)
+	defer m.Close()
+
+	_, _, err = m.Version()
+	require.ErrorIs(t, err, migrate.ErrNilVersion)
+	require.NoError(t, m.Up())
+	version, dirty, err := m.Version()
+	require.NoError(t, err)
+	require.False(t, dirty)
+	require.Equal(t, uint(1), version)
+
+	require.NoError(t, m.Down())
+	_, _, err = m.Version()
+	require.ErrorIs(t, err, migrate.ErrNilVersion)
+	require.NoError(t, m.Up())
+
+	storage, err := NewStorage(ctx, path, false, zap.NewNop())
+	require.NoError(t, err)
+	repo := storage.NewRepository()
+	defer repo.Close()
+	_, err = repo.GetEvent(ctx, 1)
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
@@ -0,0 +1,114 @@
+// Package sqlite is an embedded SQLite CalendarRepository for single-node deployments. Like
+// the in-memory repository it keeps events and attendees only: there are no resources,
+// profiles or outbox.
+package sqlite
+
+import (
+	"awesomeProject/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	sqlitemigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.uber.org/zap"
+	_ "modernc.org/sqlite"
+	"net/url"
+)
+
+type Storage struct {
+	db  *sql.DB
+	log *zap.Logger
+}
+
+// NewStorage opens or creates the database file at path and, when autoMigrate is set,
+// applies the SQLite migrations.
+func NewStorage(ctx context.Context, path string, autoMigrate bool, log *zap.Logger) (*Storage, error) {
+	log = log.With(zap.String("type", "Storage"))
+	log.Info("Opening SQLite database", zap.String("path", path))
+	db, err := open(ctx, path)
+	if err != nil {
+		log.Error("Error opening SQLite database", zap.Error(err))
+		return nil, err
+	}
+	if !autoMigrate {
+		log.Info("Automatic migrations disabled")
+		return &Storage{db: db, log: log}, nil
+	}
+
+	log.Info("Starting database migrations")
+	if err := runMigrations(db); err != nil {
+		db.Close()
+		log.Error("Failed to run migrations", zap.Error(err))
+		return nil, fmt.Errorf("failed to run migration: %w", err)
+	}
+	log.Info("Successfully migrated database")
+	return &Storage{db: db, log: log}, nil
+}
+
+func (s *Storage) NewRepository() *Repository {
+	return &Repository{db: s.db, log: s.log.Named("repository")}
+}
+
+// NewMigrator opens the database file at path and returns a migrator for the embedded
+// SQLite migrations. Closing the migrator closes the database.
+func NewMigrator(ctx context.Context, path string) (*migrate.Migrate, error) {
+	db, err := open(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func open(ctx context.Context, path string) (*sql.DB, error) {
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() +
+		"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite: %w", err)
+	}
+	// A single connection serializes writers; SQLite allows only one at a time anyway.
+	db.SetMaxOpenConns(1)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping SQLite database: %w", err)
+	}
+	return db, nil
+}
+
+func newMigrator(db *sql.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations.SQLite, "sqlite")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	driver, err := sqlitemigrate.WithInstance(db, &sqlitemigrate.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("start migrations error %v", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	if err != nil {
+		return nil, fmt.Errorf("start migrations error %v", err)
+	}
+	return m, nil
+}
+
+// runMigrations applies the embedded SQLite migrations. It leaves db open.
+func runMigrations(db *sql.DB) error {
+	m, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil {
+		if errors.Is(err, migrate.ErrNoChange) {
+			return nil
+		}
+		return fmt.Errorf("migration up error: %v", err)
+	}
+	return nil
+}
@@ -1,81 +1,141 @@
 package repository
 
 import (
+	"awesomeProject/migrations"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
-	"os"
-	"path/filepath"
+	"time"
 )
 
 type Storage struct {
-	db  *pgxpool.Pool
-	log *zap.Logger
+	db       *pgxpool.Pool
+	replicas *replicaSet
+	log      *zap.Logger
 }
 
-func NewStorage(ctx context.Context, user string, password string, host string, port string, dbname string, sslmode string, log *zap.Logger) (*Storage, error) {
-	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s", user, password, host, port, dbname, sslmode)
+// Options tunes the connection pools, the read routing and the start-up migrations.
+type Options struct {
+	AutoMigrate bool
+	// MaxConns and MinConns size the primary pool.
+	MaxConns int32
+	MinConns int32
+	// Replicas are connection strings of read replicas serving views and free/busy.
+	Replicas []string
+	// ReplicaMaxConns and ReplicaMinConns size the pool of each replica.
+	ReplicaMaxConns int32
+	ReplicaMinConns int32
+	// ReadYourWrites keeps a user's reads on the primary for this long after their write.
+	ReadYourWrites time.Duration
+}
+
+// NewStorage connects to the PostgreSQL primary and the replicas and, when opts.AutoMigrate
+// is set, applies pending migrations. An unreachable replica is only logged: reads fall
+// back to the primary until it is up.
+func NewStorage(ctx context.Context, user string, password string, host string, port string, dbname string, sslmode string, opts Options, log *zap.Logger) (*Storage, error) {
+	connStr := ConnString(user, password, host, port, dbname, sslmode)
 	log = log.With(zap.String("type", "Storage"))
 	log.Info("Connecting to PostgreSQL database",
 		zap.String("dbname", dbname),
 		zap.String("user", user),
 		zap.String("sslmode", sslmode),
 	)
-	config, err := pgxpool.ParseConfig(connStr)
-	if err != nil {
-		log.Error("Error parsing connection string", zap.Error(err))
-		return nil, fmt.Errorf("failed to parse config: %w", err)
-	}
-
-	config.MaxConns = 10
-	config.MinConns = 2
-
-	db, err := pgxpool.NewWithConfig(ctx, config)
-
+	db, err := newPool(ctx, connStr, opts.MaxConns, opts.MinConns)
 	if err != nil {
 		log.Error("Error connecting to PostgreSQL database", zap.Error(err))
-		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		return nil, err
 	}
 
 	log.Info("Testing database connection")
-	if err := db.Ping(context.Background()); err != nil {
+	if err := db.Ping(ctx); err != nil {
+		db.Close()
 		log.Error("Failed to ping PostgreSQL database", zap.String("dbname", dbname), zap.Error(err))
 		return nil, fmt.Errorf("failed to ping PostgreSQL database: %w", err)
 	}
-
 	log.Info("Successfully connected to database")
+
+	var replicas []*pgxpool.Pool
+	for i, dsn := range opts.Replicas {
+		replica, err := newPool(ctx, dsn, opts.ReplicaMaxConns, opts.ReplicaMinConns)
+		if err != nil {
+			for _, pool := range append(replicas, db) {
+				pool.Close()
+			}
+			log.Error("Error connecting to replica", zap.Int("replica", i), zap.Error(err))
+			return nil, err
+		}
+		if err := replica.Ping(ctx); err != nil {
+			log.Warn("Failed to ping replica", zap.Int("replica", i), zap.Error(err))
+		}
+		replicas = append(replicas, replica)
+	}
+	storage := &Storage{db: db, log: log}
+	if len(replicas) > 0 {
+		log.Info("Routing reads to replicas", zap.Int("replicas", len(replicas)))
+		storage.replicas = newReplicaSet(replicas, opts.ReadYourWrites)
+	}
+
+	if !opts.AutoMigrate {
+		log.Info("Automatic migrations disabled")
+		return storage, nil
+	}
 	log.Info("Starting database migrations")
 
 	if err := runMigrations(connStr); err != nil {
+		db.Close()
+		storage.replicas.close()
 		log.Error("Failed to run migrations", zap.Error(err))
 		return nil, fmt.Errorf("failed to run migration: %w", err)
 	}
 	log.Info("Successfully migrated database")
-	return &Storage{
-		db:  db,
-		log: log,
-	}, nil
+	return storage, nil
 }
-func runMigrations(connStr string) error {
-	migratePath := os.Getenv("MIGRATE_PATH")
-	if migratePath == "" {
-		migratePath = "./migrations"
+
+func newPool(ctx context.Context, connStr string, maxConns, minConns int32) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
-	absPath, err := filepath.Abs(migratePath)
+	// Zero keeps the pgx default size.
+	if maxConns > 0 {
+		config.MaxConns = maxConns
+	}
+	config.MinConns = minConns
+	db, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
-	absPath = filepath.ToSlash(absPath)
-	migrateUrl := fmt.Sprintf("file://%s", absPath)
-	m, err := migrate.New(migrateUrl, connStr)
+	return db, nil
+}
+
+func ConnString(user string, password string, host string, port string, dbname string, sslmode string) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s", user, password, host, port, dbname, sslmode)
+}
+
+// NewMigrator returns a migrator for the embedded Postgres migrations. The caller closes it.
+func NewMigrator(connStr string) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations.Postgres, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", source, connStr)
+	if err != nil {
+		return nil, fmt.Errorf("start migrations error %v", err)
+	}
+	return m, nil
+}
+
+func runMigrations(connStr string) error {
+	m, err := NewMigrator(connStr)
 	if err != nil {
-		return fmt.Errorf("start migrations error %v", err)
+		return err
 	}
+	defer m.Close()
 	if err := m.Up(); err != nil {
 		if errors.Is(err, migrate.ErrNoChange) {
 			return nil
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_DownAndUp(t *testing.T) {
+	repo := newTestRepo(t)
+	m, err := NewMigrator(testDB.Config().ConnString())
+	require.NoError(t, err)
+	defer m.Close()
+	latest, dirty, err := m.Version()
+	require.NoError(t, err)
+	require.False(t, dirty)
+
+	require.NoError(t, m.Down())
+	_, _, err = m.Version()
+	require.ErrorIs(t, err, migrate.ErrNilVersion)
+	require.NoError(t, m.Migrate(latest))
+	// Pooled connections cache statements planned against the dropped tables.
+	testDB.Reset()
+
+	ctx := context.Background()
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Standup", date(2025, 6, 2), 9, 10)))
+	_, err = repo.GetEvent(ctx, 1)
+	require.NoError(t, err)
+	_, err = repo.GetEvent(ctx, 2)
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
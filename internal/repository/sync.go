@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	upsertSyncChangesQuery = `INSERT INTO sync_changes (user_id, event_id, deleted)
+                                SELECT t.u, $1, t.d FROM unnest($2::bigint[], $3::bool[]) AS t(u, d)
+                                ON CONFLICT (user_id, event_id) DO UPDATE
+                                SET deleted = EXCLUDED.deleted, xid = EXCLUDED.xid, changed_at = EXCLUDED.changed_at`
+	// Changes of transactions at or after the horizon may still be joined by earlier ones.
+	syncHorizonQuery    = `SELECT (pg_snapshot_xmin(pg_current_snapshot())::text)::bigint, pruned_xid FROM sync_watermark`
+	getSyncChangesQuery = `SELECT event_id, deleted, xid FROM sync_changes
+                                WHERE user_id = $1 AND (xid, event_id) > ($2, $3) AND xid < $4 AND (NOT deleted OR $5)
+                                ORDER BY xid, event_id
+                                LIMIT $6`
+	getEventsByIDQuery = `SELECT ` + eventColumns + ` FROM calendar WHERE id = ANY($1) AND deleted_at IS NULL`
+	// Tombstones are pruned oldest first; the watermark expires the tokens that may have missed them.
+	pruneSyncQuery = `WITH pruned AS (
+                                DELETE FROM sync_changes WHERE (user_id, event_id) IN (
+                                    SELECT user_id, event_id FROM sync_changes WHERE deleted AND changed_at < $1
+                                    ORDER BY changed_at LIMIT $2 FOR UPDATE SKIP LOCKED)
+                                RETURNING xid)
+                                UPDATE sync_watermark SET pruned_xid = GREATEST(pruned_xid, (SELECT max(xid) FROM pruned))
+                                WHERE EXISTS (SELECT 1 FROM pruned)
+                                RETURNING (SELECT count(*) FROM pruned)`
+)
+
+// writeSyncChanges moves the event to the head of the feeds of everyone who sees it after
+// the change, and leaves a tombstone for those who saw it before but no longer do.
+func writeSyncChanges(ctx context.Context, tx pgx.Tx, eventID int64, before, after *models.Event) error {
+	var users []int64
+	var deleted []bool
+	seen := make(map[int64]bool)
+	for _, side := range []struct {
+		event   *models.Event
+		deleted bool
+	}{{after, false}, {before, true}} {
+		if side.event == nil {
+			continue
+		}
+		for _, id := range viewerIDs(side.event) {
+			if !seen[id] {
+				seen[id] = true
+				users = append(users, id)
+				deleted = append(deleted, side.deleted)
+			}
+		}
+	}
+	if _, err := tx.Exec(ctx, upsertSyncChangesQuery, eventID, users, deleted); err != nil {
+		return fmt.Errorf("failed to write sync changes: %w", err)
+	}
+	return nil
+}
+
+// viewerIDs returns the organizer and the invited users of the event.
+func viewerIDs(event *models.Event) []int64 {
+	ids := []int64{event.UserID}
+	for _, a := range event.Attendees {
+		if a.UserID != 0 {
+			ids = append(ids, a.UserID)
+		}
+	}
+	return ids
+}
+
+// viewersOf returns the users who see any of the events; a user may be listed twice.
+func viewersOf(events ...models.Event) []int64 {
+	var ids []int64
+	for i := range events {
+		ids = append(ids, viewerIDs(&events[i])...)
+	}
+	return ids
+}
+
+// recordChange appends a change of an event to the audit log and to the sync feeds of the
+// users who see it, inside the transaction that makes the change.
+func recordChange(ctx context.Context, tx pgx.Tx, action, actor string, eventID int64, before, after *models.Event) error {
+	if err := writeAudit(ctx, tx, action, actor, eventID, before, after); err != nil {
+		return err
+	}
+	return writeSyncChanges(ctx, tx, eventID, before, after)
+}
+
+// GetChanges returns up to limit changes of the user's events after since. The zero cursor
+// lists every event the user sees, without tombstones. A cursor at or before the newest
+// pruned tombstone fails with models.ErrExpired.
+func (r *Repository) GetChanges(ctx context.Context, userID int64, since models.SyncCursor, limit int) (*models.SyncPage, error) {
+	r.log.Debug("Getting changes", zap.Int64("user_id", userID), zap.Int64("xid", since.XID), zap.Int64("event_id", since.EventID))
+	// The horizon, the feed and the events are read from one snapshot.
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var horizon, pruned int64
+	if err := tx.QueryRow(ctx, syncHorizonQuery).Scan(&horizon, &pruned); err != nil {
+		r.log.Error("Error get sync horizon", zap.Error(err))
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	full := since == models.SyncCursor{}
+	if !full && since.XID <= pruned {
+		return nil, fmt.Errorf("sync token: %w", models.ErrExpired)
+	}
+
+	rows, err := tx.Query(ctx, getSyncChangesQuery, userID, since.XID, since.EventID, horizon, !full, limit+1)
+	if err != nil {
+		r.log.Error("Error get changes", zap.Error(err))
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	type change struct {
+		eventID int64
+		deleted bool
+		xid     int64
+	}
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.eventID, &c.deleted, &c.xid); err != nil {
+			rows.Close()
+			r.log.Error("Error get changes", zap.Error(err))
+			return nil, fmt.Errorf("failed to get changes: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get changes", zap.Error(err))
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+
+	page := &models.SyncPage{Events: []models.Event{}, Deleted: []int64{}, Next: models.SyncCursor{XID: horizon}}
+	if len(changes) > limit {
+		changes = changes[:limit]
+		last := changes[limit-1]
+		page.More, page.Next = true, models.SyncCursor{XID: last.xid, EventID: last.eventID}
+	}
+	var ids []int64
+	for _, c := range changes {
+		if !c.deleted {
+			ids = append(ids, c.eventID)
+		}
+	}
+	events, err := collectEvents(tx.Query(ctx, getEventsByIDQuery, ids))
+	if err == nil {
+		err = loadAttendees(ctx, tx, events)
+	}
+	if err != nil {
+		r.log.Error("Error get changed events", zap.Error(err))
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	byID := make(map[int64]models.Event, len(events))
+	for _, ev := range events {
+		byID[ev.ID] = ev
+	}
+	for _, c := range changes {
+		// An event trashed by a transaction past the horizon is reported deleted already.
+		if ev, ok := byID[c.eventID]; ok && !c.deleted {
+			page.Events = append(page.Events, ev)
+		} else {
+			page.Deleted = append(page.Deleted, c.eventID)
+		}
+	}
+	return page, nil
+}
+
+// PruneSyncChanges deletes up to limit tombstones older than the given time and returns how
+// many it deleted.
+func (r *Repository) PruneSyncChanges(ctx context.Context, before time.Time, limit int) (int, error) {
+	var pruned int
+	err := r.db.QueryRow(ctx, pruneSyncQuery, before, limit).Scan(&pruned)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		r.log.Error("Error prune sync changes", zap.Error(err))
+		return 0, fmt.Errorf("failed to prune sync changes: %w", err)
+	}
+	return pruned, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync_FeedsCreatesUpdatesAndDeletes(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	ev := timedEvent(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}}
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	other := timedEvent(1, "Lunch", date(2025, 6, 3), 12, 13)
+	require.NoError(t, repo.CreateEvent(ctx, other))
+
+	full, err := repo.GetChanges(ctx, 2, models.SyncCursor{}, 10)
+	require.NoError(t, err)
+	require.Len(t, full.Events, 1)
+	require.Equal(t, ev.ID, full.Events[0].ID)
+	require.False(t, full.More)
+
+	// Uninviting user 2 leaves them a tombstone; the organizer sees an update.
+	since1, err := repo.GetChanges(ctx, 1, models.SyncCursor{}, 10)
+	require.NoError(t, err)
+	updated := timedEvent(1, "Planning", date(2025, 6, 2), 14, 15)
+	updated.ID, updated.Attendees = ev.ID, []models.Attendee{}
+	require.NoError(t, repo.UpdateEvent(ctx, updated))
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: other.ID, UserID: 1}))
+
+	page, err := repo.GetChanges(ctx, 2, full.Next, 10)
+	require.NoError(t, err)
+	require.Empty(t, page.Events)
+	require.Equal(t, []int64{ev.ID}, page.Deleted)
+	page, err = repo.GetChanges(ctx, 1, since1.Next, 10)
+	require.NoError(t, err)
+	require.Len(t, page.Events, 1)
+	require.Equal(t, 14, page.Events[0].Start.Hour())
+	require.Equal(t, []int64{other.ID}, page.Deleted)
+
+	// Nothing changed since the last page.
+	page, err = repo.GetChanges(ctx, 1, page.Next, 10)
+	require.NoError(t, err)
+	require.Empty(t, page.Events)
+	require.Empty(t, page.Deleted)
+}
+
+func TestSync_Paginates(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	for d := 2; d <= 6; d++ {
+		require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Standup", date(2025, 6, d), 9, 10)))
+	}
+
+	var ids []int64
+	since := models.SyncCursor{}
+	for pages := 1; ; pages++ {
+		page, err := repo.GetChanges(ctx, 1, since, 2)
+		require.NoError(t, err)
+		for _, ev := range page.Events {
+			ids = append(ids, ev.ID)
+		}
+		since = page.Next
+		if !page.More {
+			require.Equal(t, 3, pages)
+			break
+		}
+	}
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+}
+
+func TestSync_PrunedTombstonesExpireTokens(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	ev := timedEvent(1, "Planning", date(2025, 6, 2), 9, 10)
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	old, err := repo.GetChanges(ctx, 1, models.SyncCursor{}, 10)
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: ev.ID, UserID: 1}))
+	current, err := repo.GetChanges(ctx, 1, old.Next, 10)
+	require.NoError(t, err)
+	require.Equal(t, []int64{ev.ID}, current.Deleted)
+
+	pruned, err := repo.PruneSyncChanges(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, pruned)
+	_, err = repo.GetChanges(ctx, 1, old.Next, 10)
+	require.ErrorIs(t, err, models.ErrExpired)
+	_, err = repo.GetChanges(ctx, 1, current.Next, 10)
+	require.NoError(t, err)
+	_, err = repo.GetChanges(ctx, 1, models.SyncCursor{}, 10)
+	require.NoError(t, err)
+
+	pruned, err = repo.PruneSyncChanges(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	taskColumns     = `id, user_id, uid, summary, description, due_at, priority, status, percent_complete, completed_at`
+	createTaskQuery = `INSERT INTO tasks (user_id, uid, summary, description, due_at, priority, status, percent_complete, completed_at)
+                                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	// A task that was already completed keeps its original completion time.
+	updateTaskQuery = `UPDATE tasks SET summary = $3, description = $4, due_at = $5, priority = $6, status = $7,
+                                percent_complete = $8,
+                                completed_at = CASE WHEN $7 = 'completed' THEN COALESCE(completed_at, $9) END
+                                WHERE id = $1 AND user_id = $2 RETURNING uid, completed_at`
+	deleteTaskQuery = `DELETE FROM tasks WHERE id = $1 AND user_id = $2`
+	// A zero window returns every task; otherwise only tasks due within [from, to).
+	getTasksQuery = `SELECT ` + taskColumns + ` FROM tasks
+                                WHERE user_id = $1
+                                  AND ($2::timestamptz IS NULL OR (due_at >= $2 AND due_at < $3))
+                                  AND ($4 OR status NOT IN ('completed', 'cancelled'))
+                                ORDER BY due_at NULLS LAST, priority = 0, priority, id`
+	upsertTaskQuery = `INSERT INTO tasks (user_id, uid, summary, description, due_at, priority, status, percent_complete, completed_at)
+                                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                                ON CONFLICT (user_id, uid) DO UPDATE SET summary = EXCLUDED.summary,
+                                    description = EXCLUDED.description, due_at = EXCLUDED.due_at,
+                                    priority = EXCLUDED.priority, status = EXCLUDED.status,
+                                    percent_complete = EXCLUDED.percent_complete, completed_at = EXCLUDED.completed_at
+                                RETURNING id`
+)
+
+func (r *Repository) CreateTask(ctx context.Context, task *models.Task) error {
+	r.log.Debug("Creating task", zap.Int64("user_id", task.UserID), zap.String("uid", task.UID))
+	err := r.db.QueryRow(ctx, createTaskQuery, task.UserID, task.UID, task.Summary, task.Description, task.Due,
+		task.Priority, task.Status, task.PercentComplete, task.CompletedAt).Scan(&task.ID)
+	if err != nil {
+		r.log.Error("Error create task", zap.Error(err))
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdateTask(ctx context.Context, task *models.Task) error {
+	r.log.Debug("Updating task", zap.Int64("id", task.ID), zap.Int64("user_id", task.UserID))
+	err := r.db.QueryRow(ctx, updateTaskQuery, task.ID, task.UserID, task.Summary, task.Description, task.Due,
+		task.Priority, task.Status, task.PercentComplete, task.CompletedAt).Scan(&task.UID, &task.CompletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("task %d: %w", task.ID, models.ErrNotFound)
+	}
+	if err != nil {
+		r.log.Error("Error update task", zap.Error(err))
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteTask(ctx context.Context, task *models.Task) error {
+	r.log.Debug("Deleting task", zap.Int64("id", task.ID), zap.Int64("user_id", task.UserID))
+	tag, err := r.db.Exec(ctx, deleteTaskQuery, task.ID, task.UserID)
+	if err != nil {
+		r.log.Error("Error delete task", zap.Error(err))
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("task %d: %w", task.ID, models.ErrNotFound)
+	}
+	return nil
+}
+
+// GetTasks returns the user's tasks due within [from, to), or all of them when from is zero.
+// Completed and cancelled tasks are left out unless includeDone is set.
+func (r *Repository) GetTasks(ctx context.Context, userID int64, from, to time.Time, includeDone bool) ([]models.Task, error) {
+	r.log.Debug("Getting tasks", zap.Int64("user_id", userID), zap.Time("from", from), zap.Time("to", to))
+	var window *time.Time
+	if !from.IsZero() {
+		window = &from
+	}
+	rows, err := r.db.Query(ctx, getTasksQuery, userID, window, to, includeDone)
+	if err != nil {
+		r.log.Error("Error get tasks", zap.Error(err))
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	defer rows.Close()
+	var tasks []models.Task
+	for rows.Next() {
+		var t models.Task
+		if err := rows.Scan(&t.ID, &t.UserID, &t.UID, &t.Summary, &t.Description, &t.Due, &t.Priority, &t.Status,
+			&t.PercentComplete, &t.CompletedAt); err != nil {
+			r.log.Error("Error get tasks", zap.Error(err))
+			return nil, fmt.Errorf("failed to get tasks: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get tasks", zap.Error(err))
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// UpsertTasks creates or, matching on UID, replaces the given tasks in one transaction.
+func (r *Repository) UpsertTasks(ctx context.Context, tasks []models.Task) error {
+	r.log.Debug("Upserting tasks", zap.Int("tasks", len(tasks)))
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	for i := range tasks {
+		t := &tasks[i]
+		err := tx.QueryRow(ctx, upsertTaskQuery, t.UserID, t.UID, t.Summary, t.Description, t.Due,
+			t.Priority, t.Status, t.PercentComplete, t.CompletedAt).Scan(&t.ID)
+		if err != nil {
+			r.log.Error("Error upsert task", zap.Error(err))
+			return fmt.Errorf("failed to upsert task: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("Error commit transaction", zap.Error(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasks_CRUD(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	due := time.Date(2025, 6, 2, 17, 0, 0, 0, time.UTC)
+	task := &models.Task{UserID: 1, UID: "task-1@example.com", Summary: "Report", Due: &due, Priority: 1, Status: models.TaskNeedsAction}
+	require.NoError(t, repo.CreateTask(ctx, task))
+	require.NotZero(t, task.ID)
+
+	done := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	task.Status = models.TaskCompleted
+	task.PercentComplete = 100
+	task.CompletedAt = &done
+	require.NoError(t, repo.UpdateTask(ctx, task))
+	require.True(t, done.Equal(*task.CompletedAt))
+
+	// A second update keeps the original completion time.
+	task.Summary = "Final report"
+	later := done.Add(time.Hour)
+	task.CompletedAt = &later
+	require.NoError(t, repo.UpdateTask(ctx, task))
+	require.True(t, done.Equal(*task.CompletedAt))
+	require.Equal(t, "task-1@example.com", task.UID)
+
+	task.Status = models.TaskInProcess
+	require.NoError(t, repo.UpdateTask(ctx, task))
+	require.Nil(t, task.CompletedAt)
+
+	err := repo.UpdateTask(ctx, &models.Task{ID: task.ID, UserID: 2, Status: models.TaskNeedsAction})
+	require.ErrorIs(t, err, models.ErrNotFound)
+	require.ErrorIs(t, repo.DeleteTask(ctx, &models.Task{ID: task.ID, UserID: 2}), models.ErrNotFound)
+	require.NoError(t, repo.DeleteTask(ctx, task))
+	require.ErrorIs(t, repo.DeleteTask(ctx, task), models.ErrNotFound)
+}
+
+func TestTasks_GetWindowAndOrder(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, 6, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	tasks := []*models.Task{
+		{UserID: 1, UID: "a", Summary: "No due date", Status: models.TaskNeedsAction},
+		{UserID: 1, UID: "b", Summary: "Unprioritized", Due: at(2, 9), Status: models.TaskNeedsAction},
+		{UserID: 1, UID: "c", Summary: "Urgent", Due: at(2, 9), Priority: 1, Status: models.TaskNeedsAction},
+		{UserID: 1, UID: "d", Summary: "Done", Due: at(2, 8), Status: models.TaskCompleted, PercentComplete: 100, CompletedAt: at(1, 8)},
+		{UserID: 1, UID: "e", Summary: "Next week", Due: at(9, 9), Status: models.TaskInProcess},
+		{UserID: 2, UID: "a", Summary: "Someone else's", Due: at(2, 9), Status: models.TaskNeedsAction},
+	}
+	for _, task := range tasks {
+		require.NoError(t, repo.CreateTask(ctx, task))
+	}
+	summaries := func(tasks []models.Task) []string {
+		var s []string
+		for _, task := range tasks {
+			s = append(s, task.Summary)
+		}
+		return s
+	}
+
+	got, err := repo.GetTasks(ctx, 1, time.Time{}, time.Time{}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Urgent", "Unprioritized", "Next week", "No due date"}, summaries(got))
+
+	got, err = repo.GetTasks(ctx, 1, *at(2, 0), *at(9, 9), true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Done", "Urgent", "Unprioritized"}, summaries(got))
+	require.True(t, at(1, 8).Equal(*got[0].CompletedAt))
+
+	got, err = repo.GetTasks(ctx, 3, time.Time{}, time.Time{}, true)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestTasks_UpsertByUID(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	existing := &models.Task{UserID: 1, UID: "shared", Summary: "Old", Status: models.TaskNeedsAction}
+	require.NoError(t, repo.CreateTask(ctx, existing))
+
+	imported := []models.Task{
+		{UserID: 1, UID: "shared", Summary: "New", Priority: 5, Status: models.TaskInProcess, PercentComplete: 50},
+		{UserID: 1, UID: "fresh", Summary: "Fresh", Status: models.TaskNeedsAction},
+		{UserID: 2, UID: "shared", Summary: "Other user", Status: models.TaskNeedsAction},
+	}
+	require.NoError(t, repo.UpsertTasks(ctx, imported))
+	require.Equal(t, existing.ID, imported[0].ID)
+	require.NotEqual(t, existing.ID, imported[2].ID)
+
+	got, err := repo.GetTasks(ctx, 1, time.Time{}, time.Time{}, true)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, "New", got[0].Summary)
+	require.Equal(t, 50, got[0].PercentComplete)
+
+	// A failing task rolls back the whole import.
+	err = repo.UpsertTasks(ctx, []models.Task{
+		{UserID: 1, UID: "fresh", Summary: "Renamed", Status: models.TaskNeedsAction},
+		{UserID: 1, UID: "broken", Summary: "Bad", Status: "unknown"},
+	})
+	require.Error(t, err)
+	got, err = repo.GetTasks(ctx, 1, time.Time{}, time.Time{}, true)
+	require.NoError(t, err)
+	require.Equal(t, "Fresh", got[1].Summary)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"awesomeProject/internal/audit"
+	"awesomeProject/internal/models"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	getTrashQuery = `SELECT ` + eventColumns + `, deleted_at FROM calendar
+                                WHERE user_id = $1 AND deleted_at IS NOT NULL
+                                ORDER BY deleted_at DESC, id`
+	// The cancellation sent on delete carried the next sequence, so the restored event skips
+	// past it for attendees to accept the new invitation.
+	restoreQuery = `UPDATE calendar SET deleted_at = NULL, sequence = sequence + 2
+                                WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+                                RETURNING ` + eventColumns
+	// Purges are audited in the same statement; the delete entry keeps the last state.
+	purgeQuery = `WITH purged AS (
+                                DELETE FROM calendar WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL RETURNING id)
+                                INSERT INTO audit_log (event_id, actor, action, request_id)
+                                SELECT id, $3::text, $4::text, $5::text FROM purged`
+	// SKIP LOCKED lets several instances purge concurrently.
+	purgeTrashQuery = `WITH purged AS (
+                                DELETE FROM calendar WHERE id IN (
+                                    SELECT id FROM calendar WHERE deleted_at < $1
+                                    ORDER BY deleted_at LIMIT $2 FOR UPDATE SKIP LOCKED)
+                                RETURNING id)
+                                INSERT INTO audit_log (event_id, actor, action, request_id)
+                                SELECT id, $3::text, $4::text, $5::text FROM purged`
+)
+
+// GetTrash returns the user's deleted events, most recently deleted first.
+func (r *Repository) GetTrash(ctx context.Context, userID int64) ([]models.TrashedEvent, error) {
+	r.log.Debug("Getting trash", zap.Int64("user_id", userID))
+	rows, err := r.db.Query(ctx, getTrashQuery, userID)
+	if err != nil {
+		r.log.Error("Error get trash", zap.Error(err))
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+	defer rows.Close()
+	var events []models.Event
+	var deletedAt []time.Time
+	for rows.Next() {
+		var ev models.Event
+		var at time.Time
+		if err := rows.Scan(&ev.ID, &ev.UserID, &ev.Date, &ev.Event, &ev.Start, &ev.End, &ev.AllDay,
+			&ev.Transparency, &ev.Visibility, &ev.RRule, &ev.Sequence, &at); err != nil {
+			r.log.Error("Error get trash", zap.Error(err))
+			return nil, fmt.Errorf("failed to get trash: %w", err)
+		}
+		events = append(events, ev)
+		deletedAt = append(deletedAt, at)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get trash", zap.Error(err))
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+	if err := loadAttendees(ctx, r.db, events); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return nil, err
+	}
+	trash := make([]models.TrashedEvent, len(events))
+	for i, ev := range events {
+		trash[i] = models.TrashedEvent{Event: ev, DeletedAt: deletedAt[i]}
+	}
+	return trash, nil
+}
+
+// RestoreEvent brings a deleted event of the user back from the trash. It fails with
+// models.ErrConflict when the event now overlaps another one of a no-double-booking calendar.
+func (r *Repository) RestoreEvent(ctx context.Context, userID, eventID int64) (*models.Event, error) {
+	r.log.Debug("Restoring event", zap.Int64("user_id", userID), zap.Int64("event_id", eventID))
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	restored, err := collectEvents(tx.Query(ctx, restoreQuery, eventID, userID))
+	if err != nil {
+		r.log.Error("Error restore event", zap.Error(err))
+		return nil, fmt.Errorf("failed to restore event: %w", conflictErr(err))
+	}
+	if len(restored) == 0 {
+		return nil, fmt.Errorf("trashed event %d: %w", eventID, models.ErrNotFound)
+	}
+	if err := loadAttendees(ctx, tx, restored); err != nil {
+		r.log.Error("Error get attendees", zap.Error(err))
+		return nil, err
+	}
+	if err := writeOutbox(ctx, tx, models.NotificationEventRestored, restored[0]); err != nil {
+		r.log.Error("Error write outbox", zap.Error(err))
+		return nil, err
+	}
+	if err := recordChange(ctx, tx, models.AuditRestore, userActor(userID), eventID, nil, &restored[0]); err != nil {
+		r.log.Error("Error record change", zap.Error(err))
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("Error commit transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	r.replicas.wrote(viewerIDs(&restored[0])...)
+	return &restored[0], nil
+}
+
+// PurgeEvent permanently deletes an event of the user from the trash. Its deletion has
+// already been announced, so nothing is written to the outbox.
+func (r *Repository) PurgeEvent(ctx context.Context, userID, eventID int64) error {
+	r.log.Debug("Purging event", zap.Int64("user_id", userID), zap.Int64("event_id", eventID))
+	tag, err := r.db.Exec(ctx, purgeQuery, eventID, userID, userActor(userID), models.AuditPurge, audit.RequestID(ctx))
+	if err != nil {
+		r.log.Error("Error purge event", zap.Error(err))
+		return fmt.Errorf("failed to purge event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("trashed event %d: %w", eventID, models.ErrNotFound)
+	}
+	return nil
+}
+
+// PurgeTrash permanently deletes up to limit events that were deleted before the given time
+// and returns how many it deleted.
+func (r *Repository) PurgeTrash(ctx context.Context, before time.Time, limit int) (int, error) {
+	tag, err := r.db.Exec(ctx, purgeTrashQuery, before, limit, models.AuditSystem, models.AuditPurge, audit.RequestID(ctx))
+	if err != nil {
+		r.log.Error("Error purge trash", zap.Error(err))
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash_DeleteHidesAndRestoreBringsBack(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	ev := timedEvent(1, "Planning", date(2025, 6, 2), 9, 10)
+	ev.Attendees = []models.Attendee{{UserID: 2}}
+	require.NoError(t, repo.CreateEvent(ctx, ev))
+	require.NoError(t, repo.CreateReminder(ctx, &models.Reminder{EventID: ev.ID, UserID: 1, Before: time.Hour}))
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: ev.ID, UserID: 1}))
+
+	_, err := repo.GetEvent(ctx, ev.ID)
+	require.ErrorIs(t, err, models.ErrNotFound)
+	for _, user := range []int64{1, 2} {
+		day, err := repo.GetEventsForDay(ctx, &models.Event{UserID: user, Date: date(2025, 6, 2)})
+		require.NoError(t, err)
+		require.Empty(t, day)
+	}
+	busy, err := repo.GetEventsInRange(ctx, []int64{1}, date(2025, 6, 1), date(2025, 6, 3))
+	require.NoError(t, err)
+	require.Empty(t, busy)
+	require.ErrorIs(t, repo.RespondToEvent(ctx, ev.ID, models.Attendee{UserID: 2, Status: models.AttendeeAccepted}), models.ErrNotFound)
+	fired, err := repo.ProcessDueReminders(ctx, date(2025, 6, 2), 10, func(ctx context.Context, d *models.DueReminder) {})
+	require.NoError(t, err)
+	require.Zero(t, fired)
+
+	trash, err := repo.GetTrash(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, "Planning", trash[0].Event.Event)
+	require.Len(t, trash[0].Attendees, 1)
+	require.False(t, trash[0].DeletedAt.IsZero())
+	trash, err = repo.GetTrash(ctx, 2)
+	require.NoError(t, err)
+	require.Empty(t, trash)
+
+	_, err = repo.RestoreEvent(ctx, 2, ev.ID)
+	require.ErrorIs(t, err, models.ErrNotFound)
+	restored, err := repo.RestoreEvent(ctx, 1, ev.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, restored.Sequence)
+	require.Len(t, restored.Attendees, 1)
+	_, err = repo.RestoreEvent(ctx, 1, ev.ID)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	day, err := repo.GetEventsForDay(ctx, &models.Event{UserID: 2, Date: date(2025, 6, 2)})
+	require.NoError(t, err)
+	require.Len(t, day, 1)
+	messages := publishedOutbox(t, repo)
+	require.Len(t, messages, 3)
+	require.Equal(t, models.NotificationEventDeleted, messages[1].Type)
+	require.Equal(t, models.NotificationEventRestored, messages[2].Type)
+	var payload models.Event
+	require.NoError(t, json.Unmarshal(messages[2].Payload, &payload))
+	require.Equal(t, ev.ID, payload.ID)
+}
+
+func TestTrash_RestoreIntoDoubleBooking(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	require.NoError(t, repo.UpsertProfile(ctx, &models.Profile{UserID: 1, TimeZone: "UTC", NoDoubleBooking: true}))
+	first := timedEvent(1, "Sync", date(2025, 6, 2), 9, 10)
+	require.NoError(t, repo.CreateEvent(ctx, first))
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: first.ID, UserID: 1}))
+
+	// The trashed event no longer holds its slot.
+	require.NoError(t, repo.CreateEvent(ctx, timedEvent(1, "Overlap", date(2025, 6, 2), 9, 11)))
+	_, err := repo.RestoreEvent(ctx, 1, first.ID)
+	require.ErrorIs(t, err, models.ErrConflict)
+	trash, err := repo.GetTrash(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+}
+
+func TestTrash_Purge(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	live := timedEvent(1, "Live", date(2025, 6, 2), 9, 10)
+	old := timedEvent(1, "Old", date(2025, 6, 3), 9, 10)
+	recent := timedEvent(1, "Recent", date(2025, 6, 4), 9, 10)
+	for _, ev := range []*models.Event{live, old, recent} {
+		require.NoError(t, repo.CreateEvent(ctx, ev))
+	}
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: old.ID, UserID: 1}))
+	require.NoError(t, repo.DeleteEvent(ctx, &models.Event{ID: recent.ID, UserID: 1}))
+	_, err := testDB.Exec(ctx, `UPDATE calendar SET deleted_at = now() - INTERVAL '40 days' WHERE id = $1`, old.ID)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, repo.PurgeEvent(ctx, 1, live.ID), models.ErrNotFound)
+	require.ErrorIs(t, repo.PurgeEvent(ctx, 2, recent.ID), models.ErrNotFound)
+
+	purged, err := repo.PurgeTrash(ctx, time.Now().AddDate(0, 0, -30), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+	trash, err := repo.GetTrash(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, recent.ID, trash[0].ID)
+
+	require.NoError(t, repo.PurgeEvent(ctx, 1, recent.ID))
+	trash, err = repo.GetTrash(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, trash)
+	_, err = repo.GetEvent(ctx, live.ID)
+	require.NoError(t, err)
+}
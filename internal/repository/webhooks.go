@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	createWebhookQuery = `INSERT INTO webhooks (user_id, url, secret) VALUES ($1, $2, $3) RETURNING id, created_at`
+	deleteWebhookQuery = `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+	// Secrets are not listed; they are only returned when a webhook is created.
+	getWebhooksQuery = `SELECT id, user_id, url, created_at FROM webhooks WHERE user_id = $1 ORDER BY id`
+	enqueueQuery     = `
+		INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+		SELECT id, $2, $3 FROM webhooks WHERE user_id = $1`
+	getDeliveriesQuery = `
+		SELECT d.id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+		       d.last_error, d.response_code, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE w.user_id = $1 AND ($2 = 0 OR d.webhook_id = $2)
+		ORDER BY d.id DESC
+		LIMIT $3`
+	redeliverQuery = `
+		INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+		SELECT d.webhook_id, d.event_type, d.payload
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.id = $1 AND w.user_id = $2
+		RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at,
+		          last_error, response_code, created_at, delivered_at`
+	dueDeliveriesQuery = `
+		SELECT d.id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+		       d.last_error, d.response_code, d.created_at, d.delivered_at,
+		       w.user_id, w.url, w.secret, w.created_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+		ORDER BY d.id
+		LIMIT $2
+		FOR UPDATE OF d SKIP LOCKED`
+	updateDeliveryQuery = `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, response_code = $6, delivered_at = $7
+		WHERE id = $1`
+)
+
+func (r *Repository) CreateWebhook(ctx context.Context, hook *models.Webhook) error {
+	r.log.Debug("Creating webhook", zap.Int64("user_id", hook.UserID), zap.String("url", hook.URL))
+	err := r.db.QueryRow(ctx, createWebhookQuery, hook.UserID, hook.URL, hook.Secret).Scan(&hook.ID, &hook.CreatedAt)
+	if err != nil {
+		r.log.Error("Error create webhook", zap.Error(err))
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	r.log.Debug("Created webhook", zap.Int64("id", hook.ID))
+	return nil
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, hook *models.Webhook) error {
+	r.log.Debug("Deleting webhook", zap.Int64("user_id", hook.UserID), zap.Int64("id", hook.ID))
+	tag, err := r.db.Exec(ctx, deleteWebhookQuery, hook.ID, hook.UserID)
+	if err != nil {
+		r.log.Error("Error delete webhook", zap.Error(err))
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("webhook %d: %w", hook.ID, models.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) GetWebhooks(ctx context.Context, userID int64) ([]models.Webhook, error) {
+	r.log.Debug("Getting webhooks", zap.Int64("user_id", userID))
+	rows, err := r.db.Query(ctx, getWebhooksQuery, userID)
+	if err != nil {
+		r.log.Error("Error get webhooks", zap.Error(err))
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	defer rows.Close()
+	var hooks []models.Webhook
+	for rows.Next() {
+		var hook models.Webhook
+		if err := rows.Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.CreatedAt); err != nil {
+			r.log.Error("Error get webhooks", zap.Error(err))
+			return nil, fmt.Errorf("failed to get webhooks: %w", err)
+		}
+		hooks = append(hooks, hook)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get webhooks", zap.Error(err))
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// EnqueueDeliveries queues payload for every webhook registered by the user and returns how many were queued.
+func (r *Repository) EnqueueDeliveries(ctx context.Context, userID int64, eventType string, payload []byte) (int, error) {
+	tag, err := r.db.Exec(ctx, enqueueQuery, userID, eventType, payload)
+	if err != nil {
+		r.log.Error("Error enqueue webhook deliveries", zap.Error(err))
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *Repository) GetDeliveries(ctx context.Context, userID int64, webhookID int64, limit int) ([]models.WebhookDelivery, error) {
+	r.log.Debug("Getting webhook deliveries", zap.Int64("user_id", userID), zap.Int64("webhook_id", webhookID))
+	rows, err := r.db.Query(ctx, getDeliveriesQuery, userID, webhookID, limit)
+	if err != nil {
+		r.log.Error("Error get webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := scanDelivery(rows, &d); err != nil {
+			r.log.Error("Error get webhook deliveries", zap.Error(err))
+			return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Redeliver queues a fresh copy of a previous delivery, leaving the original in the log.
+func (r *Repository) Redeliver(ctx context.Context, userID int64, deliveryID int64) (*models.WebhookDelivery, error) {
+	r.log.Debug("Redelivering webhook", zap.Int64("user_id", userID), zap.Int64("delivery_id", deliveryID))
+	d := &models.WebhookDelivery{}
+	err := scanDelivery(r.db.QueryRow(ctx, redeliverQuery, deliveryID, userID), d)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("delivery %d: %w", deliveryID, models.ErrNotFound)
+	}
+	if err != nil {
+		r.log.Error("Error redeliver webhook", zap.Error(err))
+		return nil, fmt.Errorf("failed to redeliver webhook: %w", err)
+	}
+	return d, nil
+}
+
+// ProcessDueDeliveries locks up to limit pending deliveries, lets fn attempt each one and
+// persists the delivery state fn leaves behind.
+func (r *Repository) ProcessDueDeliveries(ctx context.Context, now time.Time, limit int, fn func(ctx context.Context, d *models.WebhookDelivery, hook models.Webhook)) (int, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		r.log.Error("Error begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, dueDeliveriesQuery, now, limit)
+	if err != nil {
+		r.log.Error("Error get due webhook deliveries", zap.Error(err))
+		return 0, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+	var deliveries []models.WebhookDelivery
+	var hooks []models.Webhook
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var hook models.Webhook
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastError, &d.ResponseCode, &d.CreatedAt, &d.DeliveredAt,
+			&hook.UserID, &hook.URL, &hook.Secret, &hook.CreatedAt)
+		if err != nil {
+			rows.Close()
+			r.log.Error("Error get due webhook deliveries", zap.Error(err))
+			return 0, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+		}
+		hook.ID = d.WebhookID
+		deliveries = append(deliveries, d)
+		hooks = append(hooks, hook)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		r.log.Error("Error get due webhook deliveries", zap.Error(err))
+		return 0, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	for i := range deliveries {
+		d := &deliveries[i]
+		fn(ctx, d, hooks[i])
+		_, err := tx.Exec(ctx, updateDeliveryQuery, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.ResponseCode, d.DeliveredAt)
+		if err != nil {
+			r.log.Error("Error update webhook delivery", zap.Error(err))
+			return 0, fmt.Errorf("failed to update webhook delivery: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		r.log.Error("Error commit transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(deliveries), nil
+}
+
+func scanDelivery(row pgx.Row, d *models.WebhookDelivery) error {
+	return row.Scan(&d.ID, &d.WebhookID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastError, &d.ResponseCode, &d.CreatedAt, &d.DeliveredAt)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks_CreateGetDelete(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	first := &models.Webhook{UserID: 1, URL: "https://example.com/a", Secret: "s1"}
+	second := &models.Webhook{UserID: 1, URL: "https://example.com/b", Secret: "s2"}
+	foreign := &models.Webhook{UserID: 2, URL: "https://example.com/c", Secret: "s3"}
+	for _, hook := range []*models.Webhook{first, second, foreign} {
+		require.NoError(t, repo.CreateWebhook(ctx, hook))
+		require.NotZero(t, hook.ID)
+		require.False(t, hook.CreatedAt.IsZero())
+	}
+
+	hooks, err := repo.GetWebhooks(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, hooks, 2)
+	require.Equal(t, first.URL, hooks[0].URL)
+	require.Equal(t, second.URL, hooks[1].URL)
+	require.Empty(t, hooks[1].Secret)
+
+	require.ErrorIs(t, repo.DeleteWebhook(ctx, &models.Webhook{ID: foreign.ID, UserID: 1}), models.ErrNotFound)
+	require.NoError(t, repo.DeleteWebhook(ctx, first))
+	hooks, err = repo.GetWebhooks(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	require.Equal(t, second.ID, hooks[0].ID)
+
+	hooks, err = repo.GetWebhooks(ctx, 3)
+	require.NoError(t, err)
+	require.Empty(t, hooks)
+}
+
+func TestWebhooks_Deliveries(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	first := &models.Webhook{UserID: 1, URL: "https://example.com/a", Secret: "s1"}
+	second := &models.Webhook{UserID: 1, URL: "https://example.com/b", Secret: "s2"}
+	require.NoError(t, repo.CreateWebhook(ctx, first))
+	require.NoError(t, repo.CreateWebhook(ctx, second))
+
+	queued, err := repo.EnqueueDeliveries(ctx, 1, models.NotificationEventCreated, []byte(`{"ID":1}`))
+	require.NoError(t, err)
+	require.Equal(t, 2, queued)
+	queued, err = repo.EnqueueDeliveries(ctx, 2, models.NotificationEventCreated, []byte(`{"ID":2}`))
+	require.NoError(t, err)
+	require.Zero(t, queued)
+
+	deliveries, err := repo.GetDeliveries(ctx, 1, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Greater(t, deliveries[0].ID, deliveries[1].ID)
+	require.Equal(t, models.DeliveryPending, deliveries[0].Status)
+	require.JSONEq(t, `{"ID":1}`, string(deliveries[0].Payload))
+
+	deliveries, err = repo.GetDeliveries(ctx, 1, first.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, first.ID, deliveries[0].WebhookID)
+	deliveries, err = repo.GetDeliveries(ctx, 2, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	// Deliver the first attempt to each hook: one succeeds, one is rescheduled.
+	now := time.Now().Add(time.Second)
+	processed, err := repo.ProcessDueDeliveries(ctx, now, 10, func(ctx context.Context, d *models.WebhookDelivery, hook models.Webhook) {
+		require.Equal(t, int64(1), hook.UserID)
+		d.Attempts++
+		if hook.ID == first.ID {
+			d.Status = models.DeliveryDelivered
+			d.ResponseCode = 200
+			d.DeliveredAt = &now
+			return
+		}
+		d.LastError = "timeout"
+		d.NextAttemptAt = now.Add(time.Minute)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, processed)
+
+	processed, err = repo.ProcessDueDeliveries(ctx, now, 10, func(ctx context.Context, d *models.WebhookDelivery, hook models.Webhook) {
+		t.Fatalf("delivery %d is not due", d.ID)
+	})
+	require.NoError(t, err)
+	require.Zero(t, processed)
+
+	delivered, err := repo.GetDeliveries(ctx, 1, first.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, models.DeliveryDelivered, delivered[0].Status)
+	require.Equal(t, 1, delivered[0].Attempts)
+	require.Equal(t, 200, delivered[0].ResponseCode)
+	require.NotNil(t, delivered[0].DeliveredAt)
+	retried, err := repo.GetDeliveries(ctx, 1, second.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, models.DeliveryPending, retried[0].Status)
+	require.Equal(t, "timeout", retried[0].LastError)
+
+	_, err = repo.Redeliver(ctx, 2, delivered[0].ID)
+	require.ErrorIs(t, err, models.ErrNotFound)
+	copied, err := repo.Redeliver(ctx, 1, delivered[0].ID)
+	require.NoError(t, err)
+	require.NotEqual(t, delivered[0].ID, copied.ID)
+	require.Equal(t, first.ID, copied.WebhookID)
+	require.Equal(t, models.DeliveryPending, copied.Status)
+	require.Zero(t, copied.Attempts)
+	require.JSONEq(t, `{"ID":1}`, string(copied.Payload))
+
+	deliveries, err = repo.GetDeliveries(ctx, 1, first.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	deliveries, err = repo.GetDeliveries(ctx, 1, 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int64{copied.ID}, []int64{deliveries[0].ID})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"go.uber.org/zap"
+)
+
+// GraphQLExecutor runs GraphQL requests; the result has no data when a request did not run.
+type GraphQLExecutor interface {
+	Execute(ctx context.Context, query, operationName string, variables map[string]any) *graphql.Result
+}
+
+type GraphQLHandler struct {
+	executor GraphQLExecutor
+}
+
+func NewGraphQLHandler(executor GraphQLExecutor) *GraphQLHandler {
+	return &GraphQLHandler{executor: executor}
+}
+
+// Query answers POST /graphql with the data and errors of a query or mutation. A request
+// that does not parse, validate or fit the complexity limit gets 400; errors of single
+// fields are reported next to the data with 200.
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GraphQL handler called")
+
+	var req models.GraphQLRequest
+	// Numbers stay exact, so large ids in variables are not rounded to floats.
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&req); err != nil || req.Query == "" {
+		log.Error("Invalid GraphQL request", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid GraphQL request"})
+		return
+	}
+	result := h.executor.Execute(c.Request.Context(), req.Query, req.OperationName, req.Variables)
+	if result.Data == nil {
+		log.Info("GraphQL request rejected", zap.Any("errors", result.Errors))
+		c.JSON(400, result)
+		return
+	}
+	if result.HasErrors() {
+		log.Info("GraphQL request completed with errors", zap.Int("error_count", len(result.Errors)))
+	} else {
+		log.Info("GraphQL request completed successfully")
+	}
+	c.JSON(200, result)
+}
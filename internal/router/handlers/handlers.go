@@ -4,18 +4,23 @@ import (
 	"awesomeProject/internal/models"
 	"awesomeProject/internal/service"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"io"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type CalendarHandler struct {
 	calendarService *service.CalendarService
+	taskService     *service.TaskService
 }
 
-func NewCalendarHandler(calendarService *service.CalendarService) *CalendarHandler {
-	return &CalendarHandler{calendarService: calendarService}
+func NewCalendarHandler(calendarService *service.CalendarService, taskService *service.TaskService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService, taskService: taskService}
 }
 
 func (h *CalendarHandler) CreateEvent(c *gin.Context) {
@@ -44,18 +49,58 @@ func (h *CalendarHandler) CreateEvent(c *gin.Context) {
 		return
 	}
 	serviceEvent := &models.Event{
-		UserID: req.UserID,
-		Date:   dateTime,
-		Event:  req.Event,
+		UserID:       req.UserID,
+		Date:         dateTime,
+		Event:        req.Event,
+		Transparency: req.Transparency,
+		Visibility:   req.Visibility,
+		RRule:        req.RRule,
+		Attendees:    toAttendees(req.Attendees),
+	}
+	loc, err := h.calendarService.Location(c.Request.Context(), req.UserID)
+	if err != nil {
+		log.Error("Failed to get time zone", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to create event"})
+		return
+	}
+	if err := parseEventTimes(req, serviceEvent, loc); err != nil {
+		log.Error("Invalid event time", zap.String("start_time", req.StartTime), zap.String("end_time", req.EndTime), zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid start_time/end_time. Use HH:MM or RFC 3339 and set both"})
+		return
+	}
+	policy, err := service.ParseConflictPolicy(req.ConflictPolicy)
+	if err != nil {
+		log.Error("Invalid conflict policy", zap.String("conflict_policy", req.ConflictPolicy))
+		c.JSON(400, gin.H{"error": "Invalid conflict_policy. Use warn, reject or force"})
+		return
+	}
+	conflicts, err := h.calendarService.CreateEventWithPolicy(c.Request.Context(), serviceEvent, policy)
+	if errors.Is(err, service.ErrInvalidEvent) {
+		log.Error("Invalid event", zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrConflict) {
+		log.Error("Event conflicts with existing events", zap.Int("conflict_count", len(conflicts)))
+		c.JSON(409, gin.H{"error": "Event conflicts with existing events", "conflicts": conflicts})
+		return
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Resource not found", zap.Error(err))
+		c.JSON(404, gin.H{"error": "Resource not found"})
+		return
 	}
-	err = h.calendarService.CreateEvent(c.Request.Context(), serviceEvent)
 	if err != nil {
 		log.Error("Failed to create event", zap.Error(err))
 		c.JSON(503, gin.H{"error": "Failed to create event"})
 		return
 	}
 	log.Info("Event created successfully", zap.Int64("user_id", serviceEvent.UserID), zap.Time("date", dateTime), zap.String("event", serviceEvent.Event))
-	c.JSON(200, gin.H{"result": "Event created successfully"})
+	resp := gin.H{"result": "Event created successfully", "id": serviceEvent.ID}
+	if len(conflicts) > 0 {
+		resp["conflicts"] = conflicts
+	}
+	c.JSON(200, resp)
 }
 func (h *CalendarHandler) UpdateEvent(c *gin.Context) {
 	log := c.Value("logger").(*zap.Logger)
@@ -67,8 +112,8 @@ func (h *CalendarHandler) UpdateEvent(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Invalid request body"}) // Какой код возвращать?
 		return
 	}
-	if req.UserID <= 0 || req.Event == "" || req.Date == "" {
-		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.String("date", req.Date), zap.String("event", req.Event))
+	if req.ID <= 0 || req.UserID <= 0 || req.Event == "" || req.Date == "" {
+		log.Error("Missing required parameters", zap.Int64("id", req.ID), zap.Int64("user_id", req.UserID), zap.String("date", req.Date), zap.String("event", req.Event))
 		c.JSON(400, gin.H{"error": "Missing required parameters"})
 		return
 	}
@@ -82,18 +127,64 @@ func (h *CalendarHandler) UpdateEvent(c *gin.Context) {
 		return
 	}
 	serviceEvent := &models.Event{
-		UserID: req.UserID,
-		Date:   dateTime,
-		Event:  req.Event,
+		ID:           req.ID,
+		UserID:       req.UserID,
+		Date:         dateTime,
+		Event:        req.Event,
+		Transparency: req.Transparency,
+		Visibility:   req.Visibility,
+		RRule:        req.RRule,
+		Attendees:    toAttendees(req.Attendees),
+	}
+	loc, err := h.calendarService.Location(c.Request.Context(), req.UserID)
+	if err != nil {
+		log.Error("Failed to get time zone", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to update event"})
+		return
+	}
+	if err := parseEventTimes(req, serviceEvent, loc); err != nil {
+		log.Error("Invalid event time", zap.String("start_time", req.StartTime), zap.String("end_time", req.EndTime), zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid start_time/end_time. Use HH:MM or RFC 3339 and set both"})
+		return
+	}
+	policy, err := service.ParseConflictPolicy(req.ConflictPolicy)
+	if err != nil {
+		log.Error("Invalid conflict policy", zap.String("conflict_policy", req.ConflictPolicy))
+		c.JSON(400, gin.H{"error": "Invalid conflict_policy. Use warn, reject or force"})
+		return
+	}
+	conflicts, err := h.calendarService.UpdateEventWithPolicy(c.Request.Context(), serviceEvent, policy)
+	if errors.Is(err, service.ErrInvalidEvent) {
+		log.Error("Invalid event", zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Event or resource not found", zap.Int64("id", req.ID))
+		c.JSON(404, gin.H{"error": "Event or resource not found"})
+		return
+	}
+	if errors.Is(err, models.ErrForbidden) {
+		log.Error("Only the organizer can update the event", zap.Int64("id", req.ID), zap.Int64("user_id", req.UserID))
+		c.JSON(403, gin.H{"error": "Only the organizer can update the event"})
+		return
+	}
+	if errors.Is(err, models.ErrConflict) {
+		log.Error("Event conflicts with existing events", zap.Int("conflict_count", len(conflicts)))
+		c.JSON(409, gin.H{"error": "Event conflicts with existing events", "conflicts": conflicts})
+		return
 	}
-	err = h.calendarService.UpdateEvent(c.Request.Context(), serviceEvent)
 	if err != nil {
 		log.Error("Failed to update event", zap.Error(err))
 		c.JSON(503, gin.H{"error": "Failed to update event"})
 		return
 	}
 	log.Info("Event updated successfully", zap.Int64("user_id", serviceEvent.UserID), zap.Time("date", dateTime), zap.String("event", serviceEvent.Event))
-	c.JSON(200, gin.H{"result": "Event updated successfully"})
+	resp := gin.H{"result": "Event updated successfully", "id": serviceEvent.ID}
+	if len(conflicts) > 0 {
+		resp["conflicts"] = conflicts
+	}
+	c.JSON(200, resp)
 }
 func (h *CalendarHandler) DeleteEvent(c *gin.Context) {
 	log := c.Value("logger").(*zap.Logger)
@@ -106,25 +197,40 @@ func (h *CalendarHandler) DeleteEvent(c *gin.Context) {
 		return
 	}
 
-	if req.UserID <= 0 || req.Date == "" {
+	if req.UserID <= 0 || (req.Date == "" && req.ID <= 0) {
 		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.String("date", req.Date))
 		c.JSON(400, gin.H{"error": "Missing required parameters"})
 		return
 	}
 
-	log.Info("Received DeleteEvent request", zap.Int64("user_id", req.UserID), zap.String("date", req.Date))
+	log.Info("Received DeleteEvent request", zap.Int64("user_id", req.UserID), zap.String("date", req.Date), zap.Int64("id", req.ID))
 
-	dateTime, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		log.Error("Invalid date format", zap.String("date", req.Date), zap.Error(err))
-		c.JSON(400, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
-		return
+	var dateTime time.Time
+	if req.Date != "" {
+		var err error
+		dateTime, err = time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			log.Error("Invalid date format", zap.String("date", req.Date), zap.Error(err))
+			c.JSON(400, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
 	}
 	serviceEvent := &models.Event{
+		ID:     req.ID,
 		UserID: req.UserID,
 		Date:   dateTime,
 	}
-	err = h.calendarService.DeleteEvent(c.Request.Context(), serviceEvent)
+	err := h.calendarService.DeleteEvent(c.Request.Context(), serviceEvent)
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Event not found", zap.Int64("id", req.ID))
+		c.JSON(404, gin.H{"error": "Event not found"})
+		return
+	}
+	if errors.Is(err, models.ErrForbidden) {
+		log.Error("Only the organizer can delete the event", zap.Int64("id", req.ID), zap.Int64("user_id", req.UserID))
+		c.JSON(403, gin.H{"error": "Only the organizer can delete the event"})
+		return
+	}
 	if err != nil {
 		log.Error("Failed to delete event", zap.Error(err))
 		c.JSON(503, gin.H{"error": "Failed to delete event"})
@@ -134,6 +240,75 @@ func (h *CalendarHandler) DeleteEvent(c *gin.Context) {
 	c.JSON(200, gin.H{"result": "Event deleted successfully"})
 }
 
+func (h *CalendarHandler) RespondToEvent(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("RespondToEvent handler called")
+	req := &models.RSVPRequest{}
+
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.EventID <= 0 || req.Status == "" {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.Int64("event_id", req.EventID), zap.String("status", req.Status))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	err := h.calendarService.RespondToEvent(c.Request.Context(), req.EventID, req.UserID, req.Status)
+	if errors.Is(err, service.ErrInvalidResponse) {
+		log.Error("Invalid response status", zap.String("status", req.Status))
+		c.JSON(400, gin.H{"error": "Invalid status. Use accepted, declined, tentative or needs-action"})
+		return
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Invitation not found", zap.Int64("event_id", req.EventID), zap.Int64("user_id", req.UserID))
+		c.JSON(404, gin.H{"error": "Invitation not found"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to respond to event", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to respond to event"})
+		return
+	}
+	log.Info("Responded to event", zap.Int64("event_id", req.EventID), zap.Int64("user_id", req.UserID), zap.String("status", req.Status))
+	c.JSON(200, gin.H{"result": "Response recorded"})
+}
+
+// maxReplySize bounds the size of an inbound iTIP reply.
+const maxReplySize = 1 << 20
+
+// ITIPReply accepts a raw text/calendar REPLY, e.g. forwarded by an inbound mail gateway.
+func (h *CalendarHandler) ITIPReply(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("ITIPReply handler called")
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxReplySize))
+	if err != nil {
+		log.Error("Failed to read request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	err = h.calendarService.ApplyReply(c.Request.Context(), data)
+	if errors.Is(err, service.ErrInvalidReply) {
+		log.Error("Invalid iTIP reply", zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Event or attendee not found", zap.Error(err))
+		c.JSON(404, gin.H{"error": "Event or attendee not found"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to apply iTIP reply", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to apply reply"})
+		return
+	}
+	log.Info("iTIP reply applied")
+	c.JSON(200, gin.H{"result": "Reply applied"})
+}
+
 func (h *CalendarHandler) GetEventsForDay(c *gin.Context) {
 	log := c.Value("logger").(*zap.Logger)
 	log.Info("GetEventsForDay handler called")
@@ -171,7 +346,7 @@ func (h *CalendarHandler) GetEventsForDay(c *gin.Context) {
 		return
 	}
 	log.Info("Events retrieved successfully", zap.Int64("user_id", serviceEvent.UserID), zap.Time("date", dateTime), zap.Int("event_count", len(events)))
-	c.JSON(200, gin.H{"result": events})
+	h.respondWithView(c, log, events, userID, dateTime, dateTime.AddDate(0, 0, 1))
 }
 
 func (h *CalendarHandler) GetEventsForWeek(c *gin.Context) {
@@ -210,7 +385,7 @@ func (h *CalendarHandler) GetEventsForWeek(c *gin.Context) {
 		return
 	}
 	log.Info("Events retrieved successfully", zap.Int64("user_id", serviceEvent.UserID), zap.Time("date", dateTime), zap.Int("event_count", len(events)))
-	c.JSON(200, gin.H{"result": events})
+	h.respondWithView(c, log, events, userID, dateTime, dateTime.AddDate(0, 0, 7))
 }
 func (h *CalendarHandler) GetEventsForMonth(c *gin.Context) {
 	log := c.Value("logger").(*zap.Logger)
@@ -248,5 +423,100 @@ func (h *CalendarHandler) GetEventsForMonth(c *gin.Context) {
 		return
 	}
 	log.Info("Events retrieved successfully", zap.Int64("user_id", serviceEvent.UserID), zap.Time("date", dateTime), zap.Int("event_count", len(events)))
-	c.JSON(200, gin.H{"result": events})
+	h.respondWithView(c, log, events, userID, dateTime, addMonth(dateTime))
+}
+
+// respondWithView writes the events of a day/week/month view, adding the tasks due in
+// [from, to) when the request asks for them with include_tasks=true.
+func (h *CalendarHandler) respondWithView(c *gin.Context, log *zap.Logger, events []models.Event, userID int64, from, to time.Time) {
+	includeTasks, err := strconv.ParseBool(c.DefaultQuery("include_tasks", "false"))
+	if err != nil {
+		log.Error("Invalid include_tasks", zap.String("include_tasks", c.Query("include_tasks")))
+		c.JSON(400, gin.H{"error": "Invalid include_tasks parameter"})
+		return
+	}
+	if !includeTasks {
+		c.JSON(200, gin.H{"result": events})
+		return
+	}
+	if h.taskService == nil {
+		log.Error("Tasks are not available with the configured storage")
+		c.JSON(501, gin.H{"error": "Tasks are not available with the configured storage"})
+		return
+	}
+	tasks, err := h.taskService.GetTasks(c.Request.Context(), userID, from, to, true)
+	if err != nil {
+		log.Error("Failed to get tasks", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get tasks"})
+		return
+	}
+	c.JSON(200, gin.H{"result": events, "tasks": tasks})
+}
+
+// addMonth adds a calendar month, clamping to the last day of a shorter month like the
+// repositories do, so the tasks of a month view cover the same days as its events.
+func addMonth(t time.Time) time.Time {
+	next := t.AddDate(0, 1, 0)
+	if next.Day() != t.Day() {
+		next = next.AddDate(0, 0, -next.Day())
+	}
+	return next
+}
+
+// parseEventTimes sets Start and End from the optional start_time/end_time. RFC 3339 times
+// are taken as given; HH:MM is a wall clock time on the event date in loc, the organizer's
+// time zone.
+func parseEventTimes(req *models.EventRequest, event *models.Event, loc *time.Location) error {
+	if req.StartTime == "" && req.EndTime == "" {
+		return nil
+	}
+	start, err := parseEventTime(req.StartTime, event.Date, loc)
+	if err != nil {
+		return err
+	}
+	end, err := parseEventTime(req.EndTime, event.Date, loc)
+	if err != nil {
+		return err
+	}
+	event.Start, event.End = start, end
+	return nil
+}
+
+func parseEventTime(s string, day time.Time, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	clock, err := time.Parse("15:04", s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc).UTC(), nil
+}
+
+// parseUserIDs parses a comma separated list of positive user ids.
+func parseUserIDs(s string) ([]int64, error) {
+	var userIDs []int64
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid user id %d", id)
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, nil
+}
+
+// toAttendees keeps a missing list nil so that updates leave the stored attendees alone.
+func toAttendees(reqs []models.AttendeeRequest) []models.Attendee {
+	if reqs == nil {
+		return nil
+	}
+	attendees := make([]models.Attendee, 0, len(reqs))
+	for _, r := range reqs {
+		attendees = append(attendees, models.Attendee{UserID: r.UserID, ResourceID: r.ResourceID, Email: r.Email})
+	}
+	return attendees
 }
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/repository/memory"
+	"awesomeProject/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestEngine serves the handlers with the request logger the logging middleware sets.
+func newTestEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("logger", zap.NewNop())
+	})
+	return r
+}
+
+func post(r http.Handler, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	return w
+}
+
+func TestCalendarHandler_UpdateEvent_ChangesOnlyTargetedEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository(zap.NewNop())
+	svc := service.NewCalendarService(repo, zap.NewNop())
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	first := &models.Event{UserID: 1, Date: day, Event: "standup"}
+	second := &models.Event{UserID: 1, Date: day, Event: "review"}
+	require.NoError(t, svc.CreateEvent(ctx, first))
+	require.NoError(t, svc.CreateEvent(ctx, second))
+
+	r := newTestEngine()
+	r.POST("/update_event", NewCalendarHandler(svc, nil).UpdateEvent)
+
+	w := post(r, "/update_event", `{"user_id": 1, "date": "2025-06-02", "event": "renamed"}`)
+	require.Equal(t, 400, w.Code, w.Body.String())
+
+	w = post(r, "/update_event", fmt.Sprintf(`{"id": %d, "user_id": 1, "date": "2025-06-02", "event": "renamed"}`, first.ID))
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	got, err := repo.GetEvent(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", got.Event)
+	got, err = repo.GetEvent(ctx, second.ID)
+	require.NoError(t, err)
+	require.Equal(t, "review", got.Event)
+}
+
+func TestParseEventTimes(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	// HH:MM is read in the organizer's time zone.
+	event := &models.Event{Date: day}
+	require.NoError(t, parseEventTimes(&models.EventRequest{StartTime: "09:00", EndTime: "10:30"}, event, berlin))
+	require.Equal(t, time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC), event.Start)
+	require.Equal(t, time.Date(2025, 6, 2, 8, 30, 0, 0, time.UTC), event.End)
+
+	// RFC 3339 carries its own offset.
+	event = &models.Event{Date: day}
+	require.NoError(t, parseEventTimes(&models.EventRequest{StartTime: "2025-06-02T09:00:00-04:00", EndTime: "2025-06-02T10:00:00-04:00"}, event, berlin))
+	require.Equal(t, time.Date(2025, 6, 2, 13, 0, 0, 0, time.UTC), event.Start)
+
+	require.Error(t, parseEventTimes(&models.EventRequest{StartTime: "09:00"}, &models.Event{Date: day}, time.UTC))
+	require.Error(t, parseEventTimes(&models.EventRequest{StartTime: "9am", EndTime: "10am"}, &models.Event{Date: day}, time.UTC))
+}
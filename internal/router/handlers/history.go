@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+type HistoryHandler struct {
+	historyService *service.HistoryService
+}
+
+func NewHistoryHandler(historyService *service.HistoryService) *HistoryHandler {
+	return &HistoryHandler{historyService: historyService}
+}
+
+// GetHistory answers /event_history?id=5&user_id=1 with the changes of the event.
+func (h *HistoryHandler) GetHistory(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GetHistory handler called")
+
+	userID, errUser := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	eventID, errEvent := strconv.ParseInt(c.Query("id"), 10, 64)
+	if errUser != nil || errEvent != nil || userID <= 0 || eventID <= 0 {
+		log.Error("Invalid or missing parameters", zap.String("user_id", c.Query("user_id")), zap.String("id", c.Query("id")))
+		c.JSON(400, gin.H{"error": "Invalid or missing id or user_id parameter"})
+		return
+	}
+	entries, err := h.historyService.GetHistory(c.Request.Context(), userID, eventID)
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Event has no history", zap.Int64("id", eventID))
+		c.JSON(404, gin.H{"error": "Event not found"})
+		return
+	}
+	if errors.Is(err, models.ErrForbidden) {
+		log.Error("User is not part of the event", zap.Int64("id", eventID), zap.Int64("user_id", userID))
+		c.JSON(403, gin.H{"error": "Only the organizer and attendees can see the history"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get history", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get history"})
+		return
+	}
+	log.Info("History retrieved successfully", zap.Int64("id", eventID), zap.Int("entry_count", len(entries)))
+	c.JSON(200, gin.H{"result": entries})
+}
+
+func (h *HistoryHandler) RevertEvent(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("RevertEvent handler called")
+
+	req := &models.RevertRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.ID <= 0 || req.UserID <= 0 || req.Version <= 0 {
+		log.Error("Missing required parameters", zap.Int64("id", req.ID), zap.Int64("user_id", req.UserID), zap.Int64("version", req.Version))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	event, err := h.historyService.RevertEvent(c.Request.Context(), req.UserID, req.ID, req.Version)
+	if errors.Is(err, service.ErrInvalidVersion) || errors.Is(err, service.ErrInvalidEvent) {
+		log.Error("Cannot revert to version", zap.Int64("version", req.Version), zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Event or version not found", zap.Int64("id", req.ID), zap.Int64("version", req.Version))
+		c.JSON(404, gin.H{"error": "Event or version not found"})
+		return
+	}
+	if errors.Is(err, models.ErrForbidden) {
+		log.Error("Only the organizer can revert the event", zap.Int64("id", req.ID), zap.Int64("user_id", req.UserID))
+		c.JSON(403, gin.H{"error": "Only the organizer can revert the event"})
+		return
+	}
+	if errors.Is(err, models.ErrConflict) {
+		log.Error("Reverted event would double-book the calendar", zap.Int64("id", req.ID))
+		c.JSON(409, gin.H{"error": "Event conflicts with existing events"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to revert event", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to revert event"})
+		return
+	}
+	log.Info("Event reverted successfully", zap.Int64("id", req.ID), zap.Int64("version", req.Version))
+	c.JSON(200, gin.H{"result": event})
+}
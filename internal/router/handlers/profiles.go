@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ProfileHandler struct {
+	profileService *service.ProfileService
+}
+
+func NewProfileHandler(profileService *service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("UpdateProfile handler called")
+
+	req := &models.ProfileRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	profile := &models.Profile{
+		UserID:          req.UserID,
+		Email:           req.Email,
+		Name:            req.Name,
+		TimeZone:        req.TimeZone,
+		NoDoubleBooking: req.NoDoubleBooking,
+	}
+	var err error
+	if profile.WorkingHours, err = parseWorkingHours(req.WorkingHours); err != nil {
+		log.Error("Invalid working hours", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid working_hours. Use a weekday name and HH:MM start/end times"})
+		return
+	}
+	if profile.OutOfOffice, err = parseOutOfOffice(req.OutOfOffice); err != nil {
+		log.Error("Invalid out of office", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid out_of_office. Use RFC 3339 start/end timestamps"})
+		return
+	}
+	err = h.profileService.UpdateProfile(c.Request.Context(), profile)
+	if errors.Is(err, service.ErrInvalidEmail) {
+		log.Error("Invalid email", zap.String("email", req.Email))
+		c.JSON(400, gin.H{"error": "Invalid email address"})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidTimeZone) {
+		log.Error("Invalid time zone", zap.String("time_zone", req.TimeZone))
+		c.JSON(400, gin.H{"error": "Invalid time zone. Use an IANA name like Europe/Berlin"})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidWorkingHours) {
+		log.Error("Invalid working hours", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid working_hours: one interval per weekday with start before end"})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidOutOfOffice) {
+		log.Error("Invalid out of office", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid out_of_office: end must be after start and the message at most 1000 bytes"})
+		return
+	}
+	if errors.Is(err, models.ErrConflict) {
+		log.Error("Existing events overlap", zap.Int64("user_id", req.UserID))
+		c.JSON(409, gin.H{"error": "Cannot enable no_double_booking: existing events overlap"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to update profile", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to update profile"})
+		return
+	}
+	log.Info("Profile updated successfully", zap.Int64("user_id", req.UserID))
+	c.JSON(200, gin.H{"result": profile})
+}
+
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GetProfile handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	profile, err := h.profileService.GetProfile(c.Request.Context(), userID)
+	if errors.Is(err, models.ErrNotFound) {
+		c.JSON(404, gin.H{"error": "Profile not found"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get profile", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get profile"})
+		return
+	}
+	c.JSON(200, gin.H{"result": profile})
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// parseWorkingHours keeps a missing list nil so that the stored hours are left alone.
+func parseWorkingHours(reqs []models.WorkingHoursRequest) ([]models.WorkingHours, error) {
+	if reqs == nil {
+		return nil, nil
+	}
+	hours := make([]models.WorkingHours, 0, len(reqs))
+	for _, r := range reqs {
+		weekday, ok := weekdays[strings.ToLower(r.Weekday)]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", r.Weekday)
+		}
+		start, err := parseMinutes(r.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseMinutes(r.End)
+		if err != nil {
+			return nil, err
+		}
+		hours = append(hours, models.WorkingHours{Weekday: weekday, StartMinute: start, EndMinute: end})
+	}
+	return hours, nil
+}
+
+// parseMinutes turns HH:MM into minutes since midnight; 24:00 is the end of the day.
+func parseMinutes(s string) (int, error) {
+	if s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func parseOutOfOffice(reqs []models.OutOfOfficeRequest) ([]models.OutOfOffice, error) {
+	if reqs == nil {
+		return nil, nil
+	}
+	periods := make([]models.OutOfOffice, 0, len(reqs))
+	for _, r := range reqs {
+		start, err := time.Parse(time.RFC3339, r.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := time.Parse(time.RFC3339, r.End)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, models.OutOfOffice{Start: start, End: end, Message: r.Message})
+	}
+	return periods, nil
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+type ReminderHandler struct {
+	reminderService *service.ReminderService
+}
+
+func NewReminderHandler(reminderService *service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{reminderService: reminderService}
+}
+
+func (h *ReminderHandler) CreateReminder(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("CreateReminder handler called")
+
+	req := &models.ReminderRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.EventID <= 0 || req.Before == "" {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.Int64("event_id", req.EventID), zap.String("before", req.Before))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	before, err := service.ParseReminderOffset(req.Before)
+	if err != nil {
+		log.Error("Invalid reminder offset", zap.String("before", req.Before), zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid before format. Use e.g. 15m, 2h, 1d"})
+		return
+	}
+	reminder := &models.Reminder{
+		UserID:  req.UserID,
+		EventID: req.EventID,
+		Before:  before,
+	}
+	err = h.reminderService.CreateReminder(c.Request.Context(), reminder)
+	switch {
+	case errors.Is(err, service.ErrInvalidReminder):
+		log.Error("Invalid reminder offset", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Reminder offset must be whole minutes and at most 4 weeks"})
+		return
+	case errors.Is(err, models.ErrNotFound):
+		log.Error("Event not found", zap.Int64("event_id", req.EventID))
+		c.JSON(404, gin.H{"error": "Event not found"})
+		return
+	case err != nil:
+		log.Error("Failed to create reminder", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to create reminder"})
+		return
+	}
+	log.Info("Reminder created successfully", zap.Int64("id", reminder.ID))
+	c.JSON(200, gin.H{"result": reminder})
+}
+
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("DeleteReminder handler called")
+
+	req := &models.ReminderRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.ID <= 0 {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.Int64("id", req.ID))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	err := h.reminderService.DeleteReminder(c.Request.Context(), &models.Reminder{ID: req.ID, UserID: req.UserID})
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Reminder not found", zap.Int64("id", req.ID))
+		c.JSON(404, gin.H{"error": "Reminder not found"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to delete reminder", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to delete reminder"})
+		return
+	}
+	log.Info("Reminder deleted successfully", zap.Int64("id", req.ID))
+	c.JSON(200, gin.H{"result": "Reminder deleted successfully"})
+}
+
+func (h *ReminderHandler) GetReminders(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GetReminders handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	var eventID int64
+	if s := c.Query("event_id"); s != "" {
+		eventID, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || eventID <= 0 {
+			log.Error("Invalid event_id", zap.String("event_id", s))
+			c.JSON(400, gin.H{"error": "Invalid event_id parameter"})
+			return
+		}
+	}
+	reminders, err := h.reminderService.GetReminders(c.Request.Context(), userID, eventID)
+	if err != nil {
+		log.Error("Failed to get reminders", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get reminders"})
+		return
+	}
+	log.Info("Reminders retrieved successfully", zap.Int64("user_id", userID), zap.Int("reminder_count", len(reminders)))
+	c.JSON(200, gin.H{"result": reminders})
+}
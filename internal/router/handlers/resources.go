@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ResourceHandler struct {
+	resourceService *service.ResourceService
+}
+
+func NewResourceHandler(resourceService *service.ResourceService) *ResourceHandler {
+	return &ResourceHandler{resourceService: resourceService}
+}
+
+func (h *ResourceHandler) CreateResource(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("CreateResource handler called")
+
+	req := &models.ResourceRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.Kind == "" || req.Name == "" {
+		log.Error("Missing required parameters", zap.String("kind", req.Kind), zap.String("name", req.Name))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	resource := &models.Resource{Kind: req.Kind, Name: req.Name, Capacity: req.Capacity, Attributes: req.Attributes}
+	err := h.resourceService.CreateResource(c.Request.Context(), resource)
+	if errors.Is(err, service.ErrInvalidResource) {
+		log.Error("Invalid resource", zap.String("kind", req.Kind), zap.Int("capacity", req.Capacity))
+		c.JSON(400, gin.H{"error": "Invalid resource. Kind must be room, projector or car and capacity non-negative"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to create resource", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to create resource"})
+		return
+	}
+	log.Info("Resource created successfully", zap.Int64("id", resource.ID))
+	c.JSON(200, gin.H{"result": resource})
+}
+
+func (h *ResourceHandler) DeleteResource(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("DeleteResource handler called")
+
+	req := &models.ResourceRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.ID <= 0 {
+		log.Error("Missing required parameters", zap.Int64("id", req.ID))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	err := h.resourceService.DeleteResource(c.Request.Context(), req.ID)
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Resource not found", zap.Int64("id", req.ID))
+		c.JSON(404, gin.H{"error": "Resource not found"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to delete resource", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to delete resource"})
+		return
+	}
+	log.Info("Resource deleted successfully", zap.Int64("id", req.ID))
+	c.JSON(200, gin.H{"result": "Resource deleted successfully"})
+}
+
+func (h *ResourceHandler) GetResources(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GetResources handler called")
+
+	filter, err := parseResourceFilter(c)
+	if err != nil {
+		log.Error("Invalid resource filter", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid resource filter: " + err.Error()})
+		return
+	}
+	resources, err := h.resourceService.GetResources(c.Request.Context(), filter)
+	if err != nil {
+		log.Error("Failed to get resources", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get resources"})
+		return
+	}
+	c.JSON(200, gin.H{"result": resources})
+}
+
+// FreeResources answers queries like
+// /free_resources?kind=room&min_capacity=8&from=2025-06-02T14:00:00Z&to=2025-06-02T15:00:00Z.
+func (h *ResourceHandler) FreeResources(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("FreeResources handler called")
+
+	filter, err := parseResourceFilter(c)
+	if err != nil {
+		log.Error("Invalid resource filter", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid resource filter: " + err.Error()})
+		return
+	}
+	from, errFrom := time.Parse(time.RFC3339, c.Query("from"))
+	to, errTo := time.Parse(time.RFC3339, c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		log.Error("Invalid time window", zap.String("from", c.Query("from")), zap.String("to", c.Query("to")))
+		c.JSON(400, gin.H{"error": "Invalid from/to. Use RFC 3339 timestamps"})
+		return
+	}
+	resources, err := h.resourceService.FindFreeResources(c.Request.Context(), filter, from, to)
+	if errors.Is(err, service.ErrInvalidWindow) {
+		log.Error("Invalid time window", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid time window: to must be after from and at most 62 days later"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to find free resources", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to find free resources"})
+		return
+	}
+	log.Info("Free resources found", zap.Int("resource_count", len(resources)))
+	c.JSON(200, gin.H{"result": resources})
+}
+
+// parseResourceFilter reads kind, min_capacity and repeated attr=key:value parameters.
+func parseResourceFilter(c *gin.Context) (models.ResourceFilter, error) {
+	filter := models.ResourceFilter{Kind: c.Query("kind")}
+	if s := c.Query("min_capacity"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return filter, errors.New("min_capacity must be a non-negative integer")
+		}
+		filter.MinCapacity = n
+	}
+	for _, attr := range c.QueryArray("attr") {
+		key, value, ok := strings.Cut(attr, ":")
+		if !ok || key == "" {
+			return filter, errors.New("use attr=key:value")
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]string)
+		}
+		filter.Attributes[key] = value
+	}
+	return filter, nil
+}
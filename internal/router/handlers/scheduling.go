@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"awesomeProject/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+type SchedulingHandler struct {
+	schedulingService *service.SchedulingService
+}
+
+func NewSchedulingHandler(schedulingService *service.SchedulingService) *SchedulingHandler {
+	return &SchedulingHandler{schedulingService: schedulingService}
+}
+
+func (h *SchedulingHandler) FindSlots(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("FindSlots handler called")
+
+	userIDs, err := parseUserIDs(c.Query("user_ids"))
+	if err != nil {
+		log.Error("Invalid or missing user_ids", zap.String("user_ids", c.Query("user_ids")))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_ids parameter"})
+		return
+	}
+	from, errFrom := time.Parse(time.RFC3339, c.Query("from"))
+	to, errTo := time.Parse(time.RFC3339, c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		log.Error("Invalid time window", zap.String("from", c.Query("from")), zap.String("to", c.Query("to")))
+		c.JSON(400, gin.H{"error": "Invalid from/to. Use RFC 3339 timestamps"})
+		return
+	}
+	duration, err := time.ParseDuration(c.Query("duration"))
+	if err != nil {
+		log.Error("Invalid duration", zap.String("duration", c.Query("duration")))
+		c.JSON(400, gin.H{"error": "Invalid or missing duration. Use e.g. 30m or 1h"})
+		return
+	}
+	req := service.SlotRequest{UserIDs: userIDs, From: from, To: to, Duration: duration}
+	if s := c.Query("buffer"); s != "" {
+		if req.Buffer, err = time.ParseDuration(s); err != nil {
+			log.Error("Invalid buffer", zap.String("buffer", s))
+			c.JSON(400, gin.H{"error": "Invalid buffer. Use e.g. 10m"})
+			return
+		}
+	}
+	if s := c.Query("limit"); s != "" {
+		if req.Limit, err = strconv.Atoi(s); err != nil {
+			log.Error("Invalid limit", zap.String("limit", s))
+			c.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+	}
+
+	slots, err := h.schedulingService.FindSlots(c.Request.Context(), req)
+	switch {
+	case errors.Is(err, service.ErrInvalidWindow), errors.Is(err, service.ErrInvalidSlotRequest):
+		log.Error("Invalid slot request", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid slot request: duration must be positive, window at most 62 days, limit at most 100"})
+		return
+	case err != nil:
+		log.Error("Failed to find slots", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to find slots"})
+		return
+	}
+	log.Info("Slots found", zap.Int("slot_count", len(slots)))
+	c.JSON(200, gin.H{"result": slots})
+}
+
+// FreeBusy reports busy time including time outside working hours and out-of-office periods.
+func (h *SchedulingHandler) FreeBusy(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("FreeBusy handler called")
+
+	userIDs, err := parseUserIDs(c.Query("user_ids"))
+	if err != nil {
+		log.Error("Invalid or missing user_ids", zap.String("user_ids", c.Query("user_ids")))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_ids parameter"})
+		return
+	}
+	from, errFrom := time.Parse(time.RFC3339, c.Query("from"))
+	to, errTo := time.Parse(time.RFC3339, c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		log.Error("Invalid time window", zap.String("from", c.Query("from")), zap.String("to", c.Query("to")))
+		c.JSON(400, gin.H{"error": "Invalid from/to. Use RFC 3339 timestamps"})
+		return
+	}
+
+	busy, err := h.schedulingService.FreeBusy(c.Request.Context(), userIDs, from, to)
+	if errors.Is(err, service.ErrInvalidWindow) {
+		log.Error("Invalid free/busy window", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Window must be positive and at most 62 days, for 1 to 50 users"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get free/busy", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get free/busy"})
+		return
+	}
+	log.Info("Free/busy retrieved successfully", zap.Int("user_count", len(userIDs)))
+	c.JSON(200, gin.H{"result": busy})
+}
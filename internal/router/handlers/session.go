@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+	"strconv"
+	"time"
+)
+
+// maxSessionMessage bounds the size of a command.
+const maxSessionMessage = 1 << 20
+
+type SessionHandler struct {
+	calendarService *service.CalendarService
+	streamService   *service.StreamService
+	heartbeat       time.Duration
+}
+
+func NewSessionHandler(calendarService *service.CalendarService, streamService *service.StreamService, heartbeat time.Duration) *SessionHandler {
+	return &SessionHandler{calendarService: calendarService, streamService: streamService, heartbeat: heartbeat}
+}
+
+// Session upgrades /ws?user_id=1 to a WebSocket. The session starts subscribed to the
+// user's own calendar; the client sends subscribe, unsubscribe, create_event and
+// update_event commands and receives an ack or error for each, and a change message for
+// every change of a subscribed calendar. Commands act as the session user, with the same
+// checks as the HTTP endpoints; only calendars the user may see can be subscribed to.
+func (h *SessionHandler) Session(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("Session handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id parameter", zap.String("user_id", c.Query("user_id")))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.serve(c.Request.Context(), ws, userID, log)
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *SessionHandler) serve(ctx context.Context, ws *websocket.Conn, userID int64, log *zap.Logger) {
+	log.Info("Session opened", zap.Int64("user_id", userID))
+	ws.MaxPayloadBytes = maxSessionMessage
+	stream := h.streamService.Subscribe(userID, 0)
+	pushed := make(chan struct{})
+	go func() {
+		defer close(pushed)
+		h.push(ws, stream, log)
+	}()
+	for {
+		var cmd models.SessionCommand
+		if err := websocket.JSON.Receive(ws, &cmd); err != nil {
+			log.Info("Session closed", zap.Int64("user_id", userID), zap.Error(err))
+			break
+		}
+		if err := websocket.JSON.Send(ws, h.handle(ctx, stream, userID, &cmd, log)); err != nil {
+			log.Info("Session write failed", zap.Int64("user_id", userID), zap.Error(err))
+			break
+		}
+	}
+	stream.Close()
+	<-pushed
+}
+
+// push sends the stream's changes and heartbeats until the stream is closed. A lagging
+// session is closed; the client reconnects and reloads.
+func (h *SessionHandler) push(ws *websocket.Conn, stream *service.Stream, log *zap.Logger) {
+	defer ws.Close()
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		var msg gin.H
+		select {
+		case change, ok := <-stream.C:
+			if !ok {
+				if stream.Lagged() {
+					log.Warn("Session lagging, closing it")
+				}
+				return
+			}
+			msg = gin.H{"type": "change", "change": gin.H{"id": change.ID, "type": change.Type, "event_id": change.EventID}}
+		case <-ticker.C:
+			msg = gin.H{"type": "ping"}
+		}
+		if err := websocket.JSON.Send(ws, msg); err != nil {
+			return
+		}
+	}
+}
+
+// handle runs a command and returns its answer.
+func (h *SessionHandler) handle(ctx context.Context, stream *service.Stream, userID int64, cmd *models.SessionCommand, log *zap.Logger) gin.H {
+	log.Info("Session command", zap.Int64("user_id", userID), zap.String("type", cmd.Type), zap.String("id", cmd.ID))
+	switch cmd.Type {
+	case "subscribe", "unsubscribe":
+		for _, id := range cmd.Calendars {
+			if id <= 0 {
+				return commandError(cmd, 400, fmt.Sprintf("invalid calendar %d", id))
+			}
+			if cmd.Type == "subscribe" && !canWatch(userID, id) {
+				return commandError(cmd, 403, fmt.Sprintf("calendar %d is not shared with you", id))
+			}
+		}
+		if cmd.Type == "subscribe" {
+			stream.Watch(cmd.Calendars...)
+		} else {
+			stream.Unwatch(cmd.Calendars...)
+		}
+		return gin.H{"id": cmd.ID, "type": "ack"}
+	case "create_event", "update_event":
+		return h.saveEvent(ctx, userID, cmd, log)
+	}
+	return commandError(cmd, 400, fmt.Sprintf("unknown command %q", cmd.Type))
+}
+
+func (h *SessionHandler) saveEvent(ctx context.Context, userID int64, cmd *models.SessionCommand, log *zap.Logger) gin.H {
+	req := cmd.Event
+	if req == nil || req.Event == "" || req.Date == "" {
+		return commandError(cmd, 400, "Missing required parameters")
+	}
+	if cmd.Type == "update_event" && req.ID <= 0 {
+		return commandError(cmd, 400, "Missing required parameters")
+	}
+	if req.UserID != 0 && req.UserID != userID {
+		return commandError(cmd, 403, "Commands act as the session user")
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return commandError(cmd, 400, "Invalid date format. Use YYYY-MM-DD")
+	}
+	event := &models.Event{
+		ID:           req.ID,
+		UserID:       userID,
+		Date:         date,
+		Event:        req.Event,
+		Transparency: req.Transparency,
+		Visibility:   req.Visibility,
+		RRule:        req.RRule,
+		Attendees:    toAttendees(req.Attendees),
+	}
+	loc, err := h.calendarService.Location(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get time zone", zap.Error(err))
+		return commandError(cmd, 503, "Failed to save event")
+	}
+	if err := parseEventTimes(req, event, loc); err != nil {
+		return commandError(cmd, 400, "Invalid start_time/end_time. Use HH:MM or RFC 3339 and set both")
+	}
+	policy, err := service.ParseConflictPolicy(req.ConflictPolicy)
+	if err != nil {
+		return commandError(cmd, 400, "Invalid conflict_policy. Use warn, reject or force")
+	}
+	var conflicts []models.Event
+	if cmd.Type == "create_event" {
+		conflicts, err = h.calendarService.CreateEventWithPolicy(ctx, event, policy)
+	} else {
+		conflicts, err = h.calendarService.UpdateEventWithPolicy(ctx, event, policy)
+	}
+	var reply gin.H
+	switch {
+	case errors.Is(err, service.ErrInvalidEvent):
+		reply = commandError(cmd, 400, err.Error())
+	case errors.Is(err, models.ErrConflict):
+		reply = commandError(cmd, 409, "Event conflicts with existing events")
+	case errors.Is(err, models.ErrNotFound):
+		reply = commandError(cmd, 404, "Event or resource not found")
+	case errors.Is(err, models.ErrForbidden):
+		reply = commandError(cmd, 403, "Only the organizer can update the event")
+	case err != nil:
+		log.Error("Failed to save event", zap.String("type", cmd.Type), zap.Error(err))
+		reply = commandError(cmd, 503, "Failed to save event")
+	default:
+		reply = gin.H{"id": cmd.ID, "type": "ack", "result": gin.H{"id": event.ID}}
+	}
+	if len(conflicts) > 0 {
+		reply["conflicts"] = conflicts
+	}
+	return reply
+}
+
+// canWatch reports whether the user may receive the changes of a calendar. Calendars are
+// not shared between users, so only the user's own calendar can be watched.
+func canWatch(userID, calendarID int64) bool {
+	return calendarID == userID
+}
+
+func commandError(cmd *models.SessionCommand, status int, msg string) gin.H {
+	return gin.H{"id": cmd.ID, "type": "error", "status": status, "error": msg}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/repository/memory"
+	"awesomeProject/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+func TestSessionHandler_SubscribeRequiresAccess(t *testing.T) {
+	calendar := service.NewCalendarService(memory.NewRepository(zap.NewNop()), zap.NewNop())
+	r := newTestEngine()
+	r.GET("/ws", NewSessionHandler(calendar, service.NewStreamService(16, zap.NewNop()), time.Minute).Session)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	ws, err := websocket.Dial("ws"+srv.URL[len("http"):]+"/ws?user_id=1", "", srv.URL)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	send := func(cmd models.SessionCommand) gin.H {
+		require.NoError(t, websocket.JSON.Send(ws, cmd))
+		var reply gin.H
+		require.NoError(t, websocket.JSON.Receive(ws, &reply))
+		return reply
+	}
+	reply := send(models.SessionCommand{ID: "1", Type: "subscribe", Calendars: []int64{1, 2}})
+	require.Equal(t, "error", reply["type"])
+	require.EqualValues(t, 403, reply["status"])
+
+	reply = send(models.SessionCommand{ID: "2", Type: "subscribe", Calendars: []int64{1}})
+	require.Equal(t, "ack", reply["type"])
+	reply = send(models.SessionCommand{ID: "3", Type: "unsubscribe", Calendars: []int64{2}})
+	require.Equal(t, "ack", reply["type"])
+}
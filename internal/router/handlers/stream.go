@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+type StreamHandler struct {
+	streamService *service.StreamService
+	heartbeat     time.Duration
+}
+
+func NewStreamHandler(streamService *service.StreamService, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{streamService: streamService, heartbeat: heartbeat}
+}
+
+// Stream answers /event_stream?user_id=1 with Server-Sent Events announcing the changes of
+// the user's events. A reconnecting client sends Last-Event-ID and gets what it missed, or
+// a reset event when that is no longer known and it must reload.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("Stream handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id parameter", zap.String("user_id", c.Query("user_id")))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	var lastID int64
+	if raw := c.GetHeader("Last-Event-ID"); raw != "" {
+		if lastID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			log.Error("Invalid Last-Event-ID header", zap.String("last_event_id", raw))
+			c.JSON(400, gin.H{"error": "Invalid Last-Event-ID header"})
+			return
+		}
+	}
+
+	stream := h.streamService.Subscribe(userID, lastID)
+	defer stream.Close()
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(200)
+
+	if stream.Reset {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	for _, change := range stream.Missed {
+		if err := writeChange(c, change); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			log.Info("Stream closed by client", zap.Int64("user_id", userID))
+			return
+		case change, ok := <-stream.C:
+			if !ok {
+				// The client reconnects with Last-Event-ID and catches up.
+				log.Warn("Stream closed", zap.Int64("user_id", userID), zap.Bool("lagged", stream.Lagged()))
+				return
+			}
+			if err := writeChange(c, change); err != nil {
+				log.Info("Stream write failed", zap.Int64("user_id", userID), zap.Error(err))
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeChange(c *gin.Context, change models.Change) error {
+	data, err := json.Marshal(gin.H{"type": change.Type, "event_id": change.EventID})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: change\ndata: %s\n\n", change.ID, data)
+	return err
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+type SyncHandler struct {
+	syncService *service.SyncService
+}
+
+func NewSyncHandler(syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{syncService: syncService}
+}
+
+// Sync answers /sync?user_id=1&sync_token=...&limit=100 with the events created, updated
+// and deleted since the token. Without a token it returns every event of the user.
+func (h *SyncHandler) Sync(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("Sync handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id parameter", zap.String("user_id", c.Query("user_id")))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+			log.Error("Invalid limit parameter", zap.String("limit", raw))
+			c.JSON(400, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+	}
+	page, err := h.syncService.Sync(c.Request.Context(), userID, c.Query("sync_token"), limit)
+	if errors.Is(err, service.ErrInvalidSyncToken) {
+		log.Error("Invalid sync token", zap.String("sync_token", c.Query("sync_token")))
+		c.JSON(400, gin.H{"error": "Invalid sync token"})
+		return
+	}
+	if errors.Is(err, models.ErrExpired) {
+		log.Info("Sync token expired", zap.Int64("user_id", userID))
+		c.JSON(410, gin.H{"error": "Sync token expired, sync again without a token"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to sync", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to sync"})
+		return
+	}
+	log.Info("Changes retrieved successfully", zap.Int("event_count", len(page.Events)), zap.Int("deleted_count", len(page.Deleted)))
+	c.JSON(200, gin.H{"result": page})
+}
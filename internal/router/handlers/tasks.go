@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"io"
+	"strconv"
+	"time"
+)
+
+// maxImportSize bounds the iCalendar data accepted by ImportICS.
+const maxImportSize = 4 << 20
+
+type TaskHandler struct {
+	taskService *service.TaskService
+}
+
+func NewTaskHandler(taskService *service.TaskService) *TaskHandler {
+	return &TaskHandler{taskService: taskService}
+}
+
+func (h *TaskHandler) CreateTask(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("CreateTask handler called")
+
+	req := &models.TaskRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.Summary == "" {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.String("summary", req.Summary))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	task, err := toTask(req)
+	if err != nil {
+		log.Error("Invalid due date", zap.String("due", req.Due), zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid due. Use an RFC 3339 timestamp"})
+		return
+	}
+	err = h.taskService.CreateTask(c.Request.Context(), task)
+	if errors.Is(err, service.ErrInvalidTask) {
+		log.Error("Invalid task", zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to create task", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to create task"})
+		return
+	}
+	log.Info("Task created successfully", zap.Int64("id", task.ID))
+	c.JSON(200, gin.H{"result": task})
+}
+
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("UpdateTask handler called")
+
+	req := &models.TaskRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.ID <= 0 || req.Summary == "" {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.Int64("id", req.ID), zap.String("summary", req.Summary))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	task, err := toTask(req)
+	if err != nil {
+		log.Error("Invalid due date", zap.String("due", req.Due), zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid due. Use an RFC 3339 timestamp"})
+		return
+	}
+	err = h.taskService.UpdateTask(c.Request.Context(), task)
+	switch {
+	case errors.Is(err, service.ErrInvalidTask):
+		log.Error("Invalid task", zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, models.ErrNotFound):
+		log.Error("Task not found", zap.Int64("id", req.ID))
+		c.JSON(404, gin.H{"error": "Task not found"})
+		return
+	case err != nil:
+		log.Error("Failed to update task", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to update task"})
+		return
+	}
+	log.Info("Task updated successfully", zap.Int64("id", task.ID))
+	c.JSON(200, gin.H{"result": task})
+}
+
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("DeleteTask handler called")
+
+	req := &models.TaskRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.ID <= 0 {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.Int64("id", req.ID))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	err := h.taskService.DeleteTask(c.Request.Context(), &models.Task{ID: req.ID, UserID: req.UserID})
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Task not found", zap.Int64("id", req.ID))
+		c.JSON(404, gin.H{"error": "Task not found"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to delete task", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to delete task"})
+		return
+	}
+	log.Info("Task deleted successfully", zap.Int64("id", req.ID))
+	c.JSON(200, gin.H{"result": "Task deleted successfully"})
+}
+
+// GetTasks lists the user's tasks, optionally only those due within from/to. Completed and
+// cancelled tasks are included with include_done=true.
+func (h *TaskHandler) GetTasks(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GetTasks handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	includeDone, err := strconv.ParseBool(c.DefaultQuery("include_done", "false"))
+	if err != nil {
+		log.Error("Invalid include_done", zap.String("include_done", c.Query("include_done")))
+		c.JSON(400, gin.H{"error": "Invalid include_done parameter"})
+		return
+	}
+	var from, to time.Time
+	if c.Query("from") != "" || c.Query("to") != "" {
+		var errFrom, errTo error
+		from, errFrom = time.Parse(time.RFC3339, c.Query("from"))
+		to, errTo = time.Parse(time.RFC3339, c.Query("to"))
+		if errFrom != nil || errTo != nil {
+			log.Error("Invalid time window", zap.String("from", c.Query("from")), zap.String("to", c.Query("to")))
+			c.JSON(400, gin.H{"error": "Invalid from/to. Use RFC 3339 timestamps"})
+			return
+		}
+	}
+	tasks, err := h.taskService.GetTasks(c.Request.Context(), userID, from, to, includeDone)
+	if errors.Is(err, service.ErrInvalidWindow) {
+		log.Error("Invalid task window", zap.Error(err))
+		c.JSON(400, gin.H{"error": "to must be after from"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get tasks", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get tasks"})
+		return
+	}
+	log.Info("Tasks retrieved successfully", zap.Int64("user_id", userID), zap.Int("task_count", len(tasks)))
+	c.JSON(200, gin.H{"result": tasks})
+}
+
+// ExportICS returns the user's events within from/to and all of their tasks as text/calendar.
+func (h *TaskHandler) ExportICS(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("ExportICS handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	from, errFrom := time.Parse(time.RFC3339, c.Query("from"))
+	to, errTo := time.Parse(time.RFC3339, c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		log.Error("Invalid time window", zap.String("from", c.Query("from")), zap.String("to", c.Query("to")))
+		c.JSON(400, gin.H{"error": "Invalid from/to. Use RFC 3339 timestamps"})
+		return
+	}
+	data, err := h.taskService.ExportICS(c.Request.Context(), userID, from, to)
+	if errors.Is(err, service.ErrInvalidWindow) {
+		log.Error("Invalid export window", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Window must be positive and at most 366 days"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to export calendar", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to export calendar"})
+		return
+	}
+	log.Info("Calendar exported successfully", zap.Int64("user_id", userID), zap.Int("bytes", len(data)))
+	c.Data(200, "text/calendar; charset=utf-8", data)
+}
+
+// ImportICS reads raw text/calendar data and creates or updates the user's tasks from its VTODOs.
+func (h *TaskHandler) ImportICS(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("ImportICS handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxImportSize))
+	if err != nil {
+		log.Error("Failed to read request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	tasks, err := h.taskService.ImportICS(c.Request.Context(), userID, data)
+	if errors.Is(err, service.ErrInvalidCalendar) {
+		log.Error("Invalid calendar data", zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to import calendar", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to import calendar"})
+		return
+	}
+	log.Info("Calendar imported successfully", zap.Int64("user_id", userID), zap.Int("task_count", len(tasks)))
+	c.JSON(200, gin.H{"result": tasks})
+}
+
+func toTask(req *models.TaskRequest) (*models.Task, error) {
+	task := &models.Task{
+		ID:              req.ID,
+		UserID:          req.UserID,
+		Summary:         req.Summary,
+		Description:     req.Description,
+		Priority:        req.Priority,
+		Status:          req.Status,
+		PercentComplete: req.PercentComplete,
+	}
+	if req.Due != "" {
+		due, err := time.Parse(time.RFC3339, req.Due)
+		if err != nil {
+			return nil, err
+		}
+		task.Due = &due
+	}
+	return task, nil
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GetTrash handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	trash, err := h.trashService.GetTrash(c.Request.Context(), userID)
+	if err != nil {
+		log.Error("Failed to get trash", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get trash"})
+		return
+	}
+	log.Info("Trash retrieved successfully", zap.Int64("user_id", userID), zap.Int("event_count", len(trash)))
+	c.JSON(200, gin.H{"result": trash})
+}
+
+func (h *TrashHandler) RestoreEvent(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("RestoreEvent handler called")
+
+	req, ok := decodeTrashRequest(c, log)
+	if !ok {
+		return
+	}
+	event, err := h.trashService.RestoreEvent(c.Request.Context(), req.UserID, req.ID)
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Event not in trash", zap.Int64("id", req.ID), zap.Int64("user_id", req.UserID))
+		c.JSON(404, gin.H{"error": "Event not found in trash"})
+		return
+	}
+	if errors.Is(err, models.ErrConflict) {
+		log.Error("Restored event would double-book the calendar", zap.Int64("id", req.ID))
+		c.JSON(409, gin.H{"error": "Event conflicts with existing events"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to restore event", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to restore event"})
+		return
+	}
+	log.Info("Event restored successfully", zap.Int64("id", req.ID))
+	c.JSON(200, gin.H{"result": event})
+}
+
+func (h *TrashHandler) PurgeEvent(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("PurgeEvent handler called")
+
+	req, ok := decodeTrashRequest(c, log)
+	if !ok {
+		return
+	}
+	err := h.trashService.PurgeEvent(c.Request.Context(), req.UserID, req.ID)
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Event not in trash", zap.Int64("id", req.ID), zap.Int64("user_id", req.UserID))
+		c.JSON(404, gin.H{"error": "Event not found in trash"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to purge event", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to purge event"})
+		return
+	}
+	log.Info("Event purged successfully", zap.Int64("id", req.ID))
+	c.JSON(200, gin.H{"result": "Event purged successfully"})
+}
+
+// decodeTrashRequest reads the id and user_id of a trashed event and answers 400 when
+// either is missing.
+func decodeTrashRequest(c *gin.Context, log *zap.Logger) (*models.EventRequest, bool) {
+	req := &models.EventRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return nil, false
+	}
+	if req.ID <= 0 || req.UserID <= 0 {
+		log.Error("Missing required parameters", zap.Int64("id", req.ID), zap.Int64("user_id", req.UserID))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return nil, false
+	}
+	return req, true
+}
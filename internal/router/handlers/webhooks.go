@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("CreateWebhook handler called")
+
+	req := &models.WebhookRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.URL == "" {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.String("url", req.URL))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	hook := &models.Webhook{UserID: req.UserID, URL: req.URL, Secret: req.Secret}
+	err := h.webhookService.CreateWebhook(c.Request.Context(), hook)
+	if errors.Is(err, service.ErrInvalidWebhookURL) {
+		log.Error("Invalid webhook url", zap.String("url", req.URL))
+		c.JSON(400, gin.H{"error": "Invalid url. Use an absolute http(s) URL of a public host"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to create webhook", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+	log.Info("Webhook created successfully", zap.Int64("id", hook.ID))
+	c.JSON(200, gin.H{"result": models.CreatedWebhook{Webhook: *hook, Secret: hook.Secret}})
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("DeleteWebhook handler called")
+
+	req := &models.WebhookRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.ID <= 0 {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.Int64("id", req.ID))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	err := h.webhookService.DeleteWebhook(c.Request.Context(), &models.Webhook{ID: req.ID, UserID: req.UserID})
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Webhook not found", zap.Int64("id", req.ID))
+		c.JSON(404, gin.H{"error": "Webhook not found"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to delete webhook", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+	log.Info("Webhook deleted successfully", zap.Int64("id", req.ID))
+	c.JSON(200, gin.H{"result": "Webhook deleted successfully"})
+}
+
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GetWebhooks handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	hooks, err := h.webhookService.GetWebhooks(c.Request.Context(), userID)
+	if err != nil {
+		log.Error("Failed to get webhooks", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get webhooks"})
+		return
+	}
+	c.JSON(200, gin.H{"result": hooks})
+}
+
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("GetDeliveries handler called")
+
+	userID, err := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		log.Error("Invalid or missing user_id", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid or missing user_id parameter"})
+		return
+	}
+	var webhookID int64
+	if s := c.Query("webhook_id"); s != "" {
+		webhookID, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || webhookID <= 0 {
+			log.Error("Invalid webhook_id", zap.String("webhook_id", s))
+			c.JSON(400, gin.H{"error": "Invalid webhook_id parameter"})
+			return
+		}
+	}
+	deliveries, err := h.webhookService.GetDeliveries(c.Request.Context(), userID, webhookID)
+	if err != nil {
+		log.Error("Failed to get webhook deliveries", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to get webhook deliveries"})
+		return
+	}
+	log.Info("Webhook deliveries retrieved successfully", zap.Int64("user_id", userID), zap.Int("delivery_count", len(deliveries)))
+	c.JSON(200, gin.H{"result": deliveries})
+}
+
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	log := c.Value("logger").(*zap.Logger)
+	log.Info("Redeliver handler called")
+
+	req := &models.WebhookRequest{}
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		log.Error("Failed to decode request body", zap.Error(err))
+		c.JSON(400, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.UserID <= 0 || req.ID <= 0 {
+		log.Error("Missing required parameters", zap.Int64("user_id", req.UserID), zap.Int64("id", req.ID))
+		c.JSON(400, gin.H{"error": "Missing required parameters"})
+		return
+	}
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), req.UserID, req.ID)
+	if errors.Is(err, models.ErrNotFound) {
+		log.Error("Delivery not found", zap.Int64("id", req.ID))
+		c.JSON(404, gin.H{"error": "Delivery not found"})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to redeliver webhook", zap.Error(err))
+		c.JSON(503, gin.H{"error": "Failed to redeliver webhook"})
+		return
+	}
+	log.Info("Webhook redelivery queued", zap.Int64("delivery_id", delivery.ID))
+	c.JSON(200, gin.H{"result": delivery})
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BearerToken rejects requests that do not carry "Authorization: Bearer <token>", for
+// endpoints called by trusted services rather than users.
+func BearerToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			log := c.Value("logger").(*zap.Logger)
+			log.Error("Missing or invalid token")
+			c.AbortWithStatusJSON(401, gin.H{"error": "Missing or invalid token"})
+			return
+		}
+		c.Next()
+	}
+}
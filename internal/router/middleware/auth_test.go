@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBearerToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(LoggingMiddleware(zap.NewNop()))
+	r.POST("/itip_reply", BearerToken("secret"), func(c *gin.Context) {
+		c.JSON(200, gin.H{"result": "ok"})
+	})
+
+	for header, code := range map[string]int{
+		"":              401,
+		"Bearer wrong":  401,
+		"secret":        401,
+		"Bearer secret": 200,
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/itip_reply", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		r.ServeHTTP(w, req)
+		require.Equal(t, code, w.Code, header)
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"awesomeProject/internal/audit"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -11,6 +12,7 @@ func LoggingMiddleware(log *zap.Logger) gin.HandlerFunc {
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.String("remote_addr", c.Request.RemoteAddr),
+			zap.String("request_id", audit.RequestID(c.Request.Context())),
 		)
 
 		requestLog.Info("Request started")
@@ -7,13 +7,33 @@ import (
 	"go.uber.org/zap"
 )
 
+// Handlers groups the HTTP handlers served by the router.
+type Handlers struct {
+	Calendar   *handlers.CalendarHandler
+	Reminder   *handlers.ReminderHandler
+	Webhook    *handlers.WebhookHandler
+	Profile    *handlers.ProfileHandler
+	Scheduling *handlers.SchedulingHandler
+	Resource   *handlers.ResourceHandler
+	Task       *handlers.TaskHandler
+	Trash      *handlers.TrashHandler
+	History    *handlers.HistoryHandler
+	Sync       *handlers.SyncHandler
+	Stream     *handlers.StreamHandler
+	Session    *handlers.SessionHandler
+	GraphQL    *handlers.GraphQLHandler
+	// ITIPReplySecret is the bearer token required on /itip_reply, which is left out when
+	// it is empty.
+	ITIPReplySecret string
+}
+
 type Router struct {
-	rout    *gin.Engine
-	handler *handlers.CalendarHandler
-	log     *zap.Logger
+	rout     *gin.Engine
+	handlers Handlers
+	log      *zap.Logger
 }
 
-func NewRouter(handler *handlers.CalendarHandler, mode string, log *zap.Logger) *Router {
+func NewRouter(handlers Handlers, mode string, log *zap.Logger) *Router {
 	switch mode {
 	case "debug":
 		gin.SetMode(gin.DebugMode)
@@ -21,9 +41,9 @@ func NewRouter(handler *handlers.CalendarHandler, mode string, log *zap.Logger)
 		gin.SetMode(gin.ReleaseMode)
 	}
 	router := &Router{
-		rout:    gin.Default(),
-		handler: handler,
-		log:     log,
+		rout:     gin.Default(),
+		handlers: handlers,
+		log:      log,
 	}
 	router.setupRouter()
 
@@ -31,13 +51,85 @@ func NewRouter(handler *handlers.CalendarHandler, mode string, log *zap.Logger)
 }
 
 func (r *Router) setupRouter() {
-	r.rout.Use(middleware.LoggingMiddleware(r.log))
-	r.rout.POST("/create_event", r.handler.CreateEvent)
-	r.rout.POST("/update_event", r.handler.UpdateEvent)
-	r.rout.POST("/delete_event", r.handler.DeleteEvent)
-	r.rout.GET("/events_for_day", r.handler.GetEventsForDay)
-	r.rout.GET("/events_for_week", r.handler.GetEventsForWeek)
-	r.rout.GET("/events_for_month", r.handler.GetEventsForMonth)
+	r.rout.Use(middleware.RequestID(), middleware.LoggingMiddleware(r.log))
+	r.rout.POST("/create_event", r.handlers.Calendar.CreateEvent)
+	r.rout.POST("/update_event", r.handlers.Calendar.UpdateEvent)
+	r.rout.POST("/delete_event", r.handlers.Calendar.DeleteEvent)
+	r.rout.POST("/respond_event", r.handlers.Calendar.RespondToEvent)
+	if r.handlers.ITIPReplySecret != "" {
+		r.rout.POST("/itip_reply", middleware.BearerToken(r.handlers.ITIPReplySecret), r.handlers.Calendar.ITIPReply)
+	}
+	r.rout.GET("/events_for_day", r.handlers.Calendar.GetEventsForDay)
+	r.rout.GET("/events_for_week", r.handlers.Calendar.GetEventsForWeek)
+	r.rout.GET("/events_for_month", r.handlers.Calendar.GetEventsForMonth)
+
+	// The remaining features need the database; they are left out when their handler is not set.
+	if r.handlers.Scheduling != nil {
+		r.rout.GET("/freebusy", r.handlers.Scheduling.FreeBusy)
+		r.rout.GET("/find_slots", r.handlers.Scheduling.FindSlots)
+	}
+
+	if r.handlers.Reminder != nil {
+		r.rout.POST("/create_reminder", r.handlers.Reminder.CreateReminder)
+		r.rout.POST("/delete_reminder", r.handlers.Reminder.DeleteReminder)
+		r.rout.GET("/reminders", r.handlers.Reminder.GetReminders)
+	}
+
+	if r.handlers.Webhook != nil {
+		r.rout.POST("/create_webhook", r.handlers.Webhook.CreateWebhook)
+		r.rout.POST("/delete_webhook", r.handlers.Webhook.DeleteWebhook)
+		r.rout.GET("/webhooks", r.handlers.Webhook.GetWebhooks)
+		r.rout.GET("/webhook_deliveries", r.handlers.Webhook.GetDeliveries)
+		r.rout.POST("/redeliver_webhook", r.handlers.Webhook.Redeliver)
+	}
+
+	if r.handlers.Profile != nil {
+		r.rout.POST("/update_profile", r.handlers.Profile.UpdateProfile)
+		r.rout.GET("/profile", r.handlers.Profile.GetProfile)
+	}
+
+	if r.handlers.Resource != nil {
+		r.rout.POST("/create_resource", r.handlers.Resource.CreateResource)
+		r.rout.POST("/delete_resource", r.handlers.Resource.DeleteResource)
+		r.rout.GET("/resources", r.handlers.Resource.GetResources)
+		r.rout.GET("/free_resources", r.handlers.Resource.FreeResources)
+	}
+
+	if r.handlers.Task != nil {
+		r.rout.POST("/create_task", r.handlers.Task.CreateTask)
+		r.rout.POST("/update_task", r.handlers.Task.UpdateTask)
+		r.rout.POST("/delete_task", r.handlers.Task.DeleteTask)
+		r.rout.GET("/tasks", r.handlers.Task.GetTasks)
+		r.rout.GET("/export_ics", r.handlers.Task.ExportICS)
+		r.rout.POST("/import_ics", r.handlers.Task.ImportICS)
+	}
+
+	if r.handlers.Trash != nil {
+		r.rout.GET("/trash", r.handlers.Trash.GetTrash)
+		r.rout.POST("/restore_event", r.handlers.Trash.RestoreEvent)
+		r.rout.POST("/purge_event", r.handlers.Trash.PurgeEvent)
+	}
+
+	if r.handlers.History != nil {
+		r.rout.GET("/event_history", r.handlers.History.GetHistory)
+		r.rout.POST("/revert_event", r.handlers.History.RevertEvent)
+	}
+
+	if r.handlers.Sync != nil {
+		r.rout.GET("/sync", r.handlers.Sync.Sync)
+	}
+
+	if r.handlers.Stream != nil {
+		r.rout.GET("/event_stream", r.handlers.Stream.Stream)
+	}
+
+	if r.handlers.Session != nil {
+		r.rout.GET("/ws", r.handlers.Session.Session)
+	}
+
+	if r.handlers.GraphQL != nil {
+		r.rout.POST("/graphql", r.handlers.GraphQL.Query)
+	}
 }
 
 func (r *Router) GetHTTPHandler() *gin.Engine {
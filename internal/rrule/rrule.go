@@ -0,0 +1,230 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules the calendar supports:
+// FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL and BYDAY for weekly rules.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRule = errors.New("invalid recurrence rule")
+	// ErrTooManyOccurrences is returned when a window holds more occurrences than are expanded.
+	ErrTooManyOccurrences = errors.New("too many occurrences")
+)
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+
+	// maxOccurrences caps the occurrences expanded for one window.
+	maxOccurrences = 10000
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+type Rule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []time.Weekday
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL=%s", ErrInvalidRule, value)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT=%s", ErrInvalidRule, value)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("%w: BYDAY=%s", ErrInvalidRule, value)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+	switch r.Freq {
+	case Daily, Monthly, Yearly:
+		if len(r.ByDay) > 0 {
+			return nil, fmt.Errorf("%w: BYDAY is only supported with FREQ=WEEKLY", ErrInvalidRule)
+		}
+	case Weekly:
+	default:
+		return nil, fmt.Errorf("%w: FREQ=%s", ErrInvalidRule, r.Freq)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	sort.Slice(r.ByDay, func(i, j int) bool { return mondayFirst(r.ByDay[i]) < mondayFirst(r.ByDay[j]) })
+	return r, nil
+}
+
+func parseUntil(v string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			if layout == "20060102" {
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL=%s", ErrInvalidRule, v)
+}
+
+// Between returns the start times of occurrences of a series starting at dtstart whose
+// [start, start+duration) overlaps [from, to). Occurrences keep the wall-clock time of
+// dtstart in its location, so expand in the organizer's location to follow DST. When the
+// window holds more than maxOccurrences the ones found are returned with
+// ErrTooManyOccurrences.
+func (r *Rule) Between(dtstart time.Time, duration time.Duration, from, to time.Time) ([]time.Time, error) {
+	var out []time.Time
+	n, truncated := 0, false
+	r.each(dtstart, r.skip(dtstart, from.Add(-duration)), func(t time.Time) bool {
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		if !t.Before(to) {
+			return false
+		}
+		if n == maxOccurrences {
+			truncated = true
+			return false
+		}
+		n++
+		if t.Add(duration).After(from) {
+			out = append(out, t)
+		}
+		return r.Count == 0 || n < r.Count
+	})
+	if truncated {
+		return out, fmt.Errorf("%w: more than %d", ErrTooManyOccurrences, maxOccurrences)
+	}
+	return out, nil
+}
+
+// skip returns the number of whole periods of an unbounded daily or weekly series that
+// start well before the given time, so long-running series are not expanded from dtstart.
+// Rules with COUNT are expanded from the start, as every occurrence counts.
+func (r *Rule) skip(dtstart, before time.Time) int {
+	if r.Count > 0 || !before.After(dtstart) {
+		return 0
+	}
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = daysBetween(dtstart, before) / r.Interval
+	case Weekly:
+		periods = daysBetween(weekStart(dtstart), before) / 7 / r.Interval
+	default:
+		return 0
+	}
+	// One period of margin covers DST shifts and times of day.
+	return max(periods-1, 0)
+}
+
+// daysBetween returns the number of calendar days from a to b in the location of a.
+func daysBetween(a, b time.Time) int {
+	b = b.In(a.Location())
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da) / (24 * time.Hour))
+}
+
+func weekStart(t time.Time) time.Time {
+	return t.AddDate(0, 0, -mondayFirst(t.Weekday()))
+}
+
+// each calls fn with successive occurrences in order, starting skip periods after dtstart,
+// until fn returns false.
+func (r *Rule) each(dtstart time.Time, skip int, fn func(time.Time) bool) {
+	switch r.Freq {
+	case Daily:
+		for i := skip; ; i++ {
+			if !fn(dtstart.AddDate(0, 0, i*r.Interval)) {
+				return
+			}
+		}
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{dtstart.Weekday()}
+		}
+		first := weekStart(dtstart)
+		for i := skip; ; i++ {
+			week := first.AddDate(0, 0, 7*i*r.Interval)
+			for _, wd := range days {
+				t := week.AddDate(0, 0, mondayFirst(wd))
+				if t.Before(dtstart) {
+					continue
+				}
+				if !fn(t) {
+					return
+				}
+			}
+		}
+	case Monthly:
+		for i := 0; ; i++ {
+			t := dtstart.AddDate(0, i*r.Interval, 0)
+			if t.Day() != dtstart.Day() {
+				// Skip months that do not have this day, as RFC 5545 requires.
+				continue
+			}
+			if !fn(t) {
+				return
+			}
+		}
+	case Yearly:
+		for i := 0; ; i++ {
+			t := dtstart.AddDate(i*r.Interval, 0, 0)
+			if t.Day() != dtstart.Day() {
+				continue
+			}
+			if !fn(t) {
+				return
+			}
+		}
+	}
+}
+
+func mondayFirst(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
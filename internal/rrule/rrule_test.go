@@ -0,0 +1,107 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func day(y int, m time.Month, d, h int) time.Time {
+	return time.Date(y, m, d, h, 0, 0, 0, time.UTC)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTH=1",
+		"FREQ",
+	} {
+		_, err := Parse(s)
+		require.ErrorIs(t, err, ErrInvalidRule, s)
+	}
+}
+
+func TestBetween_DailyCount(t *testing.T) {
+	r, err := Parse("FREQ=DAILY;COUNT=3")
+	require.NoError(t, err)
+	got, err := r.Between(day(2025, 1, 1, 9), time.Hour, day(2025, 1, 1, 0), day(2025, 2, 1, 0))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day(2025, 1, 1, 9), day(2025, 1, 2, 9), day(2025, 1, 3, 9)}, got)
+}
+
+func TestBetween_WeeklyByDayWindow(t *testing.T) {
+	// Wednesday 2025-01-01; every second week on Monday and Wednesday.
+	r, err := Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=WE,MO")
+	require.NoError(t, err)
+	got, err := r.Between(day(2025, 1, 1, 10), time.Hour, day(2025, 1, 10, 0), day(2025, 1, 31, 0))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day(2025, 1, 13, 10), day(2025, 1, 15, 10), day(2025, 1, 27, 10), day(2025, 1, 29, 10)}, got)
+}
+
+func TestBetween_IncludesOccurrenceOverlappingWindowStart(t *testing.T) {
+	r, err := Parse("FREQ=DAILY")
+	require.NoError(t, err)
+	got, err := r.Between(day(2025, 1, 1, 23), 2*time.Hour, day(2025, 1, 3, 0), day(2025, 1, 3, 12))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day(2025, 1, 2, 23)}, got)
+}
+
+func TestBetween_MonthlySkipsShortMonthsAndHonorsUntil(t *testing.T) {
+	r, err := Parse("FREQ=MONTHLY;UNTIL=20250531")
+	require.NoError(t, err)
+	got, err := r.Between(day(2025, 1, 31, 8), time.Hour, day(2025, 1, 1, 0), day(2026, 1, 1, 0))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day(2025, 1, 31, 8), day(2025, 3, 31, 8), day(2025, 5, 31, 8)}, got)
+}
+
+func TestBetween_YearlyLeapDay(t *testing.T) {
+	r, err := Parse("FREQ=YEARLY;COUNT=2")
+	require.NoError(t, err)
+	got, err := r.Between(day(2024, 2, 29, 0), 24*time.Hour, day(2024, 1, 1, 0), day(2040, 1, 1, 0))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day(2024, 2, 29, 0), day(2028, 2, 29, 0)}, got)
+}
+
+func TestBetween_KeepsLocalTimeAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	r, err := Parse("FREQ=WEEKLY")
+	require.NoError(t, err)
+	// Thursdays at 09:00 in Berlin; summer time starts on 2025-03-30.
+	start := time.Date(2025, 3, 20, 9, 0, 0, 0, berlin)
+	got, err := r.Between(start, time.Hour, day(2025, 3, 1, 0), day(2025, 4, 5, 0))
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	require.Equal(t, day(2025, 3, 20, 8), got[0].UTC())
+	require.Equal(t, day(2025, 3, 27, 8), got[1].UTC())
+	require.Equal(t, day(2025, 4, 3, 7), got[2].UTC())
+}
+
+func TestBetween_SkipsToWindowOfLongSeries(t *testing.T) {
+	r, err := Parse("FREQ=DAILY")
+	require.NoError(t, err)
+	// Far more than maxOccurrences days after the series started.
+	got, err := r.Between(day(1990, 1, 1, 9), time.Hour, day(2025, 6, 1, 0), day(2025, 6, 3, 0))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day(2025, 6, 1, 9), day(2025, 6, 2, 9)}, got)
+
+	r, err = Parse("FREQ=WEEKLY;BYDAY=MO,FR")
+	require.NoError(t, err)
+	got, err = r.Between(day(1990, 1, 1, 9), time.Hour, day(2025, 6, 1, 0), day(2025, 6, 8, 0))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{day(2025, 6, 2, 9), day(2025, 6, 6, 9)}, got)
+}
+
+func TestBetween_ReportsTooManyOccurrences(t *testing.T) {
+	r, err := Parse("FREQ=DAILY;COUNT=20000")
+	require.NoError(t, err)
+	got, err := r.Between(day(2000, 1, 1, 9), time.Hour, day(2000, 1, 1, 0), day(2100, 1, 1, 0))
+	require.ErrorIs(t, err, ErrTooManyOccurrences)
+	require.Len(t, got, maxOccurrences)
+}
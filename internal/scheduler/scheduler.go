@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/notifier"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"slices"
+	"time"
+)
+
+const (
+	defaultBatchSize   = 100
+	defaultMaxAttempts = 6
+	baseBackoff        = time.Minute
+	maxBackoff         = time.Hour
+)
+
+type ReminderRepository interface {
+	ProcessDueReminders(ctx context.Context, now time.Time, limit int, fn func(ctx context.Context, d *models.DueReminder)) (int, error)
+}
+
+// Channel is a named way of delivering reminders, e.g. email or webhooks.
+type Channel struct {
+	Name     string
+	Notifier notifier.Notifier
+}
+
+// Scheduler periodically fires due reminders through its channels. Each channel delivers a
+// reminder once: when one fails, only the channels that have not delivered yet are retried,
+// with exponential backoff until maxAttempts is reached, after which the reminder is marked
+// failed and no longer picked up.
+type Scheduler struct {
+	repo        ReminderRepository
+	channels    []Channel
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	now         func() time.Time
+	log         *zap.Logger
+}
+
+func NewScheduler(repo ReminderRepository, channels []Channel, interval time.Duration, log *zap.Logger) *Scheduler {
+	return &Scheduler{
+		repo:        repo,
+		channels:    channels,
+		interval:    interval,
+		batchSize:   defaultBatchSize,
+		maxAttempts: defaultMaxAttempts,
+		now:         time.Now,
+		log:         log.Named("Scheduler"),
+	}
+}
+
+// Run polls for due reminders until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.log.Info("Starting reminder scheduler", zap.Duration("interval", s.interval))
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			s.log.Info("Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick fires all reminders that are due now, batch by batch.
+func (s *Scheduler) Tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		fired := 0
+		n, err := s.repo.ProcessDueReminders(ctx, s.now(), s.batchSize, func(ctx context.Context, d *models.DueReminder) {
+			s.attempt(ctx, d)
+			if d.Status == models.ReminderFired {
+				fired++
+			}
+		})
+		if err != nil {
+			s.log.Error("Failed to process due reminders", zap.Error(err))
+			return
+		}
+		if fired > 0 {
+			s.log.Info("Fired reminders", zap.Int("count", fired))
+		}
+		// Failed reminders back off, so the next batch holds different ones.
+		if n < s.batchSize {
+			return
+		}
+	}
+}
+
+func (s *Scheduler) attempt(ctx context.Context, d *models.DueReminder) {
+	var errs []error
+	for _, ch := range s.channels {
+		if slices.Contains(d.Delivered, ch.Name) {
+			continue
+		}
+		if err := ch.Notifier.Notify(ctx, d.Notification); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name, err))
+			continue
+		}
+		d.Delivered = append(d.Delivered, ch.Name)
+	}
+	err := errors.Join(errs...)
+	d.Attempts++
+	if err == nil {
+		d.Status = models.ReminderFired
+		d.LastError = ""
+		return
+	}
+	d.LastError = err.Error()
+	reminderID := d.Notification.Reminder.ID
+	if d.Attempts >= s.maxAttempts {
+		d.Status = models.ReminderFailed
+		s.log.Warn("Reminder failed for good", zap.Int64("reminder_id", reminderID), zap.Int("attempts", d.Attempts), zap.Error(err))
+		return
+	}
+	d.NextAttemptAt = s.now().Add(Backoff(d.Attempts))
+	s.log.Info("Reminder notification failed, will retry", zap.Int64("reminder_id", reminderID), zap.Time("next_attempt_at", d.NextAttemptAt), zap.Error(err))
+}
+
+// Backoff returns the delay before the next attempt after the given number of failed attempts.
+func Backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
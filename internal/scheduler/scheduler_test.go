@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRepo hands out due reminders in batches, keeping the state the scheduler leaves behind.
+type fakeRepo struct {
+	reminders []models.DueReminder
+	fired     []int64
+	calls     int
+}
+
+func (f *fakeRepo) ProcessDueReminders(ctx context.Context, now time.Time, limit int, fn func(ctx context.Context, d *models.DueReminder)) (int, error) {
+	f.calls++
+	n := 0
+	for i := range f.reminders {
+		d := &f.reminders[i]
+		if n == limit || d.Status != models.ReminderPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		fn(ctx, d)
+		if d.Status == models.ReminderFired {
+			f.fired = append(f.fired, d.Notification.Reminder.ID)
+		}
+		n++
+	}
+	return n, nil
+}
+
+type fakeNotifier struct {
+	failFor int64
+	got     []models.Notification
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, n models.Notification) error {
+	if n.Reminder.ID == f.failFor {
+		return errors.New("delivery failed")
+	}
+	f.got = append(f.got, n)
+	return nil
+}
+
+func dueReminders(n int) []models.DueReminder {
+	out := make([]models.DueReminder, n)
+	for i := range out {
+		out[i] = models.DueReminder{
+			Notification: models.Notification{Type: models.NotificationReminder, Reminder: &models.Reminder{ID: int64(i + 1)}},
+			Status:       models.ReminderPending,
+		}
+	}
+	return out
+}
+
+func TestScheduler_Tick_DrainsBatches(t *testing.T) {
+	repo := &fakeRepo{reminders: dueReminders(5)}
+	n := &fakeNotifier{}
+	s := NewScheduler(repo, []Channel{{Name: "log", Notifier: n}}, time.Minute, zap.NewNop())
+	s.batchSize = 2
+
+	s.Tick(context.Background())
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, repo.fired)
+	require.Len(t, n.got, 5)
+	require.Equal(t, 3, repo.calls)
+}
+
+func TestScheduler_Tick_BacksOffFailures(t *testing.T) {
+	repo := &fakeRepo{reminders: dueReminders(4)}
+	n := &fakeNotifier{failFor: 1}
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	s := NewScheduler(repo, []Channel{{Name: "log", Notifier: n}}, time.Minute, zap.NewNop())
+	s.batchSize = 2
+	s.now = func() time.Time { return now }
+
+	// A failing reminder does not keep the ones behind it from firing.
+	s.Tick(context.Background())
+	require.Equal(t, []int64{2, 3, 4}, repo.fired)
+	failing := &repo.reminders[0]
+	require.Equal(t, models.ReminderPending, failing.Status)
+	require.Equal(t, 1, failing.Attempts)
+	require.Equal(t, "log: delivery failed", failing.LastError)
+	require.Equal(t, now.Add(time.Minute), failing.NextAttemptAt)
+
+	// It is left alone until its backoff has passed.
+	repo.calls = 0
+	s.Tick(context.Background())
+	require.Equal(t, 1, repo.calls)
+	require.Equal(t, 1, failing.Attempts)
+
+	for i := 2; i <= s.maxAttempts; i++ {
+		now = failing.NextAttemptAt
+		s.Tick(context.Background())
+		require.Equal(t, i, failing.Attempts)
+	}
+	require.Equal(t, models.ReminderFailed, failing.Status)
+	require.Equal(t, []int64{2, 3, 4}, repo.fired)
+}
+
+func TestScheduler_Tick_RetriesOnlyFailedChannels(t *testing.T) {
+	repo := &fakeRepo{reminders: dueReminders(2)}
+	webhooks := &fakeNotifier{}
+	email := &fakeNotifier{failFor: 1}
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	s := NewScheduler(repo, []Channel{{Name: "webhook", Notifier: webhooks}, {Name: "email", Notifier: email}}, time.Minute, zap.NewNop())
+	s.now = func() time.Time { return now }
+
+	s.Tick(context.Background())
+	require.Equal(t, []int64{2}, repo.fired)
+	require.Equal(t, []string{"webhook"}, repo.reminders[0].Delivered)
+	require.Equal(t, []string{"webhook", "email"}, repo.reminders[1].Delivered)
+	require.Len(t, webhooks.got, 2)
+
+	email.failFor = 0
+	now = repo.reminders[0].NextAttemptAt
+	s.Tick(context.Background())
+	require.Equal(t, []int64{2, 1}, repo.fired)
+	require.Equal(t, []string{"webhook", "email"}, repo.reminders[0].Delivered)
+	// The webhook delivery was not queued again.
+	require.Len(t, webhooks.got, 2)
+	require.Len(t, email.got, 2)
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, time.Minute, Backoff(1))
+	require.Equal(t, 2*time.Minute, Backoff(2))
+	require.Equal(t, 32*time.Minute, Backoff(6))
+	require.Equal(t, time.Hour, Backoff(7))
+	require.Equal(t, time.Hour, Backoff(50))
+}
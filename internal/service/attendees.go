@@ -0,0 +1,155 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"net/mail"
+	"strings"
+)
+
+var ErrInvalidResponse = errors.New("invalid response status")
+
+// RespondToEvent records an invitee's answer to an invitation. Only internal invitees answer
+// here; the organizer is not an attendee of their own event.
+func (s *CalendarService) RespondToEvent(ctx context.Context, eventID, userID int64, status string) error {
+	s.log.Info("Responding to event", zap.Int64("event_id", eventID), zap.Int64("user_id", userID), zap.String("status", status))
+	switch status {
+	case models.AttendeeAccepted, models.AttendeeDeclined, models.AttendeeTentative, models.AttendeeNeedsAction:
+	default:
+		return ErrInvalidResponse
+	}
+	return s.respond(ctx, eventID, models.Attendee{UserID: userID, Status: status})
+}
+
+// GetAttendees returns the invitees of the events by event id. Events without invitees are
+// left out.
+func (s *CalendarService) GetAttendees(ctx context.Context, eventIDs []int64) (map[int64][]models.Attendee, error) {
+	s.log.Info("Getting attendees", zap.Int64s("event_ids", eventIDs))
+	return s.repo.GetAttendees(ctx, eventIDs)
+}
+
+func (s *CalendarService) respond(ctx context.Context, eventID int64, attendee models.Attendee) error {
+	if err := s.repo.RespondToEvent(ctx, eventID, attendee); err != nil {
+		return err
+	}
+	if s.notifier != nil {
+		event, err := s.repo.GetEvent(ctx, eventID)
+		if err != nil {
+			s.log.Error("Failed to load event for notification", zap.Int64("event_id", eventID), zap.Error(err))
+			return nil
+		}
+		s.notifyChange(ctx, models.NotificationEventResponded, event)
+	}
+	return nil
+}
+
+// declineOutOfOffice answers for internal invitees who are out of office when the event
+// (its first occurrence, for a series) takes place. Invitees already on a stored event keep
+// their answer; only new invitations are declined.
+func (s *CalendarService) declineOutOfOffice(ctx context.Context, event *models.Event) error {
+	var userIDs []int64
+	for _, a := range event.Attendees {
+		if a.UserID != 0 {
+			userIDs = append(userIDs, a.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+	periods, err := s.repo.GetOutOfOffice(ctx, userIDs, event.Start, event.End)
+	if err != nil {
+		return err
+	}
+	away := make(map[int64]models.OutOfOffice, len(periods))
+	for _, ooo := range periods {
+		away[ooo.UserID] = ooo
+	}
+	for i := range event.Attendees {
+		a := &event.Attendees[i]
+		if ooo, ok := away[a.UserID]; ok && a.UserID != 0 {
+			s.log.Info("Declining invitation, attendee is out of office", zap.Int64("user_id", a.UserID), zap.Int64("event_id", event.ID))
+			a.Status, a.Comment = models.AttendeeDeclined, ooo.Message
+		}
+	}
+	return nil
+}
+
+// bookResources lets invited resources answer. The event is already saved, so a failure is
+// only logged and the resources stay at needs-action.
+func (s *CalendarService) bookResources(ctx context.Context, event *models.Event) {
+	if s.resources == nil {
+		return
+	}
+	if err := s.resources.BookResources(ctx, event); err != nil {
+		s.log.Error("Failed to book resources", zap.Int64("event_id", event.ID), zap.Error(err))
+	}
+}
+
+func hasResources(attendees []models.Attendee) bool {
+	for _, a := range attendees {
+		if a.ResourceID != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOrganizer makes sure userID organizes the event; invitees may only respond to it.
+func (s *CalendarService) checkOrganizer(ctx context.Context, eventID, userID int64) error {
+	event, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event.UserID != userID {
+		return fmt.Errorf("user %d does not organize event %d: %w", userID, eventID, models.ErrForbidden)
+	}
+	return nil
+}
+
+// normalizeAttendees validates the invitees, drops duplicates and the organizer, and resets
+// their status; stored responses of invitees who stay on the list are kept by the repository.
+func normalizeAttendees(event *models.Event) error {
+	if event.Attendees == nil {
+		return nil
+	}
+	seenUsers := make(map[int64]bool)
+	seenResources := make(map[int64]bool)
+	seenEmails := make(map[string]bool)
+	attendees := make([]models.Attendee, 0, len(event.Attendees))
+	for _, a := range event.Attendees {
+		a.EventID = event.ID
+		a.Status = models.AttendeeNeedsAction
+		switch {
+		case a.UserID < 0 || a.ResourceID < 0 || (a.UserID > 0 && a.ResourceID > 0):
+			return fmt.Errorf("%w: attendee needs exactly one of user_id, resource_id or email", ErrInvalidEvent)
+		case a.ResourceID > 0:
+			if seenResources[a.ResourceID] {
+				continue
+			}
+			seenResources[a.ResourceID] = true
+			a.Email = ""
+		case a.UserID > 0:
+			if a.UserID == event.UserID || seenUsers[a.UserID] {
+				continue
+			}
+			seenUsers[a.UserID] = true
+			a.Email = ""
+		default:
+			addr, err := mail.ParseAddress(a.Email)
+			if err != nil || addr.Name != "" {
+				return fmt.Errorf("%w: invalid attendee email %q", ErrInvalidEvent, a.Email)
+			}
+			a.Email = strings.ToLower(addr.Address)
+			if seenEmails[a.Email] {
+				continue
+			}
+			seenEmails[a.Email] = true
+		}
+		attendees = append(attendees, a)
+	}
+	event.Attendees = attendees
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCalendarService_CreateEvent_NormalizesAttendees(t *testing.T) {
+	r := &fakeRepo{}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 0), End: at(2, 10, 0), Attendees: []models.Attendee{
+		{UserID: 2},
+		{UserID: 1},
+		{UserID: 2},
+		{Email: "Guest@Example.com"},
+		{Email: "guest@example.com"},
+	}}
+	require.NoError(t, svc.CreateEvent(context.Background(), ev))
+	require.Equal(t, []models.Attendee{
+		{UserID: 2, Status: models.AttendeeNeedsAction},
+		{Email: "guest@example.com", Status: models.AttendeeNeedsAction},
+	}, r.lastEvent.Attendees)
+}
+
+func TestCalendarService_CreateEvent_InvalidAttendee(t *testing.T) {
+	r := &fakeRepo{}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 0), End: at(2, 10, 0), Attendees: []models.Attendee{{Email: "not an email"}}}
+	require.ErrorIs(t, svc.CreateEvent(context.Background(), ev), ErrInvalidEvent)
+	require.False(t, r.createCalled)
+}
+
+func TestCalendarService_UpdateEvent_OrganizerOnly(t *testing.T) {
+	r := &fakeRepo{event: &models.Event{ID: 5, UserID: 1}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{ID: 5, UserID: 2, Event: "Sync", Start: at(2, 9, 0), End: at(2, 10, 0)}
+	require.ErrorIs(t, svc.UpdateEvent(context.Background(), ev), models.ErrForbidden)
+	require.False(t, r.updateCalled)
+
+	ev.UserID = 1
+	require.NoError(t, svc.UpdateEvent(context.Background(), ev))
+	require.True(t, r.updateCalled)
+}
+
+func TestCalendarService_UpdateEvent_AttendeesNeedID(t *testing.T) {
+	svc := NewCalendarService(&fakeRepo{}, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 0), End: at(2, 10, 0), Attendees: []models.Attendee{}}
+	require.ErrorIs(t, svc.UpdateEvent(context.Background(), ev), ErrInvalidEvent)
+}
+
+func TestCalendarService_DeleteEvent_OrganizerOnly(t *testing.T) {
+	r := &fakeRepo{event: &models.Event{ID: 5, UserID: 1}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	require.ErrorIs(t, svc.DeleteEvent(context.Background(), &models.Event{ID: 5, UserID: 2}), models.ErrForbidden)
+	require.False(t, r.deleteCalled)
+
+	r.event, r.errForGet = nil, models.ErrNotFound
+	require.ErrorIs(t, svc.DeleteEvent(context.Background(), &models.Event{ID: 6, UserID: 1}), models.ErrNotFound)
+}
+
+func TestCalendarService_RespondToEvent(t *testing.T) {
+	r := &fakeRepo{}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	require.NoError(t, svc.RespondToEvent(context.Background(), 5, 2, models.AttendeeAccepted))
+	require.Equal(t, models.AttendeeAccepted, r.rsvpStatus)
+	require.ErrorIs(t, svc.RespondToEvent(context.Background(), 5, 2, "maybe"), ErrInvalidResponse)
+
+	r.errForRSVP = models.ErrNotFound
+	require.ErrorIs(t, svc.RespondToEvent(context.Background(), 5, 3, models.AttendeeDeclined), models.ErrNotFound)
+}
+
+func TestCalendarService_CreateEvent_DeclinesOutOfOffice(t *testing.T) {
+	r := &fakeRepo{outOfOffice: []models.OutOfOffice{{UserID: 3, Start: at(1, 0, 0), End: at(6, 0, 0), Message: "On holiday"}}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 0), End: at(2, 10, 0), Attendees: []models.Attendee{{UserID: 2}, {UserID: 3}}}
+	require.NoError(t, svc.CreateEvent(context.Background(), ev))
+	require.Equal(t, []models.Attendee{
+		{UserID: 2, Status: models.AttendeeNeedsAction},
+		{UserID: 3, Status: models.AttendeeDeclined, Comment: "On holiday"},
+	}, r.lastEvent.Attendees)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ConflictPolicy decides what happens when an event overlaps existing ones.
+type ConflictPolicy string
+
+const (
+	// ConflictWarn saves the event and reports the overlaps.
+	ConflictWarn ConflictPolicy = "warn"
+	// ConflictReject refuses to save an overlapping event.
+	ConflictReject ConflictPolicy = "reject"
+	// ConflictForce saves the event without looking for overlaps. Calendars marked
+	// "no double booking" still refuse overlaps at the database level.
+	ConflictForce ConflictPolicy = "force"
+)
+
+// conflictHorizon bounds how far ahead recurring series are compared.
+const conflictHorizon = 62 * 24 * time.Hour
+
+// ParseConflictPolicy maps the request value to a policy; empty means warn.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(s); p {
+	case "":
+		return ConflictWarn, nil
+	case ConflictWarn, ConflictReject, ConflictForce:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: unknown conflict policy %q", ErrInvalidEvent, s)
+	}
+}
+
+// checkConflicts returns the opaque events of the same user, including the invitations they
+// have not declined, that overlap event and, under
+// ConflictReject, models.ErrConflict when there are any.
+func (s *CalendarService) checkConflicts(ctx context.Context, event *models.Event, policy ConflictPolicy) ([]models.Event, error) {
+	if policy == ConflictForce || event.Transparency == models.TransparencyTransparent {
+		return nil, nil
+	}
+	conflicts, err := s.findConflicts(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		s.log.Info("Event overlaps existing events", zap.Int64("user_id", event.UserID), zap.Int("conflict_count", len(conflicts)), zap.String("policy", string(policy)))
+		if policy == ConflictReject {
+			return conflicts, models.ErrConflict
+		}
+	}
+	return conflicts, nil
+}
+
+func (s *CalendarService) findConflicts(ctx context.Context, event *models.Event) ([]models.Event, error) {
+	from, to := event.Start, event.End
+	if event.RRule != "" {
+		to = event.Start.Add(conflictHorizon)
+	}
+	candidates, err := s.repo.GetEventsInRange(ctx, []int64{event.UserID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	locs, err := s.locations(ctx, append(candidates, *event))
+	if err != nil {
+		return nil, err
+	}
+	mine := s.occurrences(*event, locs, from, to)
+	var conflicts []models.Event
+	for _, c := range candidates {
+		if (event.ID != 0 && c.ID == event.ID) || c.Transparency == models.TransparencyTransparent {
+			continue
+		}
+		if overlaps(mine, s.occurrences(c, locs, from, to)) {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts, nil
+}
+
+// overlaps reports whether any interval of a intersects any interval of b; both are sorted.
+func overlaps(a, b []models.BusyInterval) bool {
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if a[i].Start.Before(b[j].End) && b[j].Start.Before(a[i].End) {
+			return true
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCalendarService_CreateEventWithPolicy_Warn(t *testing.T) {
+	existing := timedEvent(1, at(2, 9, 0), at(2, 10, 0))
+	existing.ID = 7
+	r := &fakeRepo{eventsInRange: []models.Event{existing}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 30), End: at(2, 10, 30)}
+	conflicts, err := svc.CreateEventWithPolicy(context.Background(), ev, ConflictWarn)
+	require.NoError(t, err)
+	require.True(t, r.createCalled)
+	require.Len(t, conflicts, 1)
+	require.Equal(t, int64(7), conflicts[0].ID)
+}
+
+func TestCalendarService_CreateEventWithPolicy_Reject(t *testing.T) {
+	r := &fakeRepo{eventsInRange: []models.Event{timedEvent(1, at(2, 9, 0), at(2, 10, 0))}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 30), End: at(2, 10, 30)}
+	conflicts, err := svc.CreateEventWithPolicy(context.Background(), ev, ConflictReject)
+	require.ErrorIs(t, err, models.ErrConflict)
+	require.Len(t, conflicts, 1)
+	require.False(t, r.createCalled)
+}
+
+func TestCalendarService_CreateEventWithPolicy_Force(t *testing.T) {
+	r := &fakeRepo{eventsInRange: []models.Event{timedEvent(1, at(2, 9, 0), at(2, 10, 0))}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 30), End: at(2, 10, 30)}
+	conflicts, err := svc.CreateEventWithPolicy(context.Background(), ev, ConflictForce)
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+	require.True(t, r.createCalled)
+}
+
+func TestCalendarService_CreateEventWithPolicy_IgnoresTransparentAndAdjacent(t *testing.T) {
+	transparent := timedEvent(1, at(2, 8, 0), at(2, 18, 0))
+	transparent.Transparency = models.TransparencyTransparent
+	r := &fakeRepo{eventsInRange: []models.Event{transparent, timedEvent(1, at(2, 8, 0), at(2, 9, 0))}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 0), End: at(2, 10, 0)}
+	conflicts, err := svc.CreateEventWithPolicy(context.Background(), ev, ConflictReject)
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+}
+
+func TestCalendarService_CreateEventWithPolicy_Recurring(t *testing.T) {
+	standup := timedEvent(1, at(2, 9, 0), at(2, 9, 15))
+	standup.RRule = "FREQ=DAILY;COUNT=5"
+	r := &fakeRepo{eventsInRange: []models.Event{standup}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Review", Start: at(5, 9, 10), End: at(5, 10, 0)}
+	conflicts, err := svc.CreateEventWithPolicy(context.Background(), ev, ConflictWarn)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+
+	later := &models.Event{UserID: 1, Event: "Review", Start: at(9, 9, 0), End: at(9, 10, 0)}
+	conflicts, err = svc.CreateEventWithPolicy(context.Background(), later, ConflictWarn)
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+}
+
+func TestCalendarService_CreateEventWithPolicy_AcceptedInvitation(t *testing.T) {
+	// The repository returns the invitations the user has not declined.
+	invitation := timedEvent(2, at(2, 9, 0), at(2, 10, 0))
+	r := &fakeRepo{eventsInRange: []models.Event{invitation}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 30), End: at(2, 10, 30)}
+	conflicts, err := svc.CreateEventWithPolicy(context.Background(), ev, ConflictReject)
+	require.ErrorIs(t, err, models.ErrConflict)
+	require.Len(t, conflicts, 1)
+}
+
+func TestCalendarService_UpdateEventWithPolicy_SkipsItself(t *testing.T) {
+	self := timedEvent(1, at(2, 9, 0), at(2, 10, 0))
+	self.ID = 3
+	r := &fakeRepo{eventsInRange: []models.Event{self}, event: &self}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	ev := &models.Event{ID: 3, UserID: 1, Event: "Sync", Start: at(2, 9, 30), End: at(2, 10, 30)}
+	conflicts, err := svc.UpdateEventWithPolicy(context.Background(), ev, ConflictReject)
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+	require.True(t, r.updateCalled)
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	p, err := ParseConflictPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, ConflictWarn, p)
+	p, err = ParseConflictPolicy("reject")
+	require.NoError(t, err)
+	require.Equal(t, ConflictReject, p)
+	_, err = ParseConflictPolicy("maybe")
+	require.Error(t, err)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/rrule"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+var ErrInvalidWindow = errors.New("invalid time window")
+
+const (
+	maxFreeBusyWindow = 62 * 24 * time.Hour
+	maxFreeBusyUsers  = 50
+)
+
+// FreeBusy returns the merged busy intervals of every user within [from, to). Only the
+// intervals are exposed, never event details, so private events stay private. Events block
+// their organizer and the invitees who have not declined; transparent events do not block
+// time and recurring events are expanded within the window.
+func (s *CalendarService) FreeBusy(ctx context.Context, userIDs []int64, from, to time.Time) (map[int64][]models.BusyInterval, error) {
+	s.log.Info("Getting free/busy", zap.Int64s("user_ids", userIDs), zap.Time("from", from), zap.Time("to", to))
+	if !to.After(from) || to.Sub(from) > maxFreeBusyWindow || len(userIDs) == 0 || len(userIDs) > maxFreeBusyUsers {
+		return nil, ErrInvalidWindow
+	}
+	events, err := s.repo.GetEventsInRange(ctx, userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	locs, err := s.locations(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+	attendees, err := s.attendeesOf(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+	busy := make(map[int64][]models.BusyInterval, len(userIDs))
+	for _, id := range userIDs {
+		busy[id] = []models.BusyInterval{}
+	}
+	for _, ev := range events {
+		if ev.Transparency == models.TransparencyTransparent {
+			continue
+		}
+		var intervals []models.BusyInterval
+		for _, id := range blocked(ev, attendees[ev.ID]) {
+			if _, ok := busy[id]; !ok {
+				continue
+			}
+			if intervals == nil {
+				intervals = s.occurrences(ev, locs, from, to)
+			}
+			busy[id] = append(busy[id], intervals...)
+		}
+	}
+	for id, intervals := range busy {
+		busy[id] = mergeIntervals(intervals)
+	}
+	return busy, nil
+}
+
+// GetEventsInRange returns the events of the users with an occurrence in [from, to), within
+// the free/busy limits, including the invitations they have not declined. A recurring event
+// is returned once, as its series. The invitees may be missing; GetAttendees reads those of
+// many events at once.
+func (s *CalendarService) GetEventsInRange(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Event, error) {
+	s.log.Info("Getting events in range", zap.Int64s("user_ids", userIDs), zap.Time("from", from), zap.Time("to", to))
+	if !to.After(from) || to.Sub(from) > maxFreeBusyWindow || len(userIDs) == 0 || len(userIDs) > maxFreeBusyUsers {
+		return nil, ErrInvalidWindow
+	}
+	events, err := s.repo.GetEventsInRange(ctx, userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	locs, err := s.locations(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+	// The repository returns every series starting before to, ended or not.
+	inRange := make([]models.Event, 0, len(events))
+	for _, ev := range events {
+		if ev.RRule == "" || len(s.occurrences(ev, locs, from, to)) > 0 {
+			inRange = append(inRange, ev)
+		}
+	}
+	return inRange, nil
+}
+
+// attendeesOf reads the invitees of events by event id.
+func (s *CalendarService) attendeesOf(ctx context.Context, events []models.Event) (map[int64][]models.Attendee, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	ids := make([]int64, len(events))
+	for i, ev := range events {
+		ids[i] = ev.ID
+	}
+	return s.repo.GetAttendees(ctx, ids)
+}
+
+// blocked returns the users whose time ev blocks: its organizer and the internal invitees
+// who have not declined.
+func blocked(ev models.Event, attendees []models.Attendee) []int64 {
+	users := []int64{ev.UserID}
+	for _, a := range attendees {
+		if a.UserID != 0 && a.UserID != ev.UserID && a.Status != models.AttendeeDeclined {
+			users = append(users, a.UserID)
+		}
+	}
+	return users
+}
+
+// locations returns the locations recurring events are expanded in: the time zone of each
+// organizer's profile. Organizers without one are missing and expand in UTC.
+func (s *CalendarService) locations(ctx context.Context, events []models.Event) (map[int64]*time.Location, error) {
+	var userIDs []int64
+	seen := make(map[int64]bool)
+	for _, ev := range events {
+		if ev.RRule != "" && !seen[ev.UserID] {
+			seen[ev.UserID] = true
+			userIDs = append(userIDs, ev.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	zones, err := s.repo.GetTimeZones(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	locs := make(map[int64]*time.Location, len(zones))
+	for id, tz := range zones {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			s.log.Warn("Expanding recurrences in UTC for unknown time zone", zap.Int64("user_id", id), zap.String("time_zone", tz))
+			continue
+		}
+		locs[id] = loc
+	}
+	return locs, nil
+}
+
+// Location returns the time zone of the user's profile, or UTC when the user has none or
+// it is unknown.
+func (s *CalendarService) Location(ctx context.Context, userID int64) (*time.Location, error) {
+	zones, err := s.repo.GetTimeZones(ctx, []int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	tz, ok := zones[userID]
+	if !ok {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		s.log.Warn("Using UTC for unknown time zone", zap.Int64("user_id", userID), zap.String("time_zone", tz))
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// occurrences returns the intervals of ev (expanding its recurrence in the organizer's
+// location from locs) clipped to [from, to).
+func (s *CalendarService) occurrences(ev models.Event, locs map[int64]*time.Location, from, to time.Time) []models.BusyInterval {
+	duration := ev.End.Sub(ev.Start)
+	starts := []time.Time{ev.Start}
+	if ev.RRule != "" {
+		rule, err := rrule.Parse(ev.RRule)
+		if err != nil {
+			s.log.Warn("Skipping event with invalid recurrence", zap.Int64("event_id", ev.ID), zap.Error(err))
+			return nil
+		}
+		loc := locs[ev.UserID]
+		if loc == nil {
+			loc = time.UTC
+		}
+		if starts, err = rule.Between(ev.Start.In(loc), duration, from, to); err != nil {
+			s.log.Warn("Recurrence expanded only partly", zap.Int64("event_id", ev.ID), zap.Error(err))
+		}
+	}
+	var out []models.BusyInterval
+	for _, start := range starts {
+		start = start.UTC()
+		end := start.Add(duration)
+		if !start.Before(to) || !end.After(from) {
+			continue
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		out = append(out, models.BusyInterval{Start: start, End: end})
+	}
+	return out
+}
+
+// mergeIntervals sorts intervals and merges overlapping or touching ones.
+func mergeIntervals(intervals []models.BusyInterval) []models.BusyInterval {
+	if len(intervals) == 0 {
+		return intervals
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	merged := []models.BusyInterval{intervals[0]}
+	for _, iv := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if iv.Start.After(last.End) {
+			merged = append(merged, iv)
+			continue
+		}
+		if iv.End.After(last.End) {
+			last.End = iv.End
+		}
+	}
+	return merged
+}
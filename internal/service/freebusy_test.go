@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func at(d, h, m int) time.Time {
+	return time.Date(2025, 6, d, h, m, 0, 0, time.UTC)
+}
+
+func timedEvent(userID int64, start, end time.Time) models.Event {
+	return models.Event{UserID: userID, Start: start, End: end, Transparency: models.TransparencyOpaque}
+}
+
+func TestCalendarService_FreeBusy_MergesAndClips(t *testing.T) {
+	recurring := timedEvent(2, at(2, 12, 0), at(2, 13, 0))
+	recurring.RRule = "FREQ=DAILY;COUNT=5"
+	transparent := timedEvent(1, at(2, 8, 0), at(2, 18, 0))
+	transparent.Transparency = models.TransparencyTransparent
+
+	r := &fakeRepo{eventsInRange: []models.Event{
+		timedEvent(1, at(2, 9, 0), at(2, 10, 0)),
+		timedEvent(1, at(2, 9, 30), at(2, 11, 0)),
+		timedEvent(1, at(2, 11, 0), at(2, 11, 30)),
+		timedEvent(1, at(2, 16, 0), at(2, 19, 0)),
+		transparent,
+		recurring,
+	}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	busy, err := svc.FreeBusy(context.Background(), []int64{1, 2, 3}, at(2, 8, 0), at(4, 0, 0))
+	require.NoError(t, err)
+	require.Equal(t, []models.BusyInterval{
+		{Start: at(2, 9, 0), End: at(2, 11, 30)},
+		{Start: at(2, 16, 0), End: at(2, 19, 0)},
+	}, busy[1])
+	require.Equal(t, []models.BusyInterval{
+		{Start: at(2, 12, 0), End: at(2, 13, 0)},
+		{Start: at(3, 12, 0), End: at(3, 13, 0)},
+	}, busy[2])
+	require.Empty(t, busy[3])
+	require.NotNil(t, busy[3])
+}
+
+func TestCalendarService_FreeBusy_InviteesAreBusy(t *testing.T) {
+	meeting := timedEvent(1, at(2, 10, 0), at(2, 11, 0))
+	meeting.ID = 7
+	r := &fakeRepo{
+		eventsInRange: []models.Event{meeting},
+		attendees: map[int64][]models.Attendee{7: {
+			{EventID: 7, UserID: 2, Status: models.AttendeeAccepted},
+			{EventID: 7, UserID: 3, Status: models.AttendeeDeclined},
+			{EventID: 7, Email: "guest@example.com", Status: models.AttendeeAccepted},
+		}},
+	}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	busy, err := svc.FreeBusy(context.Background(), []int64{2, 3}, at(2, 0, 0), at(3, 0, 0))
+	require.NoError(t, err)
+	require.Equal(t, []models.BusyInterval{{Start: at(2, 10, 0), End: at(2, 11, 0)}}, busy[2])
+	require.Empty(t, busy[3])
+	require.NotContains(t, busy, int64(1))
+}
+
+func TestCalendarService_FreeBusy_ExpandsInOrganizerTimeZone(t *testing.T) {
+	// Thursdays at 09:00 in Berlin, stored in UTC; summer time starts on 2025-03-30.
+	weekly := models.Event{
+		UserID:       1,
+		Start:        time.Date(2025, 3, 20, 8, 0, 0, 0, time.UTC),
+		End:          time.Date(2025, 3, 20, 9, 0, 0, 0, time.UTC),
+		Transparency: models.TransparencyOpaque,
+		RRule:        "FREQ=WEEKLY",
+	}
+	r := &fakeRepo{eventsInRange: []models.Event{weekly}, timeZones: map[int64]string{1: "Europe/Berlin"}}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	from, to := time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC)
+	busy, err := svc.FreeBusy(context.Background(), []int64{1}, from, to)
+	require.NoError(t, err)
+	require.Equal(t, []models.BusyInterval{
+		{Start: time.Date(2025, 3, 27, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 3, 27, 9, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2025, 4, 3, 7, 0, 0, 0, time.UTC), End: time.Date(2025, 4, 3, 8, 0, 0, 0, time.UTC)},
+	}, busy[1])
+}
+
+func TestCalendarService_FreeBusy_InvalidWindow(t *testing.T) {
+	svc := NewCalendarService(&fakeRepo{}, zap.NewNop())
+
+	_, err := svc.FreeBusy(context.Background(), []int64{1}, at(2, 10, 0), at(2, 9, 0))
+	require.ErrorIs(t, err, ErrInvalidWindow)
+	_, err = svc.FreeBusy(context.Background(), []int64{1}, at(1, 0, 0), at(1, 0, 0).AddDate(0, 3, 0))
+	require.ErrorIs(t, err, ErrInvalidWindow)
+	_, err = svc.FreeBusy(context.Background(), nil, at(1, 0, 0), at(2, 0, 0))
+	require.ErrorIs(t, err, ErrInvalidWindow)
+}
+
+func TestCalendarService_CreateEvent_Normalizes(t *testing.T) {
+	r := &fakeRepo{}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	allDay := &models.Event{UserID: 1, Date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), Event: "Holiday"}
+	require.NoError(t, svc.CreateEvent(context.Background(), allDay))
+	require.True(t, allDay.AllDay)
+	require.Equal(t, at(2, 0, 0), allDay.Start)
+	require.Equal(t, at(3, 0, 0), allDay.End)
+	require.Equal(t, models.TransparencyTransparent, allDay.Transparency)
+	require.Equal(t, models.VisibilityDefault, allDay.Visibility)
+
+	timed := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 0), End: at(2, 10, 0)}
+	require.NoError(t, svc.CreateEvent(context.Background(), timed))
+	require.False(t, timed.AllDay)
+	require.Equal(t, models.TransparencyOpaque, timed.Transparency)
+}
+
+func TestCalendarService_CreateEvent_Invalid(t *testing.T) {
+	r := &fakeRepo{}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	for _, ev := range []*models.Event{
+		{UserID: 1, Start: at(2, 10, 0), End: at(2, 9, 0)},
+		{UserID: 1, Transparency: "sometimes"},
+		{UserID: 1, Visibility: "secret"},
+		{UserID: 1, RRule: "FREQ=SECONDLY"},
+	} {
+		require.ErrorIs(t, svc.CreateEvent(context.Background(), ev), ErrInvalidEvent)
+	}
+	require.False(t, r.createCalled)
+}
+
+func TestCalendarService_GetEventsInRange(t *testing.T) {
+	ended := timedEvent(1, at(1, 9, 0), at(1, 10, 0))
+	ended.RRule = "FREQ=DAILY;COUNT=1"
+	daily := timedEvent(1, at(1, 12, 0), at(1, 13, 0))
+	daily.RRule = "FREQ=DAILY"
+	single := timedEvent(2, at(2, 9, 0), at(2, 10, 0))
+	svc := NewCalendarService(&fakeRepo{eventsInRange: []models.Event{ended, daily, single}}, zap.NewNop())
+
+	events, err := svc.GetEventsInRange(context.Background(), []int64{1, 2}, at(2, 0, 0), at(3, 0, 0))
+	require.NoError(t, err)
+	require.Equal(t, []models.Event{daily, single}, events)
+
+	_, err = svc.GetEventsInRange(context.Background(), []int64{1}, at(1, 0, 0), at(1, 0, 0).AddDate(0, 3, 0))
+	require.ErrorIs(t, err, ErrInvalidWindow)
+	_, err = svc.GetEventsInRange(context.Background(), nil, at(1, 0, 0), at(2, 0, 0))
+	require.ErrorIs(t, err, ErrInvalidWindow)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidVersion = errors.New("invalid version")
+
+type HistoryRepository interface {
+	GetHistory(ctx context.Context, eventID int64) ([]models.AuditEntry, error)
+}
+
+// HistoryService shows who changed an event and when, and reverts events to earlier versions.
+type HistoryService struct {
+	calendar *CalendarService
+	repo     HistoryRepository
+	log      *zap.Logger
+}
+
+func NewHistoryService(calendar *CalendarService, repo HistoryRepository, log *zap.Logger) *HistoryService {
+	return &HistoryService{calendar: calendar, repo: repo, log: log.Named("HistoryService")}
+}
+
+// GetHistory returns the changes of an event, oldest first. It is shown to everyone who
+// organized or was invited to any version of the event, also after it was deleted.
+func (s *HistoryService) GetHistory(ctx context.Context, userID, eventID int64) ([]models.AuditEntry, error) {
+	s.log.Info("Getting history", zap.Int64("user_id", userID), zap.Int64("event_id", eventID))
+	entries, err := s.repo.GetHistory(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("event %d: %w", eventID, models.ErrNotFound)
+	}
+	for _, e := range entries {
+		if involves(e.Before, userID) || involves(e.After, userID) {
+			return entries, nil
+		}
+	}
+	return nil, fmt.Errorf("user %d is not invited to event %d: %w", userID, eventID, models.ErrForbidden)
+}
+
+// RevertEvent updates the event to the state after the audit entry version. Only the
+// organizer may revert, and invitees who stay on the list keep their responses.
+func (s *HistoryService) RevertEvent(ctx context.Context, userID, eventID, version int64) (*models.Event, error) {
+	s.log.Info("Reverting event", zap.Int64("user_id", userID), zap.Int64("event_id", eventID), zap.Int64("version", version))
+	entries, err := s.GetHistory(ctx, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	var entry *models.AuditEntry
+	for i := range entries {
+		if entries[i].ID == version {
+			entry = &entries[i]
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("version %d of event %d: %w", version, eventID, models.ErrNotFound)
+	}
+	if len(entry.After) == 0 || string(entry.After) == "null" {
+		return nil, fmt.Errorf("%w: the event does not exist after %s", ErrInvalidVersion, entry.Action)
+	}
+	event := &models.Event{}
+	if err := json.Unmarshal(entry.After, event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVersion, err)
+	}
+	event.ID, event.UserID = eventID, userID
+	if event.Attendees == nil {
+		event.Attendees = []models.Attendee{}
+	}
+	if err := s.calendar.UpdateEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// involves reports whether userID organizes or is invited to the event snapshot.
+func involves(snapshot json.RawMessage, userID int64) bool {
+	if len(snapshot) == 0 {
+		return false
+	}
+	var event models.Event
+	if err := json.Unmarshal(snapshot, &event); err != nil {
+		return false
+	}
+	if event.UserID == userID {
+		return true
+	}
+	for _, a := range event.Attendees {
+		if a.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
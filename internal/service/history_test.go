@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeHistoryRepo []models.AuditEntry
+
+func (f fakeHistoryRepo) GetHistory(ctx context.Context, eventID int64) ([]models.AuditEntry, error) {
+	var out []models.AuditEntry
+	for _, e := range f {
+		if e.EventID == eventID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func snapshot(t *testing.T, event models.Event) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	return data
+}
+
+// history is the log of event 5: created by user 1 with user 2 invited, moved and
+// uninvited, then deleted.
+func history(t *testing.T) fakeHistoryRepo {
+	created := timedEvent(1, at(2, 9, 0), at(2, 10, 0))
+	created.ID, created.Event = 5, "Planning"
+	created.Attendees = []models.Attendee{{EventID: 5, UserID: 2, Status: models.AttendeeAccepted}}
+	moved := created
+	moved.Start, moved.End, moved.Attendees = at(2, 14, 0), at(2, 15, 0), nil
+	return fakeHistoryRepo{
+		{ID: 1, EventID: 5, Actor: "user:1", Action: models.AuditCreate, After: snapshot(t, created)},
+		{ID: 2, EventID: 5, Actor: "user:1", Action: models.AuditUpdate, Before: snapshot(t, created), After: snapshot(t, moved)},
+		{ID: 3, EventID: 5, Actor: "user:1", Action: models.AuditDelete, Before: snapshot(t, moved)},
+	}
+}
+
+func TestHistoryService_GetHistory(t *testing.T) {
+	svc := NewHistoryService(NewCalendarService(&fakeRepo{}, zap.NewNop()), history(t), zap.NewNop())
+
+	entries, err := svc.GetHistory(context.Background(), 1, 5)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	// A former invitee still sees the history.
+	_, err = svc.GetHistory(context.Background(), 2, 5)
+	require.NoError(t, err)
+	_, err = svc.GetHistory(context.Background(), 3, 5)
+	require.ErrorIs(t, err, models.ErrForbidden)
+	_, err = svc.GetHistory(context.Background(), 1, 6)
+	require.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func TestHistoryService_RevertEvent(t *testing.T) {
+	repo := &fakeRepo{event: &models.Event{ID: 5, UserID: 1}}
+	svc := NewHistoryService(NewCalendarService(repo, zap.NewNop()), history(t), zap.NewNop())
+
+	event, err := svc.RevertEvent(context.Background(), 1, 5, 1)
+	require.NoError(t, err)
+	require.True(t, repo.updateCalled)
+	require.Equal(t, at(2, 9, 0), repo.lastEvent.Start)
+	require.Equal(t, "Planning", event.Event)
+	require.Equal(t, []models.Attendee{{EventID: 5, UserID: 2, Status: models.AttendeeNeedsAction}}, event.Attendees)
+
+	// Reverting to a version without invitees clears the list.
+	event, err = svc.RevertEvent(context.Background(), 1, 5, 2)
+	require.NoError(t, err)
+	require.Empty(t, event.Attendees)
+	require.NotNil(t, event.Attendees)
+
+	_, err = svc.RevertEvent(context.Background(), 1, 5, 3)
+	require.ErrorIs(t, err, ErrInvalidVersion)
+	_, err = svc.RevertEvent(context.Background(), 1, 5, 9)
+	require.ErrorIs(t, err, models.ErrNotFound)
+	// Invitees see the history but cannot revert.
+	_, err = svc.RevertEvent(context.Background(), 2, 5, 1)
+	require.ErrorIs(t, err, models.ErrForbidden)
+}
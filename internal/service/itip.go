@@ -0,0 +1,85 @@
+package service
+
+import (
+	"awesomeProject/internal/ical"
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+)
+
+var ErrInvalidReply = errors.New("invalid iTIP reply")
+
+var replyStatuses = map[string]string{
+	ical.PartStatAccepted:    models.AttendeeAccepted,
+	ical.PartStatDeclined:    models.AttendeeDeclined,
+	ical.PartStatTentative:   models.AttendeeTentative,
+	ical.PartStatNeedsAction: models.AttendeeNeedsAction,
+}
+
+// ApplyReply records the answers carried by an iTIP REPLY from external attendees. Each
+// event of the reply must carry exactly one ATTENDEE, who must be an external invitee of the
+// stored event; every event is checked before the first answer is recorded. The answers are
+// then recorded one event at a time, so a storage failure can leave the earlier ones in
+// place. Replies to an older SEQUENCE than the stored event are ignored, as RFC 5546 requires.
+func (s *CalendarService) ApplyReply(ctx context.Context, data []byte) error {
+	cal, err := ical.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReply, err)
+	}
+	if cal.Method != ical.MethodReply || len(cal.Events) == 0 {
+		return fmt.Errorf("%w: expected METHOD:REPLY with an event", ErrInvalidReply)
+	}
+	type answer struct {
+		eventID  int64
+		attendee models.Attendee
+	}
+	var answers []answer
+	for _, ev := range cal.Events {
+		id, ok := ical.ParseEventUID(ev.UID)
+		if !ok {
+			return fmt.Errorf("event %q: %w", ev.UID, models.ErrNotFound)
+		}
+		if len(ev.Attendees) != 1 {
+			return fmt.Errorf("%w: expected one ATTENDEE, got %d", ErrInvalidReply, len(ev.Attendees))
+		}
+		a := ev.Attendees[0]
+		status, ok := replyStatuses[a.PartStat]
+		if !ok {
+			return fmt.Errorf("%w: unknown PARTSTAT %q", ErrInvalidReply, a.PartStat)
+		}
+		s.log.Info("Applying iTIP reply", zap.Int64("event_id", id), zap.Int("sequence", ev.Sequence))
+		stored, err := s.repo.GetEvent(ctx, id)
+		if err != nil {
+			return err
+		}
+		email, ok := inviteeEmail(stored, a.Email)
+		if !ok {
+			return fmt.Errorf("attendee %q of event %d: %w", a.Email, id, models.ErrNotFound)
+		}
+		if ev.Sequence < stored.Sequence {
+			s.log.Info("Ignoring reply to an outdated invitation", zap.Int64("event_id", id), zap.Int("sequence", ev.Sequence), zap.Int("current", stored.Sequence))
+			continue
+		}
+		answers = append(answers, answer{eventID: id, attendee: models.Attendee{Email: email, Status: status}})
+	}
+	for _, ans := range answers {
+		if err := s.respond(ctx, ans.eventID, ans.attendee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inviteeEmail finds the external invitee of event with the email, compared without case,
+// and returns the address as stored, which is what the repository matches the answer on.
+func inviteeEmail(event *models.Event, email string) (string, bool) {
+	for _, a := range event.Attendees {
+		if a.UserID == 0 && a.ResourceID == 0 && strings.EqualFold(a.Email, email) {
+			return a.Email, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func reply(sequence, partstat string) []byte {
+	return []byte(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"METHOD:REPLY",
+		"BEGIN:VEVENT",
+		"UID:event-5@awesomeProject",
+		"SEQUENCE:" + sequence,
+		"ATTENDEE;PARTSTAT=" + partstat + ":mailto:Guest@Example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n"))
+}
+
+// invited is the stored event the replies answer, with guest@example.com invited.
+func invited(sequence int) *models.Event {
+	return &models.Event{ID: 5, UserID: 1, Sequence: sequence, Attendees: []models.Attendee{
+		{EventID: 5, UserID: 2},
+		{EventID: 5, Email: "guest@example.com"},
+	}}
+}
+
+func TestCalendarService_ApplyReply(t *testing.T) {
+	r := &fakeRepo{event: invited(2)}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	require.NoError(t, svc.ApplyReply(context.Background(), reply("2", "DECLINED")))
+	require.Equal(t, models.AttendeeDeclined, r.rsvpStatus)
+	require.Equal(t, "guest@example.com", r.rsvpEmail)
+}
+
+func TestCalendarService_ApplyReply_AnswersAsStoredInvitee(t *testing.T) {
+	stored := invited(2)
+	stored.Attendees[1].Email = "GUEST@example.com"
+	r := &fakeRepo{event: stored}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	require.NoError(t, svc.ApplyReply(context.Background(), reply("2", "ACCEPTED")))
+	require.Equal(t, "GUEST@example.com", r.rsvpEmail)
+}
+
+func TestCalendarService_ApplyReply_IgnoresOutdated(t *testing.T) {
+	r := &fakeRepo{event: invited(2)}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	require.NoError(t, svc.ApplyReply(context.Background(), reply("1", "ACCEPTED")))
+	require.Empty(t, r.rsvpStatus)
+}
+
+func TestCalendarService_ApplyReply_Invalid(t *testing.T) {
+	svc := NewCalendarService(&fakeRepo{event: invited(0)}, zap.NewNop())
+
+	require.ErrorIs(t, svc.ApplyReply(context.Background(), []byte("garbage")), ErrInvalidReply)
+	require.ErrorIs(t, svc.ApplyReply(context.Background(), reply("0", "MAYBE")), ErrInvalidReply)
+	request := []byte(strings.Replace(string(reply("0", "ACCEPTED")), "METHOD:REPLY", "METHOD:REQUEST", 1))
+	require.ErrorIs(t, svc.ApplyReply(context.Background(), request), ErrInvalidReply)
+}
+
+func TestCalendarService_ApplyReply_RejectsOtherAttendees(t *testing.T) {
+	r := &fakeRepo{event: invited(0)}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	twice := strings.Replace(string(reply("0", "ACCEPTED")), "END:VEVENT",
+		"ATTENDEE;PARTSTAT=DECLINED:mailto:guest@example.com\r\nEND:VEVENT", 1)
+	require.ErrorIs(t, svc.ApplyReply(context.Background(), []byte(twice)), ErrInvalidReply)
+	stranger := strings.Replace(string(reply("0", "ACCEPTED")), "Guest@Example.com", "stranger@example.com", 1)
+	require.ErrorIs(t, svc.ApplyReply(context.Background(), []byte(stranger)), models.ErrNotFound)
+	require.Empty(t, r.rsvpStatus)
+}
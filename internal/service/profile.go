@@ -0,0 +1,68 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"net/mail"
+	"time"
+)
+
+var (
+	ErrInvalidEmail        = errors.New("invalid email address")
+	ErrInvalidTimeZone     = errors.New("invalid time zone")
+	ErrInvalidWorkingHours = errors.New("invalid working hours")
+	ErrInvalidOutOfOffice  = errors.New("invalid out of office period")
+)
+
+const maxOutOfOfficeMessage = 1000
+
+type ProfileRepository interface {
+	UpsertProfile(ctx context.Context, profile *models.Profile) error
+	GetProfile(ctx context.Context, userID int64) (*models.Profile, error)
+}
+
+type ProfileService struct {
+	repo ProfileRepository
+	log  *zap.Logger
+}
+
+func NewProfileService(repo ProfileRepository, log *zap.Logger) *ProfileService {
+	return &ProfileService{repo: repo, log: log.Named("ProfileService")}
+}
+
+func (s *ProfileService) UpdateProfile(ctx context.Context, profile *models.Profile) error {
+	s.log.Info("Updating profile", zap.Int64("user_id", profile.UserID))
+	if profile.Email != "" {
+		addr, err := mail.ParseAddress(profile.Email)
+		if err != nil || addr.Name != "" {
+			return ErrInvalidEmail
+		}
+	}
+	if profile.TimeZone == "" {
+		profile.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(profile.TimeZone); err != nil {
+		return ErrInvalidTimeZone
+	}
+	seen := make(map[time.Weekday]bool)
+	for _, wh := range profile.WorkingHours {
+		if wh.Weekday < time.Sunday || wh.Weekday > time.Saturday || seen[wh.Weekday] ||
+			wh.StartMinute < 0 || wh.EndMinute > 24*60 || wh.StartMinute >= wh.EndMinute {
+			return ErrInvalidWorkingHours
+		}
+		seen[wh.Weekday] = true
+	}
+	for _, ooo := range profile.OutOfOffice {
+		if !ooo.End.After(ooo.Start) || len(ooo.Message) > maxOutOfOfficeMessage {
+			return ErrInvalidOutOfOffice
+		}
+	}
+	return s.repo.UpsertProfile(ctx, profile)
+}
+
+func (s *ProfileService) GetProfile(ctx context.Context, userID int64) (*models.Profile, error) {
+	s.log.Info("Getting profile", zap.Int64("user_id", userID))
+	return s.repo.GetProfile(ctx, userID)
+}
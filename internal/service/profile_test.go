@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeProfileRepo struct {
+	saved *models.Profile
+}
+
+func (f *fakeProfileRepo) UpsertProfile(ctx context.Context, profile *models.Profile) error {
+	f.saved = profile
+	return nil
+}
+
+func (f *fakeProfileRepo) GetProfile(ctx context.Context, userID int64) (*models.Profile, error) {
+	return nil, models.ErrNotFound
+}
+
+func TestProfileService_UpdateProfile_WorkingHoursAndOutOfOffice(t *testing.T) {
+	r := &fakeProfileRepo{}
+	svc := NewProfileService(r, zap.NewNop())
+
+	profile := &models.Profile{
+		UserID:       1,
+		WorkingHours: []models.WorkingHours{{Weekday: time.Monday, StartMinute: 8 * 60, EndMinute: 24 * 60}},
+		OutOfOffice:  []models.OutOfOffice{{Start: at(2, 0, 0), End: at(6, 0, 0), Message: "Away"}},
+	}
+	require.NoError(t, svc.UpdateProfile(context.Background(), profile))
+	require.Equal(t, "UTC", r.saved.TimeZone)
+
+	r.saved = nil
+	duplicate := &models.Profile{UserID: 1, WorkingHours: []models.WorkingHours{
+		{Weekday: time.Monday, StartMinute: 9 * 60, EndMinute: 12 * 60},
+		{Weekday: time.Monday, StartMinute: 13 * 60, EndMinute: 17 * 60},
+	}}
+	require.ErrorIs(t, svc.UpdateProfile(context.Background(), duplicate), ErrInvalidWorkingHours)
+	backwards := &models.Profile{UserID: 1, WorkingHours: []models.WorkingHours{{Weekday: time.Friday, StartMinute: 17 * 60, EndMinute: 9 * 60}}}
+	require.ErrorIs(t, svc.UpdateProfile(context.Background(), backwards), ErrInvalidWorkingHours)
+	empty := &models.Profile{UserID: 1, OutOfOffice: []models.OutOfOffice{{Start: at(2, 0, 0), End: at(2, 0, 0)}}}
+	require.ErrorIs(t, svc.UpdateProfile(context.Background(), empty), ErrInvalidOutOfOffice)
+	require.Nil(t, r.saved)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidReminder = errors.New("invalid reminder offset")
+
+// maxReminderOffset bounds how far ahead of an event a reminder may fire.
+const maxReminderOffset = 4 * 7 * 24 * time.Hour
+
+type ReminderRepository interface {
+	CreateReminder(ctx context.Context, reminder *models.Reminder) error
+	DeleteReminder(ctx context.Context, reminder *models.Reminder) error
+	GetReminders(ctx context.Context, userID int64, eventID int64) ([]models.Reminder, error)
+	GetRemindersForEvents(ctx context.Context, userIDs, eventIDs []int64) ([]models.Reminder, error)
+}
+
+type ReminderService struct {
+	repo ReminderRepository
+	log  *zap.Logger
+}
+
+func NewReminderService(repo ReminderRepository, log *zap.Logger) *ReminderService {
+	return &ReminderService{repo: repo, log: log.Named("ReminderService")}
+}
+
+func (s *ReminderService) CreateReminder(ctx context.Context, reminder *models.Reminder) error {
+	s.log.Info("Creating reminder", zap.Int64("user_id", reminder.UserID), zap.Int64("event_id", reminder.EventID), zap.Duration("before", reminder.Before))
+	if reminder.Before < 0 || reminder.Before > maxReminderOffset || reminder.Before%time.Minute != 0 {
+		return ErrInvalidReminder
+	}
+	return s.repo.CreateReminder(ctx, reminder)
+}
+
+func (s *ReminderService) DeleteReminder(ctx context.Context, reminder *models.Reminder) error {
+	s.log.Info("Deleting reminder", zap.Int64("user_id", reminder.UserID), zap.Int64("id", reminder.ID))
+	return s.repo.DeleteReminder(ctx, reminder)
+}
+
+func (s *ReminderService) GetReminders(ctx context.Context, userID int64, eventID int64) ([]models.Reminder, error) {
+	s.log.Info("Getting reminders", zap.Int64("user_id", userID), zap.Int64("event_id", eventID))
+	return s.repo.GetReminders(ctx, userID, eventID)
+}
+
+// GetRemindersForEvents returns the reminders userIDs[i] set on eventIDs[i], for every i.
+func (s *ReminderService) GetRemindersForEvents(ctx context.Context, userIDs, eventIDs []int64) ([]models.Reminder, error) {
+	s.log.Info("Getting reminders for events", zap.Int("events", len(eventIDs)))
+	if len(userIDs) != len(eventIDs) {
+		return nil, fmt.Errorf("%d users for %d events", len(userIDs), len(eventIDs))
+	}
+	return s.repo.GetRemindersForEvents(ctx, userIDs, eventIDs)
+}
+
+// ParseReminderOffset parses offsets like "15m", "2h", "1d" or "1w".
+func ParseReminderOffset(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidReminder
+	}
+	unit := time.Duration(0)
+	switch s[len(s)-1] {
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	}
+	if unit == 0 {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidReminder, s)
+		}
+		return d, nil
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidReminder, s)
+	}
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeReminderRepo implements ReminderRepository for testing.
+type fakeReminderRepo struct {
+	created   *models.Reminder
+	deleted   *models.Reminder
+	reminders []models.Reminder
+	err       error
+}
+
+func (f *fakeReminderRepo) CreateReminder(ctx context.Context, reminder *models.Reminder) error {
+	f.created = reminder
+	return f.err
+}
+
+func (f *fakeReminderRepo) DeleteReminder(ctx context.Context, reminder *models.Reminder) error {
+	f.deleted = reminder
+	return f.err
+}
+
+func (f *fakeReminderRepo) GetReminders(ctx context.Context, userID int64, eventID int64) ([]models.Reminder, error) {
+	return f.reminders, f.err
+}
+
+func (f *fakeReminderRepo) GetRemindersForEvents(ctx context.Context, userIDs, eventIDs []int64) ([]models.Reminder, error) {
+	return f.reminders, f.err
+}
+
+func TestParseReminderOffset(t *testing.T) {
+	cases := map[string]time.Duration{
+		"15m": 15 * time.Minute,
+		"2h":  2 * time.Hour,
+		"1d":  24 * time.Hour,
+		"1w":  7 * 24 * time.Hour,
+		"0m":  0,
+	}
+	for in, want := range cases {
+		got, err := ParseReminderOffset(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "d", "-1d", "soon", "15"} {
+		_, err := ParseReminderOffset(in)
+		require.ErrorIs(t, err, ErrInvalidReminder, in)
+	}
+}
+
+func TestReminderService_CreateReminder(t *testing.T) {
+	r := &fakeReminderRepo{}
+	svc := NewReminderService(r, zap.NewNop())
+
+	rem := &models.Reminder{UserID: 1, EventID: 2, Before: 15 * time.Minute}
+	require.NoError(t, svc.CreateReminder(context.Background(), rem))
+	require.Equal(t, rem, r.created)
+}
+
+func TestReminderService_CreateReminder_InvalidOffset(t *testing.T) {
+	r := &fakeReminderRepo{}
+	svc := NewReminderService(r, zap.NewNop())
+
+	for _, before := range []time.Duration{-time.Minute, 30 * time.Second, 5 * 7 * 24 * time.Hour} {
+		err := svc.CreateReminder(context.Background(), &models.Reminder{UserID: 1, EventID: 2, Before: before})
+		require.ErrorIs(t, err, ErrInvalidReminder)
+	}
+	require.Nil(t, r.created)
+}
+
+func TestReminderService_DeleteReminder_Error(t *testing.T) {
+	r := &fakeReminderRepo{err: errors.New("db failure")}
+	svc := NewReminderService(r, zap.NewNop())
+
+	err := svc.DeleteReminder(context.Background(), &models.Reminder{ID: 3, UserID: 1})
+	require.Error(t, err)
+	require.Equal(t, int64(3), r.deleted.ID)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+var ErrInvalidResource = errors.New("invalid resource")
+
+var resourceKinds = map[string]bool{
+	models.ResourceRoom:      true,
+	models.ResourceProjector: true,
+	models.ResourceCar:       true,
+}
+
+type ResourceRepository interface {
+	CreateResource(ctx context.Context, resource *models.Resource) error
+	DeleteResource(ctx context.Context, id int64) error
+	GetResources(ctx context.Context, filter models.ResourceFilter) ([]models.Resource, error)
+	GetBookings(ctx context.Context, resourceIDs []int64, from, to time.Time) (map[int64][]models.Event, error)
+	BookResources(ctx context.Context, eventID int64, from, to time.Time,
+		decide func(event models.Event, resources []models.Resource, bookings map[int64][]models.Event) map[int64]string) (*models.Event, error)
+}
+
+// ResourceService manages rooms and equipment. Resources are booked by inviting them to an
+// event; they accept when free and large enough and decline otherwise.
+type ResourceService struct {
+	calendar *CalendarService
+	repo     ResourceRepository
+	log      *zap.Logger
+}
+
+func NewResourceService(calendar *CalendarService, repo ResourceRepository, log *zap.Logger) *ResourceService {
+	return &ResourceService{calendar: calendar, repo: repo, log: log.Named("ResourceService")}
+}
+
+func (s *ResourceService) CreateResource(ctx context.Context, resource *models.Resource) error {
+	s.log.Info("Creating resource", zap.String("kind", resource.Kind), zap.String("name", resource.Name))
+	if !resourceKinds[resource.Kind] || resource.Name == "" || resource.Capacity < 0 {
+		return ErrInvalidResource
+	}
+	return s.repo.CreateResource(ctx, resource)
+}
+
+func (s *ResourceService) DeleteResource(ctx context.Context, id int64) error {
+	s.log.Info("Deleting resource", zap.Int64("id", id))
+	return s.repo.DeleteResource(ctx, id)
+}
+
+func (s *ResourceService) GetResources(ctx context.Context, filter models.ResourceFilter) ([]models.Resource, error) {
+	s.log.Info("Getting resources", zap.String("kind", filter.Kind), zap.Int("min_capacity", filter.MinCapacity))
+	return s.repo.GetResources(ctx, filter)
+}
+
+// FindFreeResources returns the resources matching filter that have no accepted booking in [from, to).
+func (s *ResourceService) FindFreeResources(ctx context.Context, filter models.ResourceFilter, from, to time.Time) ([]models.Resource, error) {
+	s.log.Info("Finding free resources", zap.String("kind", filter.Kind), zap.Int("min_capacity", filter.MinCapacity), zap.Time("from", from), zap.Time("to", to))
+	if !to.After(from) || to.Sub(from) > maxFreeBusyWindow {
+		return nil, ErrInvalidWindow
+	}
+	resources, err := s.repo.GetResources(ctx, filter)
+	if err != nil || len(resources) == 0 {
+		return resources, err
+	}
+	ids := make([]int64, 0, len(resources))
+	for _, res := range resources {
+		ids = append(ids, res.ID)
+	}
+	bookings, err := s.repo.GetBookings(ctx, ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var booked []models.Event
+	for _, b := range bookings {
+		booked = append(booked, b...)
+	}
+	locs, err := s.calendar.locations(ctx, booked)
+	if err != nil {
+		return nil, err
+	}
+	free := []models.Resource{}
+	for _, res := range resources {
+		busy := false
+		for _, b := range bookings[res.ID] {
+			if len(s.calendar.occurrences(b, locs, from, to)) > 0 {
+				busy = true
+				break
+			}
+		}
+		if !busy {
+			free = append(free, res)
+		}
+	}
+	return free, nil
+}
+
+// BookResources answers for every resource invited to the stored event and copies the
+// resulting attendee list onto event.
+func (s *ResourceService) BookResources(ctx context.Context, event *models.Event) error {
+	s.log.Info("Booking resources", zap.Int64("event_id", event.ID))
+	from, to := event.Start, event.End
+	if event.RRule != "" {
+		to = event.Start.Add(conflictHorizon)
+	}
+	booked, err := s.repo.BookResources(ctx, event.ID, from, to, func(ev models.Event, resources []models.Resource, bookings map[int64][]models.Event) map[int64]string {
+		return s.decide(ctx, ev, resources, bookings, from, to)
+	})
+	if err != nil {
+		return err
+	}
+	event.Attendees = booked.Attendees
+	return nil
+}
+
+// decide accepts a resource unless it is too small for the people invited or already booked.
+func (s *ResourceService) decide(ctx context.Context, event models.Event, resources []models.Resource, bookings map[int64][]models.Event, from, to time.Time) map[int64]string {
+	people := 1
+	for _, a := range event.Attendees {
+		if a.ResourceID == 0 && a.Status != models.AttendeeDeclined {
+			people++
+		}
+	}
+	events := []models.Event{event}
+	for _, b := range bookings {
+		events = append(events, b...)
+	}
+	locs, err := s.calendar.locations(ctx, events)
+	if err != nil {
+		s.log.Warn("Expanding recurrences in UTC", zap.Int64("event_id", event.ID), zap.Error(err))
+	}
+	mine := s.calendar.occurrences(event, locs, from, to)
+	statuses := make(map[int64]string, len(resources))
+	for _, res := range resources {
+		status := models.AttendeeAccepted
+		if res.Capacity > 0 && people > res.Capacity {
+			status = models.AttendeeDeclined
+		}
+		for _, b := range bookings[res.ID] {
+			if status == models.AttendeeAccepted && overlaps(mine, s.calendar.occurrences(b, locs, from, to)) {
+				status = models.AttendeeDeclined
+			}
+		}
+		if status == models.AttendeeDeclined {
+			s.log.Info("Resource declined", zap.Int64("resource_id", res.ID), zap.Int64("event_id", event.ID))
+		}
+		statuses[res.ID] = status
+	}
+	return statuses
+}
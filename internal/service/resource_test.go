@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeResourceRepo struct {
+	created   *models.Resource
+	resources []models.Resource
+	bookings  map[int64][]models.Event
+	stored    models.Event
+	decided   map[int64]string
+}
+
+func (f *fakeResourceRepo) CreateResource(ctx context.Context, resource *models.Resource) error {
+	f.created = resource
+	return nil
+}
+
+func (f *fakeResourceRepo) DeleteResource(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (f *fakeResourceRepo) GetResources(ctx context.Context, filter models.ResourceFilter) ([]models.Resource, error) {
+	return f.resources, nil
+}
+
+func (f *fakeResourceRepo) GetBookings(ctx context.Context, resourceIDs []int64, from, to time.Time) (map[int64][]models.Event, error) {
+	return f.bookings, nil
+}
+
+func (f *fakeResourceRepo) BookResources(ctx context.Context, eventID int64, from, to time.Time,
+	decide func(event models.Event, resources []models.Resource, bookings map[int64][]models.Event) map[int64]string) (*models.Event, error) {
+	f.decided = decide(f.stored, f.resources, f.bookings)
+	booked := f.stored
+	booked.Attendees = nil
+	for _, a := range f.stored.Attendees {
+		if status, ok := f.decided[a.ResourceID]; ok && a.ResourceID != 0 {
+			a.Status = status
+		}
+		booked.Attendees = append(booked.Attendees, a)
+	}
+	return &booked, nil
+}
+
+func rooms() []models.Resource {
+	return []models.Resource{
+		{ID: 1, Kind: models.ResourceRoom, Name: "Big", Capacity: 10},
+		{ID: 2, Kind: models.ResourceRoom, Name: "Busy", Capacity: 12},
+		{ID: 3, Kind: models.ResourceRoom, Name: "Daily", Capacity: 8},
+	}
+}
+
+func TestResourceService_CreateResource_Invalid(t *testing.T) {
+	r := &fakeResourceRepo{}
+	svc := NewResourceService(NewCalendarService(&fakeRepo{}, zap.NewNop()), r, zap.NewNop())
+
+	require.ErrorIs(t, svc.CreateResource(context.Background(), &models.Resource{Kind: "boat", Name: "Boat"}), ErrInvalidResource)
+	require.ErrorIs(t, svc.CreateResource(context.Background(), &models.Resource{Kind: models.ResourceRoom, Name: "Room", Capacity: -1}), ErrInvalidResource)
+	require.Nil(t, r.created)
+}
+
+func TestResourceService_FindFreeResources(t *testing.T) {
+	daily := timedEvent(1, at(1, 14, 30), at(1, 15, 30))
+	daily.RRule = "FREQ=DAILY"
+	r := &fakeResourceRepo{resources: rooms(), bookings: map[int64][]models.Event{
+		1: {timedEvent(1, at(2, 13, 0), at(2, 14, 0))},
+		2: {timedEvent(1, at(2, 14, 45), at(2, 16, 0))},
+		3: {daily},
+	}}
+	svc := NewResourceService(NewCalendarService(&fakeRepo{}, zap.NewNop()), r, zap.NewNop())
+
+	free, err := svc.FindFreeResources(context.Background(), models.ResourceFilter{Kind: models.ResourceRoom, MinCapacity: 8}, at(2, 14, 0), at(2, 15, 0))
+	require.NoError(t, err)
+	require.Len(t, free, 1)
+	require.Equal(t, int64(1), free[0].ID)
+
+	_, err = svc.FindFreeResources(context.Background(), models.ResourceFilter{}, at(2, 15, 0), at(2, 14, 0))
+	require.ErrorIs(t, err, ErrInvalidWindow)
+}
+
+func TestCalendarService_CreateEvent_BooksResources(t *testing.T) {
+	r := &fakeResourceRepo{
+		resources: []models.Resource{
+			{ID: 1, Kind: models.ResourceRoom, Capacity: 2},
+			{ID: 2, Kind: models.ResourceRoom, Capacity: 10},
+			{ID: 3, Kind: models.ResourceProjector},
+			{ID: 4, Kind: models.ResourceRoom, Capacity: 10},
+		},
+		bookings: map[int64][]models.Event{4: {timedEvent(2, at(2, 9, 30), at(2, 10, 30))}},
+	}
+	calendar := NewCalendarService(&fakeRepo{}, zap.NewNop())
+	calendar.SetResourceBooker(NewResourceService(calendar, r, zap.NewNop()))
+
+	ev := &models.Event{UserID: 1, Event: "Sync", Start: at(2, 9, 0), End: at(2, 10, 0), Attendees: []models.Attendee{
+		{UserID: 2}, {Email: "guest@example.com"},
+		{ResourceID: 1}, {ResourceID: 2}, {ResourceID: 3}, {ResourceID: 4},
+	}}
+	r.stored = *ev
+	require.NoError(t, calendar.CreateEvent(context.Background(), ev))
+	require.Equal(t, map[int64]string{
+		1: models.AttendeeDeclined,
+		2: models.AttendeeAccepted,
+		3: models.AttendeeAccepted,
+		4: models.AttendeeDeclined,
+	}, r.decided)
+	require.Equal(t, models.AttendeeAccepted, ev.Attendees[3].Status)
+}
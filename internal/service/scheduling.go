@@ -0,0 +1,238 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+var ErrInvalidSlotRequest = errors.New("invalid slot request")
+
+const (
+	defaultSlotStep  = 15 * time.Minute
+	defaultSlotLimit = 10
+	maxSlotLimit     = 100
+	// maxSlack caps how much free time around a slot still improves its score.
+	maxSlack = 2 * time.Hour
+)
+
+// defaultWorkingHours applies to users that did not configure any: Monday to Friday, 09:00-17:00.
+var defaultWorkingHours = func() []models.WorkingHours {
+	var hours []models.WorkingHours
+	for wd := time.Monday; wd <= time.Friday; wd++ {
+		hours = append(hours, models.WorkingHours{Weekday: wd, StartMinute: 9 * 60, EndMinute: 17 * 60})
+	}
+	return hours
+}()
+
+type ScheduleRepository interface {
+	GetSchedules(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Schedule, error)
+}
+
+// SlotRequest describes a meeting to find time for.
+type SlotRequest struct {
+	UserIDs  []int64
+	From     time.Time
+	To       time.Time
+	Duration time.Duration
+	// Buffer is kept free between the meeting and any other busy time.
+	Buffer time.Duration
+	Limit  int
+}
+
+type SchedulingService struct {
+	calendar *CalendarService
+	repo     ScheduleRepository
+	log      *zap.Logger
+}
+
+func NewSchedulingService(calendar *CalendarService, repo ScheduleRepository, log *zap.Logger) *SchedulingService {
+	return &SchedulingService{calendar: calendar, repo: repo, log: log.Named("SchedulingService")}
+}
+
+// FindSlots returns candidate meeting times inside every attendee's working hours that
+// avoid their out-of-office periods and their busy time plus the buffer. Slots start on 15 minute boundaries and are ranked
+// by how much free time surrounds them, earlier slots first on ties.
+func (s *SchedulingService) FindSlots(ctx context.Context, req SlotRequest) ([]models.Slot, error) {
+	s.log.Info("Finding slots", zap.Int64s("user_ids", req.UserIDs), zap.Time("from", req.From), zap.Time("to", req.To), zap.Duration("duration", req.Duration))
+	if req.Duration <= 0 || req.Buffer < 0 || req.Limit < 0 || req.Limit > maxSlotLimit {
+		return nil, ErrInvalidSlotRequest
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSlotLimit
+	}
+	busy, err := s.calendar.FreeBusy(ctx, req.UserIDs, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.repo.GetSchedules(ctx, req.UserIDs, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	free := []models.BusyInterval{{Start: req.From, End: req.To}}
+	for _, schedule := range schedules {
+		working, err := availableIntervals(schedule, req.From, req.To)
+		if err != nil {
+			return nil, err
+		}
+		free = intersectIntervals(free, subtractIntervals(working, padIntervals(busy[schedule.UserID], req.Buffer)))
+	}
+
+	var slots []models.Slot
+	for _, iv := range free {
+		for start := ceilTime(iv.Start, defaultSlotStep); !start.Add(req.Duration).After(iv.End); start = start.Add(defaultSlotStep) {
+			end := start.Add(req.Duration)
+			slack := min(start.Sub(iv.Start), iv.End.Sub(end), maxSlack)
+			slots = append(slots, models.Slot{Start: start, End: end, Score: slack.Minutes()})
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Score != slots[j].Score {
+			return slots[i].Score > slots[j].Score
+		}
+		return slots[i].Start.Before(slots[j].Start)
+	})
+	if len(slots) > req.Limit {
+		slots = slots[:req.Limit]
+	}
+	return slots, nil
+}
+
+// FreeBusy is CalendarService.FreeBusy with time outside each user's working hours and
+// their out-of-office periods reported as busy too.
+func (s *SchedulingService) FreeBusy(ctx context.Context, userIDs []int64, from, to time.Time) (map[int64][]models.BusyInterval, error) {
+	busy, err := s.calendar.FreeBusy(ctx, userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.repo.GetSchedules(ctx, userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	window := []models.BusyInterval{{Start: from, End: to}}
+	for _, schedule := range schedules {
+		available, err := availableIntervals(schedule, from, to)
+		if err != nil {
+			return nil, err
+		}
+		unavailable := subtractIntervals(window, available)
+		busy[schedule.UserID] = mergeIntervals(append(busy[schedule.UserID], unavailable...))
+	}
+	return busy, nil
+}
+
+// availableIntervals is the working time within [from, to) minus out-of-office periods.
+func availableIntervals(schedule models.Schedule, from, to time.Time) ([]models.BusyInterval, error) {
+	working, err := workingIntervals(schedule, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var away []models.BusyInterval
+	for _, ooo := range schedule.OutOfOffice {
+		away = append(away, models.BusyInterval{Start: ooo.Start, End: ooo.End})
+	}
+	return subtractIntervals(working, mergeIntervals(away)), nil
+}
+
+// workingIntervals expands a weekly schedule into absolute intervals within [from, to),
+// evaluating each local day in the user's time zone so DST shifts are respected.
+func workingIntervals(schedule models.Schedule, from, to time.Time) ([]models.BusyInterval, error) {
+	tz := schedule.TimeZone
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	hours := schedule.WorkingHours
+	if len(hours) == 0 {
+		hours = defaultWorkingHours
+	}
+	var out []models.BusyInterval
+	local := from.In(loc)
+	for day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, wh := range hours {
+			if wh.Weekday != day.Weekday() {
+				continue
+			}
+			start := time.Date(day.Year(), day.Month(), day.Day(), 0, wh.StartMinute, 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), 0, wh.EndMinute, 0, 0, loc)
+			out = append(out, models.BusyInterval{Start: start, End: end})
+		}
+	}
+	return intersectIntervals(mergeIntervals(out), []models.BusyInterval{{Start: from, End: to}}), nil
+}
+
+// padIntervals widens every interval by buffer on both sides.
+func padIntervals(intervals []models.BusyInterval, buffer time.Duration) []models.BusyInterval {
+	out := make([]models.BusyInterval, len(intervals))
+	for i, iv := range intervals {
+		out[i] = models.BusyInterval{Start: iv.Start.Add(-buffer), End: iv.End.Add(buffer)}
+	}
+	return mergeIntervals(out)
+}
+
+// intersectIntervals intersects two sorted, non-overlapping interval lists.
+func intersectIntervals(a, b []models.BusyInterval) []models.BusyInterval {
+	var out []models.BusyInterval
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start := later(a[i].Start, b[j].Start)
+		end := earlier(a[i].End, b[j].End)
+		if start.Before(end) {
+			out = append(out, models.BusyInterval{Start: start, End: end})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// subtractIntervals removes the sorted, merged intervals in cut from the sorted intervals in base.
+func subtractIntervals(base, cut []models.BusyInterval) []models.BusyInterval {
+	var out []models.BusyInterval
+	for _, iv := range base {
+		start := iv.Start
+		for _, c := range cut {
+			if !c.End.After(start) || !c.Start.Before(iv.End) {
+				continue
+			}
+			if c.Start.After(start) {
+				out = append(out, models.BusyInterval{Start: start, End: c.Start})
+			}
+			start = later(start, c.End)
+		}
+		if start.Before(iv.End) {
+			out = append(out, models.BusyInterval{Start: start, End: iv.End})
+		}
+	}
+	return out
+}
+
+func ceilTime(t time.Time, step time.Duration) time.Time {
+	if r := t.Truncate(step); !r.Equal(t) {
+		return r.Add(step)
+	}
+	return t
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
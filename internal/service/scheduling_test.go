@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeScheduleRepo implements ScheduleRepository for testing.
+type fakeScheduleRepo map[int64]models.Schedule
+
+func (f fakeScheduleRepo) GetSchedules(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Schedule, error) {
+	out := make([]models.Schedule, len(userIDs))
+	for i, id := range userIDs {
+		out[i] = f[id]
+		out[i].UserID = id
+	}
+	return out, nil
+}
+
+func TestSchedulingService_FindSlots_IntersectsWorkingHoursAcrossTimeZones(t *testing.T) {
+	// Monday 2 June 2025. User 1 works 09-17 in Berlin (07-15 UTC), user 2 defaults to 09-17 UTC.
+	r := &fakeRepo{eventsInRange: []models.Event{
+		timedEvent(2, at(2, 9, 0), at(2, 12, 0)),
+	}}
+	schedules := fakeScheduleRepo{
+		1: {TimeZone: "Europe/Berlin", WorkingHours: []models.WorkingHours{{Weekday: time.Monday, StartMinute: 9 * 60, EndMinute: 17 * 60}}},
+	}
+	svc := NewSchedulingService(NewCalendarService(r, zap.NewNop()), schedules, zap.NewNop())
+
+	slots, err := svc.FindSlots(context.Background(), SlotRequest{
+		UserIDs:  []int64{1, 2},
+		From:     at(2, 0, 0),
+		To:       at(3, 0, 0),
+		Duration: time.Hour,
+		Limit:    100,
+	})
+	require.NoError(t, err)
+	// Common free time is 12:00-15:00 UTC.
+	require.Len(t, slots, 9)
+	for _, s := range slots {
+		require.False(t, s.Start.Before(at(2, 12, 0)), s.Start)
+		require.False(t, s.End.After(at(2, 15, 0)), s.End)
+	}
+	// The slot centred in the free block ranks first.
+	require.Equal(t, at(2, 13, 0), slots[0].Start)
+	require.Equal(t, float64(60), slots[0].Score)
+}
+
+func TestSchedulingService_FindSlots_Buffer(t *testing.T) {
+	r := &fakeRepo{eventsInRange: []models.Event{
+		timedEvent(1, at(2, 9, 0), at(2, 10, 0)),
+		timedEvent(1, at(2, 11, 0), at(2, 17, 0)),
+	}}
+	svc := NewSchedulingService(NewCalendarService(r, zap.NewNop()), fakeScheduleRepo{}, zap.NewNop())
+
+	req := SlotRequest{UserIDs: []int64{1}, From: at(2, 0, 0), To: at(3, 0, 0), Duration: 30 * time.Minute}
+	slots, err := svc.FindSlots(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, slots, 3)
+
+	req.Buffer = 15 * time.Minute
+	slots, err = svc.FindSlots(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, []models.Slot{{Start: at(2, 10, 15), End: at(2, 10, 45), Score: 0}}, slots)
+}
+
+func TestSchedulingService_FindSlots_SkipsWeekends(t *testing.T) {
+	svc := NewSchedulingService(NewCalendarService(&fakeRepo{}, zap.NewNop()), fakeScheduleRepo{}, zap.NewNop())
+
+	// Saturday 7 and Sunday 8 June 2025.
+	slots, err := svc.FindSlots(context.Background(), SlotRequest{UserIDs: []int64{1}, From: at(7, 0, 0), To: at(9, 0, 0), Duration: time.Hour})
+	require.NoError(t, err)
+	require.Empty(t, slots)
+}
+
+func TestSchedulingService_FindSlots_Invalid(t *testing.T) {
+	svc := NewSchedulingService(NewCalendarService(&fakeRepo{}, zap.NewNop()), fakeScheduleRepo{}, zap.NewNop())
+
+	_, err := svc.FindSlots(context.Background(), SlotRequest{UserIDs: []int64{1}, From: at(2, 0, 0), To: at(3, 0, 0)})
+	require.ErrorIs(t, err, ErrInvalidSlotRequest)
+
+	_, err = svc.FindSlots(context.Background(), SlotRequest{
+		UserIDs: []int64{1}, From: at(2, 0, 0), To: at(3, 0, 0), Duration: time.Hour, Limit: 1000,
+	})
+	require.ErrorIs(t, err, ErrInvalidSlotRequest)
+}
+
+func TestSchedulingService_FindSlots_SkipsOutOfOffice(t *testing.T) {
+	schedules := fakeScheduleRepo{
+		1: {OutOfOffice: []models.OutOfOffice{{UserID: 1, Start: at(2, 0, 0), End: at(2, 16, 0)}}},
+	}
+	svc := NewSchedulingService(NewCalendarService(&fakeRepo{}, zap.NewNop()), schedules, zap.NewNop())
+
+	slots, err := svc.FindSlots(context.Background(), SlotRequest{UserIDs: []int64{1}, From: at(2, 0, 0), To: at(3, 0, 0), Duration: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	require.Equal(t, at(2, 16, 0), slots[0].Start)
+}
+
+func TestSchedulingService_FreeBusy_IncludesUnavailableTime(t *testing.T) {
+	r := &fakeRepo{eventsInRange: []models.Event{timedEvent(1, at(2, 10, 0), at(2, 11, 0))}}
+	schedules := fakeScheduleRepo{
+		1: {OutOfOffice: []models.OutOfOffice{{UserID: 1, Start: at(2, 15, 0), End: at(2, 16, 0)}}},
+	}
+	svc := NewSchedulingService(NewCalendarService(r, zap.NewNop()), schedules, zap.NewNop())
+
+	busy, err := svc.FreeBusy(context.Background(), []int64{1}, at(2, 0, 0), at(3, 0, 0))
+	require.NoError(t, err)
+	require.Equal(t, []models.BusyInterval{
+		{Start: at(2, 0, 0), End: at(2, 9, 0)},
+		{Start: at(2, 10, 0), End: at(2, 11, 0)},
+		{Start: at(2, 15, 0), End: at(2, 16, 0)},
+		{Start: at(2, 17, 0), End: at(3, 0, 0)},
+	}, busy[1])
+}
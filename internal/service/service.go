@@ -2,10 +2,17 @@ package service
 
 import (
 	"awesomeProject/internal/models"
+	"awesomeProject/internal/notifier"
+	"awesomeProject/internal/rrule"
 	"context"
+	"errors"
+	"fmt"
 	"go.uber.org/zap"
+	"time"
 )
 
+var ErrInvalidEvent = errors.New("invalid event")
+
 type CalendarRepository interface {
 	CreateEvent(ctx context.Context, event *models.Event) error
 	UpdateEvent(ctx context.Context, event *models.Event) error
@@ -13,30 +20,126 @@ type CalendarRepository interface {
 	GetEventsForDay(ctx context.Context, event *models.Event) ([]models.Event, error)
 	GetEventsForWeek(ctx context.Context, event *models.Event) ([]models.Event, error)
 	GetEventsForMonth(ctx context.Context, event *models.Event) ([]models.Event, error)
+	GetEventsInRange(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Event, error)
+	GetEvent(ctx context.Context, id int64) (*models.Event, error)
+	GetAttendees(ctx context.Context, eventIDs []int64) (map[int64][]models.Attendee, error)
+	GetOutOfOffice(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.OutOfOffice, error)
+	GetTimeZones(ctx context.Context, userIDs []int64) (map[int64]string, error)
+	RespondToEvent(ctx context.Context, eventID int64, attendee models.Attendee) error
 	Close()
 }
 
+// ResourceBooker settles the answers of the resources invited to a stored event.
+type ResourceBooker interface {
+	BookResources(ctx context.Context, event *models.Event) error
+}
+
 type CalendarService struct {
-	repo CalendarRepository
-	log  *zap.Logger
+	repo      CalendarRepository
+	notifier  notifier.Notifier
+	resources ResourceBooker
+	log       *zap.Logger
 }
 
 func NewCalendarService(repo CalendarRepository, log *zap.Logger) *CalendarService {
 	return &CalendarService{repo: repo, log: log.Named("CalendarService")}
 }
 
+// SetNotifier registers a notifier told about every successful event change.
+func (s *CalendarService) SetNotifier(n notifier.Notifier) {
+	s.notifier = n
+}
+
+// SetResourceBooker registers the booker that answers for invited rooms and equipment.
+func (s *CalendarService) SetResourceBooker(b ResourceBooker) {
+	s.resources = b
+}
+
 func (s *CalendarService) CreateEvent(ctx context.Context, event *models.Event) error {
+	_, err := s.CreateEventWithPolicy(ctx, event, ConflictWarn)
+	return err
+}
+
+// CreateEventWithPolicy creates the event and returns the events it overlaps. With
+// ConflictReject nothing is created when there are overlaps and models.ErrConflict is returned.
+func (s *CalendarService) CreateEventWithPolicy(ctx context.Context, event *models.Event, policy ConflictPolicy) ([]models.Event, error) {
 	s.log.Info("Creating event", zap.Int64("user_id", event.UserID), zap.Time("date", event.Date), zap.String("event", event.Event))
-	return s.repo.CreateEvent(ctx, event)
+	if err := normalizeEvent(event); err != nil {
+		return nil, err
+	}
+	conflicts, err := s.checkConflicts(ctx, event, policy)
+	if err != nil {
+		return conflicts, err
+	}
+	if err := s.declineOutOfOffice(ctx, event); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	if hasResources(event.Attendees) {
+		s.bookResources(ctx, event)
+	}
+	s.notifyChange(ctx, models.NotificationEventCreated, event)
+	return conflicts, nil
 }
 func (s *CalendarService) UpdateEvent(ctx context.Context, event *models.Event) error {
+	_, err := s.UpdateEventWithPolicy(ctx, event, ConflictWarn)
+	return err
+}
+
+// UpdateEventWithPolicy is the update counterpart of CreateEventWithPolicy. The event is
+// addressed by its ID; only its organizer may change it.
+func (s *CalendarService) UpdateEventWithPolicy(ctx context.Context, event *models.Event, policy ConflictPolicy) ([]models.Event, error) {
 	s.log.Info("Updating event", zap.Int64("user_id", event.UserID), zap.Time("date", event.Date), zap.String("event", event.Event))
-	return s.repo.UpdateEvent(ctx, event)
+	if err := normalizeEvent(event); err != nil {
+		return nil, err
+	}
+	if event.ID == 0 {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidEvent)
+	}
+	if err := s.checkOrganizer(ctx, event.ID, event.UserID); err != nil {
+		return nil, err
+	}
+	conflicts, err := s.checkConflicts(ctx, event, policy)
+	if err != nil {
+		return conflicts, err
+	}
+	if err := s.declineOutOfOffice(ctx, event); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	// Stored resources have to answer again for the new time.
+	s.bookResources(ctx, event)
+	s.notifyChange(ctx, models.NotificationEventUpdated, event)
+	return conflicts, nil
 }
 
 func (s *CalendarService) DeleteEvent(ctx context.Context, event *models.Event) error {
 	s.log.Info("Deleting event", zap.Int64("user_id", event.UserID), zap.String("event", event.Event))
-	return s.repo.DeleteEvent(ctx, event)
+	if event.ID != 0 {
+		if err := s.checkOrganizer(ctx, event.ID, event.UserID); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.DeleteEvent(ctx, event); err != nil {
+		return err
+	}
+	s.notifyChange(ctx, models.NotificationEventDeleted, event)
+	return nil
+}
+
+// notifyChange reports an applied change; delivery problems are logged and never fail the request.
+func (s *CalendarService) notifyChange(ctx context.Context, notificationType string, event *models.Event) {
+	if s.notifier == nil {
+		return
+	}
+	err := s.notifier.Notify(ctx, models.Notification{Type: notificationType, UserID: event.UserID, Event: *event})
+	if err != nil {
+		s.log.Error("Failed to notify about event change", zap.String("type", notificationType), zap.Error(err))
+	}
 }
 
 func (s *CalendarService) GetEventsForDay(ctx context.Context, event *models.Event) ([]models.Event, error) {
@@ -52,6 +155,43 @@ func (s *CalendarService) GetEventsForMonth(ctx context.Context, event *models.E
 	return s.repo.GetEventsForMonth(ctx, event)
 }
 
+// normalizeEvent fills in defaults and validates the timing fields of an event.
+// Events without a start are all-day events covering their date; all-day events
+// default to transparent and timed events to opaque.
+func normalizeEvent(event *models.Event) error {
+	if event.Start.IsZero() {
+		day := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, time.UTC)
+		event.AllDay = true
+		event.Start = day
+		event.End = day.AddDate(0, 0, 1)
+	} else if !event.End.After(event.Start) {
+		return fmt.Errorf("%w: end must be after start", ErrInvalidEvent)
+	}
+	switch event.Transparency {
+	case "":
+		event.Transparency = models.TransparencyOpaque
+		if event.AllDay {
+			event.Transparency = models.TransparencyTransparent
+		}
+	case models.TransparencyOpaque, models.TransparencyTransparent:
+	default:
+		return fmt.Errorf("%w: unknown transparency %q", ErrInvalidEvent, event.Transparency)
+	}
+	switch event.Visibility {
+	case "":
+		event.Visibility = models.VisibilityDefault
+	case models.VisibilityDefault, models.VisibilityPublic, models.VisibilityPrivate:
+	default:
+		return fmt.Errorf("%w: unknown visibility %q", ErrInvalidEvent, event.Visibility)
+	}
+	if event.RRule != "" {
+		if _, err := rrule.Parse(event.RRule); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+	}
+	return normalizeAttendees(event)
+}
+
 func (s *CalendarService) CloseRepo() {
 	s.log.Info("Closing repository")
 	s.repo.Close()
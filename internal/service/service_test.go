@@ -31,9 +31,19 @@ type fakeRepo struct {
 	eventsForDay   []models.Event
 	eventsForWeek  []models.Event
 	eventsForMonth []models.Event
+	eventsInRange  []models.Event
+	event          *models.Event
+	attendees      map[int64][]models.Attendee
+	outOfOffice    []models.OutOfOffice
+	timeZones      map[int64]string
+	rsvpStatus     string
+	rsvpEmail      string
 	errForDay      error
 	errForWeek     error
 	errForMonth    error
+	errForRange    error
+	errForGet      error
+	errForRSVP     error
 	errForCreate   error
 	errForUpdate   error
 	errForDelete   error
@@ -75,6 +85,32 @@ func (f *fakeRepo) GetEventsForMonth(ctx context.Context, event *models.Event) (
 	return f.eventsForMonth, f.errForMonth
 }
 
+func (f *fakeRepo) GetEventsInRange(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.Event, error) {
+	return f.eventsInRange, f.errForRange
+}
+
+func (f *fakeRepo) GetEvent(ctx context.Context, id int64) (*models.Event, error) {
+	return f.event, f.errForGet
+}
+
+func (f *fakeRepo) GetAttendees(ctx context.Context, eventIDs []int64) (map[int64][]models.Attendee, error) {
+	return f.attendees, f.errForGet
+}
+
+func (f *fakeRepo) GetOutOfOffice(ctx context.Context, userIDs []int64, from, to time.Time) ([]models.OutOfOffice, error) {
+	return f.outOfOffice, nil
+}
+
+func (f *fakeRepo) GetTimeZones(ctx context.Context, userIDs []int64) (map[int64]string, error) {
+	return f.timeZones, nil
+}
+
+func (f *fakeRepo) RespondToEvent(ctx context.Context, eventID int64, attendee models.Attendee) error {
+	f.rsvpStatus = attendee.Status
+	f.rsvpEmail = attendee.Email
+	return f.errForRSVP
+}
+
 func (f *fakeRepo) Close() {
 	f.closeCalled = true
 }
@@ -113,23 +149,34 @@ func TestCalendarService_CreateEvent_Error(t *testing.T) {
 }
 
 func TestCalendarService_UpdateEvent(t *testing.T) {
-	r := &fakeRepo{}
+	ev := newEvent(7, "UpdateName", time.Now())
+	ev.ID = 3
+	r := &fakeRepo{event: &models.Event{ID: 3, UserID: 7}}
 	log := zap.NewNop()
 	svc := NewCalendarService(r, log)
 
-	ev := newEvent(7, "UpdateName", time.Now())
 	err := svc.UpdateEvent(context.Background(), ev)
 	require.NoError(t, err)
 	require.True(t, r.updateCalled)
 	require.Equal(t, ev, r.lastEvent)
 }
 
+func TestCalendarService_UpdateEvent_RequiresID(t *testing.T) {
+	r := &fakeRepo{}
+	svc := NewCalendarService(r, zap.NewNop())
+
+	err := svc.UpdateEvent(context.Background(), newEvent(7, "UpdateName", time.Now()))
+	require.ErrorIs(t, err, ErrInvalidEvent)
+	require.False(t, r.updateCalled)
+}
+
 func TestCalendarService_UpdateEvent_Error(t *testing.T) {
-	r := &fakeRepo{errForUpdate: errors.New("cannot update")}
+	r := &fakeRepo{event: &models.Event{ID: 3, UserID: 7}, errForUpdate: errors.New("cannot update")}
 	log := zap.NewNop()
 	svc := NewCalendarService(r, log)
 
 	ev := newEvent(7, "UpdateName", time.Now())
+	ev.ID = 3
 	err := svc.UpdateEvent(context.Background(), ev)
 	require.Error(t, err)
 	require.True(t, r.updateCalled)
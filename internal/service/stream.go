@@ -0,0 +1,199 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// streamHistory is how many recent changes are kept to resume streams.
+const streamHistory = 1000
+
+// StreamService fans changes out to the open event streams of their users. Every instance
+// receives the same changes in the same order, so a stream can resume on any of them
+// after the last change it saw.
+type StreamService struct {
+	mu      sync.Mutex
+	streams map[int64]map[*Stream]struct{}
+	history []models.Change
+	seen    map[int64]bool
+	buffer  int
+	log     *zap.Logger
+}
+
+// Stream receives the changes of the users it watches, each change once. C is closed when
+// the stream is closed or falls more than the buffer behind; a lagging client reconnects
+// and resumes.
+type Stream struct {
+	C <-chan models.Change
+	// Missed are the changes after the requested last change id.
+	Missed []models.Change
+	// Reset is set when the changes after the requested id are no longer known, so the
+	// client must reload its views.
+	Reset bool
+
+	ch      chan models.Change
+	userIDs map[int64]bool
+	lagged  bool
+	service *StreamService
+}
+
+func NewStreamService(buffer int, log *zap.Logger) *StreamService {
+	return &StreamService{
+		streams: make(map[int64]map[*Stream]struct{}),
+		seen:    make(map[int64]bool),
+		buffer:  buffer,
+		log:     log.Named("StreamService"),
+	}
+}
+
+// Subscribe opens a stream of the user's changes. A non-zero lastID resumes after that change.
+func (s *StreamService) Subscribe(userID, lastID int64) *Stream {
+	s.log.Info("Opening stream", zap.Int64("user_id", userID), zap.Int64("last_id", lastID))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan models.Change, s.buffer)
+	stream := &Stream{C: ch, ch: ch, userIDs: make(map[int64]bool), service: s}
+	if lastID != 0 {
+		stream.Missed, stream.Reset = s.since(userID, lastID)
+	}
+	s.watch(stream, userID)
+	return stream
+}
+
+func (s *StreamService) watch(stream *Stream, userID int64) {
+	if s.streams[userID] == nil {
+		s.streams[userID] = make(map[*Stream]struct{})
+	}
+	s.streams[userID][stream] = struct{}{}
+	stream.userIDs[userID] = true
+}
+
+func (s *StreamService) unwatch(stream *Stream, userID int64) {
+	delete(s.streams[userID], stream)
+	if len(s.streams[userID]) == 0 {
+		delete(s.streams, userID)
+	}
+	delete(stream.userIDs, userID)
+}
+
+// since returns the user's changes after lastID, or reset when lastID is no longer kept.
+// Ids are compared by position: they arrive in commit order, not in id order.
+func (s *StreamService) since(userID, lastID int64) (missed []models.Change, reset bool) {
+	for i := len(s.history) - 1; i >= 0; i-- {
+		if s.history[i].ID != lastID {
+			continue
+		}
+		for _, c := range s.history[i+1:] {
+			if sees(c, userID) {
+				missed = append(missed, c)
+			}
+		}
+		return missed, false
+	}
+	return nil, true
+}
+
+// Publish sends the change to the streams of its users. Changes seen before are dropped,
+// since the outbox may deliver a message twice.
+func (s *StreamService) Publish(change models.Change) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[change.ID] {
+		return
+	}
+	s.seen[change.ID] = true
+	s.history = append(s.history, change)
+	if len(s.history) > streamHistory {
+		delete(s.seen, s.history[0].ID)
+		s.history = s.history[1:]
+	}
+	targets := make(map[*Stream]struct{})
+	for _, userID := range change.UserIDs {
+		for stream := range s.streams[userID] {
+			targets[stream] = struct{}{}
+		}
+	}
+	for stream := range targets {
+		select {
+		case stream.ch <- change:
+		default:
+			s.log.Warn("Stream lagging, closing it", zap.Int64("change_id", change.ID))
+			stream.lagged = true
+			s.remove(stream)
+		}
+	}
+}
+
+// Reset forgets the history and closes every stream, e.g. after changes may have been
+// missed. The clients reconnect, find their last change unknown and reload.
+func (s *StreamService) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history, s.seen = nil, make(map[int64]bool)
+	for _, streams := range s.streams {
+		for stream := range streams {
+			stream.lagged = true
+			s.remove(stream)
+		}
+	}
+}
+
+// remove closes the stream once; a closed stream watches nobody.
+func (s *StreamService) remove(stream *Stream) {
+	if stream.userIDs == nil {
+		return
+	}
+	for userID := range stream.userIDs {
+		s.unwatch(stream, userID)
+	}
+	stream.userIDs = nil
+	close(stream.ch)
+}
+
+// Watch adds the changes of the users to the stream. It does nothing on a closed stream.
+func (st *Stream) Watch(userIDs ...int64) {
+	st.service.mu.Lock()
+	defer st.service.mu.Unlock()
+	if st.userIDs == nil {
+		return
+	}
+	for _, id := range userIDs {
+		st.service.watch(st, id)
+	}
+}
+
+// Unwatch stops the changes of the users; changes already queued are still received.
+func (st *Stream) Unwatch(userIDs ...int64) {
+	st.service.mu.Lock()
+	defer st.service.mu.Unlock()
+	if st.userIDs == nil {
+		return
+	}
+	for _, id := range userIDs {
+		st.service.unwatch(st, id)
+	}
+}
+
+// Close stops the stream. It is safe to call after the stream was closed for lagging.
+func (st *Stream) Close() {
+	st.service.mu.Lock()
+	defer st.service.mu.Unlock()
+	st.service.remove(st)
+}
+
+// Lagged reports whether the stream was closed because its client fell behind.
+func (st *Stream) Lagged() bool {
+	st.service.mu.Lock()
+	defer st.service.mu.Unlock()
+	return st.lagged
+}
+
+func sees(change models.Change, userID int64) bool {
+	for _, id := range change.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func change(id int64, users ...int64) models.Change {
+	return models.Change{ID: id, Type: models.NotificationEventUpdated, EventID: id, UserIDs: users}
+}
+
+func TestStreamService_FansOutToUsers(t *testing.T) {
+	svc := NewStreamService(10, zap.NewNop())
+	one, two := svc.Subscribe(1, 0), svc.Subscribe(2, 0)
+	defer one.Close()
+	defer two.Close()
+
+	svc.Publish(change(1, 1, 2))
+	svc.Publish(change(2, 1))
+	// The outbox may deliver a message twice.
+	svc.Publish(change(2, 1))
+
+	require.Equal(t, int64(1), (<-one.C).ID)
+	require.Equal(t, int64(2), (<-one.C).ID)
+	require.Equal(t, int64(1), (<-two.C).ID)
+	require.Empty(t, one.C)
+	require.Empty(t, two.C)
+}
+
+func TestStreamService_ResumesAfterLastID(t *testing.T) {
+	svc := NewStreamService(10, zap.NewNop())
+	// Ids arrive in commit order, which is not id order.
+	for _, c := range []models.Change{change(5, 1), change(3, 1), change(4, 2), change(6, 1)} {
+		svc.Publish(c)
+	}
+
+	stream := svc.Subscribe(1, 5)
+	defer stream.Close()
+	require.False(t, stream.Reset)
+	require.Equal(t, []models.Change{change(3, 1), change(6, 1)}, stream.Missed)
+
+	unknown := svc.Subscribe(1, 99)
+	defer unknown.Close()
+	require.True(t, unknown.Reset)
+	require.Empty(t, unknown.Missed)
+}
+
+func TestStreamService_ClosesLaggingStreams(t *testing.T) {
+	svc := NewStreamService(1, zap.NewNop())
+	slow, fast := svc.Subscribe(1, 0), svc.Subscribe(1, 0)
+	defer fast.Close()
+
+	svc.Publish(change(1, 1))
+	<-fast.C
+	svc.Publish(change(2, 1))
+
+	require.Equal(t, int64(1), (<-slow.C).ID)
+	_, open := <-slow.C
+	require.False(t, open)
+	require.True(t, slow.Lagged())
+	slow.Close()
+	require.Equal(t, int64(2), (<-fast.C).ID)
+	require.False(t, fast.Lagged())
+
+	// The lagging client resumes where it stopped.
+	resumed := svc.Subscribe(1, 1)
+	defer resumed.Close()
+	require.Equal(t, []models.Change{change(2, 1)}, resumed.Missed)
+}
+
+func TestStreamService_ResetClosesStreams(t *testing.T) {
+	svc := NewStreamService(10, zap.NewNop())
+	svc.Publish(change(1, 1))
+	stream := svc.Subscribe(1, 0)
+
+	svc.Reset()
+	_, open := <-stream.C
+	require.False(t, open)
+	require.True(t, svc.Subscribe(1, 1).Reset)
+}
+
+func TestStreamService_WatchesSeveralUsers(t *testing.T) {
+	svc := NewStreamService(10, zap.NewNop())
+	stream := svc.Subscribe(1, 0)
+	defer stream.Close()
+	stream.Watch(2, 3)
+
+	// A change seen by several watched users arrives once.
+	svc.Publish(change(1, 1, 2))
+	svc.Publish(change(2, 3))
+	stream.Unwatch(3)
+	svc.Publish(change(3, 3))
+
+	require.Equal(t, int64(1), (<-stream.C).ID)
+	require.Equal(t, int64(2), (<-stream.C).ID)
+	require.Empty(t, stream.C)
+
+	stream.Close()
+	stream.Watch(4)
+	svc.Publish(change(4, 4))
+	_, open := <-stream.C
+	require.False(t, open)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSyncLimit = 100
+	maxSyncLimit     = 1000
+)
+
+var ErrInvalidSyncToken = errors.New("invalid sync token")
+
+type SyncRepository interface {
+	GetChanges(ctx context.Context, userID int64, since models.SyncCursor, limit int) (*models.SyncPage, error)
+}
+
+// SyncService serves the change feed that lets clients mirror a user's calendar without
+// reloading it: each page carries the token of the next one.
+type SyncService struct {
+	repo SyncRepository
+	log  *zap.Logger
+}
+
+func NewSyncService(repo SyncRepository, log *zap.Logger) *SyncService {
+	return &SyncService{repo: repo, log: log.Named("SyncService")}
+}
+
+// Sync returns the changes since token, or every event when token is empty. A token older
+// than the retained tombstones fails with models.ErrExpired and the client syncs in full.
+func (s *SyncService) Sync(ctx context.Context, userID int64, token string, limit int) (*models.SyncPage, error) {
+	s.log.Info("Syncing", zap.Int64("user_id", userID), zap.Int("limit", limit))
+	since, err := decodeSyncToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultSyncLimit
+	}
+	if limit > maxSyncLimit {
+		limit = maxSyncLimit
+	}
+	page, err := s.repo.GetChanges(ctx, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	page.Token = encodeSyncToken(page.Next)
+	return page, nil
+}
+
+// Tokens are opaque to clients so the cursor can change without breaking them.
+func encodeSyncToken(c models.SyncCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.XID, c.EventID)))
+}
+
+func decodeSyncToken(token string) (models.SyncCursor, error) {
+	if token == "" {
+		return models.SyncCursor{}, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return models.SyncCursor{}, ErrInvalidSyncToken
+	}
+	xid, eventID, ok := strings.Cut(string(data), ":")
+	if !ok {
+		return models.SyncCursor{}, ErrInvalidSyncToken
+	}
+	var c models.SyncCursor
+	if c.XID, err = strconv.ParseInt(xid, 10, 64); err != nil || c.XID <= 0 {
+		return models.SyncCursor{}, ErrInvalidSyncToken
+	}
+	if c.EventID, err = strconv.ParseInt(eventID, 10, 64); err != nil || c.EventID < 0 {
+		return models.SyncCursor{}, ErrInvalidSyncToken
+	}
+	return c, nil
+}
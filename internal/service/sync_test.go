@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeSyncRepo struct {
+	since models.SyncCursor
+	limit int
+	page  models.SyncPage
+	err   error
+}
+
+func (f *fakeSyncRepo) GetChanges(ctx context.Context, userID int64, since models.SyncCursor, limit int) (*models.SyncPage, error) {
+	f.since, f.limit = since, limit
+	if f.err != nil {
+		return nil, f.err
+	}
+	page := f.page
+	return &page, nil
+}
+
+func TestSyncService_TokensRoundTrip(t *testing.T) {
+	repo := &fakeSyncRepo{page: models.SyncPage{More: true, Next: models.SyncCursor{XID: 742, EventID: 9}}}
+	svc := NewSyncService(repo, zap.NewNop())
+
+	page, err := svc.Sync(context.Background(), 1, "", 0)
+	require.NoError(t, err)
+	require.Equal(t, models.SyncCursor{}, repo.since)
+	require.Equal(t, defaultSyncLimit, repo.limit)
+	require.NotEmpty(t, page.Token)
+
+	_, err = svc.Sync(context.Background(), 1, page.Token, 5000)
+	require.NoError(t, err)
+	require.Equal(t, models.SyncCursor{XID: 742, EventID: 9}, repo.since)
+	require.Equal(t, maxSyncLimit, repo.limit)
+}
+
+func TestSyncService_RejectsInvalidTokens(t *testing.T) {
+	repo := &fakeSyncRepo{}
+	svc := NewSyncService(repo, zap.NewNop())
+
+	for _, token := range []string{"not base64!", encodeSyncToken(models.SyncCursor{}), "MTIz"} {
+		_, err := svc.Sync(context.Background(), 1, token, 10)
+		require.ErrorIs(t, err, ErrInvalidSyncToken, token)
+	}
+}
+
+func TestSyncService_PassesExpiry(t *testing.T) {
+	repo := &fakeSyncRepo{err: models.ErrExpired}
+	svc := NewSyncService(repo, zap.NewNop())
+
+	_, err := svc.Sync(context.Background(), 1, encodeSyncToken(models.SyncCursor{XID: 3}), 10)
+	require.ErrorIs(t, err, models.ErrExpired)
+}
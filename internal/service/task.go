@@ -0,0 +1,217 @@
+package service
+
+import (
+	"awesomeProject/internal/ical"
+	"awesomeProject/internal/models"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTask     = errors.New("invalid task")
+	ErrInvalidCalendar = errors.New("invalid calendar data")
+)
+
+const (
+	maxTaskSummary = 1000
+	// maxExportWindow bounds the events included in an iCalendar export.
+	maxExportWindow = 366 * 24 * time.Hour
+)
+
+var taskStatuses = map[string]string{
+	models.TaskNeedsAction: ical.TodoNeedsAction,
+	models.TaskInProcess:   ical.TodoInProcess,
+	models.TaskCompleted:   ical.TodoCompleted,
+	models.TaskCancelled:   ical.TodoCancelled,
+}
+
+type TaskRepository interface {
+	CreateTask(ctx context.Context, task *models.Task) error
+	UpdateTask(ctx context.Context, task *models.Task) error
+	DeleteTask(ctx context.Context, task *models.Task) error
+	GetTasks(ctx context.Context, userID int64, from, to time.Time, includeDone bool) ([]models.Task, error)
+	UpsertTasks(ctx context.Context, tasks []models.Task) error
+}
+
+// TaskService manages to-dos and exchanges them, together with events, as iCalendar data.
+type TaskService struct {
+	calendar *CalendarService
+	repo     TaskRepository
+	log      *zap.Logger
+	now      func() time.Time
+}
+
+func NewTaskService(calendar *CalendarService, repo TaskRepository, log *zap.Logger) *TaskService {
+	return &TaskService{calendar: calendar, repo: repo, log: log.Named("TaskService"), now: time.Now}
+}
+
+func (s *TaskService) CreateTask(ctx context.Context, task *models.Task) error {
+	s.log.Info("Creating task", zap.Int64("user_id", task.UserID), zap.String("summary", task.Summary))
+	if err := s.normalize(task); err != nil {
+		return err
+	}
+	if task.UID == "" {
+		uid := make([]byte, 16)
+		if _, err := rand.Read(uid); err != nil {
+			return fmt.Errorf("failed to generate task uid: %w", err)
+		}
+		task.UID = "task-" + hex.EncodeToString(uid) + "@awesomeProject"
+	}
+	return s.repo.CreateTask(ctx, task)
+}
+
+func (s *TaskService) UpdateTask(ctx context.Context, task *models.Task) error {
+	s.log.Info("Updating task", zap.Int64("user_id", task.UserID), zap.Int64("id", task.ID))
+	if err := s.normalize(task); err != nil {
+		return err
+	}
+	return s.repo.UpdateTask(ctx, task)
+}
+
+func (s *TaskService) DeleteTask(ctx context.Context, task *models.Task) error {
+	s.log.Info("Deleting task", zap.Int64("user_id", task.UserID), zap.Int64("id", task.ID))
+	return s.repo.DeleteTask(ctx, task)
+}
+
+// GetTasks returns the user's tasks due within [from, to), or all of them when from is zero.
+func (s *TaskService) GetTasks(ctx context.Context, userID int64, from, to time.Time, includeDone bool) ([]models.Task, error) {
+	s.log.Info("Getting tasks", zap.Int64("user_id", userID), zap.Time("from", from), zap.Time("to", to), zap.Bool("include_done", includeDone))
+	if !from.IsZero() && !to.After(from) {
+		return nil, ErrInvalidWindow
+	}
+	return s.repo.GetTasks(ctx, userID, from, to, includeDone)
+}
+
+// ExportICS renders the user's own events overlapping [from, to) and all of their tasks
+// as a VCALENDAR object.
+func (s *TaskService) ExportICS(ctx context.Context, userID int64, from, to time.Time) ([]byte, error) {
+	s.log.Info("Exporting iCalendar", zap.Int64("user_id", userID), zap.Time("from", from), zap.Time("to", to))
+	if !to.After(from) || to.Sub(from) > maxExportWindow {
+		return nil, ErrInvalidWindow
+	}
+	events, err := s.calendar.repo.GetEventsInRange(ctx, []int64{userID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetTasks(ctx, userID, time.Time{}, time.Time{}, true)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	cal := &ical.Calendar{Method: ical.MethodPublish}
+	for _, ev := range events {
+		if ev.UserID != userID {
+			continue
+		}
+		cal.Events = append(cal.Events, ical.Event{
+			UID:      ical.EventUID(ev.ID),
+			Summary:  ev.Event,
+			Start:    ev.Start,
+			End:      ev.End,
+			AllDay:   ev.AllDay,
+			RRule:    ev.RRule,
+			Status:   ical.StatusConfirmed,
+			Sequence: ev.Sequence,
+			Stamp:    now,
+		})
+	}
+	for _, t := range tasks {
+		todo := ical.Todo{
+			UID:             t.UID,
+			Summary:         t.Summary,
+			Description:     t.Description,
+			Priority:        t.Priority,
+			Status:          taskStatuses[t.Status],
+			PercentComplete: t.PercentComplete,
+			Stamp:           now,
+		}
+		if t.Due != nil {
+			todo.Due = *t.Due
+		}
+		if t.CompletedAt != nil {
+			todo.Completed = *t.CompletedAt
+		}
+		cal.Todos = append(cal.Todos, todo)
+	}
+	return cal.Encode(), nil
+}
+
+// ImportICS creates or updates the user's tasks from the VTODOs in data, matching them on
+// UID. Events in the data are ignored. It returns the imported tasks.
+func (s *TaskService) ImportICS(ctx context.Context, userID int64, data []byte) ([]models.Task, error) {
+	s.log.Info("Importing iCalendar", zap.Int64("user_id", userID), zap.Int("bytes", len(data)))
+	cal, err := ical.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	tasks := make([]models.Task, 0, len(cal.Todos))
+	for _, todo := range cal.Todos {
+		if todo.UID == "" {
+			return nil, fmt.Errorf("%w: VTODO without UID", ErrInvalidCalendar)
+		}
+		task := models.Task{
+			UserID:          userID,
+			UID:             todo.UID,
+			Summary:         todo.Summary,
+			Description:     todo.Description,
+			Priority:        todo.Priority,
+			Status:          strings.ToLower(todo.Status),
+			PercentComplete: todo.PercentComplete,
+		}
+		if !todo.Due.IsZero() {
+			due := todo.Due
+			task.Due = &due
+		}
+		if !todo.Completed.IsZero() {
+			completed := todo.Completed
+			task.CompletedAt = &completed
+		}
+		if err := s.normalize(&task); err != nil {
+			return nil, fmt.Errorf("%w: task %q: %v", ErrInvalidCalendar, todo.UID, err)
+		}
+		tasks = append(tasks, task)
+	}
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+	if err := s.repo.UpsertTasks(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// normalize validates task and keeps the completion fields consistent with its status:
+// completed tasks are 100% done with a completion time, other tasks have none.
+func (s *TaskService) normalize(task *models.Task) error {
+	if task.Status == "" {
+		task.Status = models.TaskNeedsAction
+	}
+	if _, ok := taskStatuses[task.Status]; !ok {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidTask, task.Status)
+	}
+	if strings.TrimSpace(task.Summary) == "" || len(task.Summary) > maxTaskSummary {
+		return fmt.Errorf("%w: summary must be 1 to %d bytes", ErrInvalidTask, maxTaskSummary)
+	}
+	if task.Priority < 0 || task.Priority > 9 {
+		return fmt.Errorf("%w: priority must be 0 to 9", ErrInvalidTask)
+	}
+	if task.PercentComplete < 0 || task.PercentComplete > 100 {
+		return fmt.Errorf("%w: percent complete must be 0 to 100", ErrInvalidTask)
+	}
+	if task.Status != models.TaskCompleted {
+		task.CompletedAt = nil
+		return nil
+	}
+	task.PercentComplete = 100
+	if task.CompletedAt == nil {
+		now := s.now()
+		task.CompletedAt = &now
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/ical"
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeTaskRepo struct {
+	created  *models.Task
+	tasks    []models.Task
+	upserted []models.Task
+}
+
+func (f *fakeTaskRepo) CreateTask(ctx context.Context, task *models.Task) error {
+	f.created = task
+	return nil
+}
+
+func (f *fakeTaskRepo) UpdateTask(ctx context.Context, task *models.Task) error {
+	return nil
+}
+
+func (f *fakeTaskRepo) DeleteTask(ctx context.Context, task *models.Task) error {
+	return nil
+}
+
+func (f *fakeTaskRepo) GetTasks(ctx context.Context, userID int64, from, to time.Time, includeDone bool) ([]models.Task, error) {
+	return f.tasks, nil
+}
+
+func (f *fakeTaskRepo) UpsertTasks(ctx context.Context, tasks []models.Task) error {
+	f.upserted = tasks
+	return nil
+}
+
+func TestTaskService_CreateTask_CompletedSetsCompletion(t *testing.T) {
+	r := &fakeTaskRepo{}
+	svc := NewTaskService(NewCalendarService(&fakeRepo{}, zap.NewNop()), r, zap.NewNop())
+	svc.now = func() time.Time { return at(3, 12, 0) }
+
+	task := &models.Task{UserID: 1, Summary: "Send report", Status: models.TaskCompleted, PercentComplete: 40}
+	require.NoError(t, svc.CreateTask(context.Background(), task))
+	require.Equal(t, task, r.created)
+	require.Contains(t, task.UID, "@awesomeProject")
+	require.Equal(t, 100, task.PercentComplete)
+	require.Equal(t, at(3, 12, 0), *task.CompletedAt)
+}
+
+func TestTaskService_CreateTask_Invalid(t *testing.T) {
+	svc := NewTaskService(NewCalendarService(&fakeRepo{}, zap.NewNop()), &fakeTaskRepo{}, zap.NewNop())
+
+	for _, task := range []*models.Task{
+		{UserID: 1, Summary: " "},
+		{UserID: 1, Summary: "A", Priority: 10},
+		{UserID: 1, Summary: "A", PercentComplete: 101},
+		{UserID: 1, Summary: "A", Status: "done"},
+	} {
+		require.ErrorIs(t, svc.CreateTask(context.Background(), task), ErrInvalidTask)
+	}
+}
+
+func TestTaskService_UpdateTask_ReopenClearsCompletion(t *testing.T) {
+	svc := NewTaskService(NewCalendarService(&fakeRepo{}, zap.NewNop()), &fakeTaskRepo{}, zap.NewNop())
+	done := at(2, 9, 0)
+
+	task := &models.Task{ID: 4, UserID: 1, Summary: "Send report", Status: models.TaskInProcess, CompletedAt: &done}
+	require.NoError(t, svc.UpdateTask(context.Background(), task))
+	require.Nil(t, task.CompletedAt)
+}
+
+func TestTaskService_ExportICS(t *testing.T) {
+	due := at(5, 17, 0)
+	own := timedEvent(1, at(2, 9, 0), at(2, 10, 0))
+	own.ID = 7
+	other := timedEvent(2, at(2, 9, 0), at(2, 10, 0))
+	other.ID = 8
+	cal := NewCalendarService(&fakeRepo{eventsInRange: []models.Event{own, other}}, zap.NewNop())
+	r := &fakeTaskRepo{tasks: []models.Task{{ID: 1, UserID: 1, UID: "t1", Summary: "Send report", Due: &due, Priority: 1, Status: models.TaskInProcess, PercentComplete: 50}}}
+	svc := NewTaskService(cal, r, zap.NewNop())
+
+	data, err := svc.ExportICS(context.Background(), 1, at(1, 0, 0), at(8, 0, 0))
+	require.NoError(t, err)
+	parsed, err := ical.Parse(data)
+	require.NoError(t, err)
+	require.Len(t, parsed.Events, 1)
+	require.Equal(t, ical.EventUID(7), parsed.Events[0].UID)
+	require.Len(t, parsed.Todos, 1)
+	require.Equal(t, ical.TodoInProcess, parsed.Todos[0].Status)
+	require.True(t, parsed.Todos[0].Due.Equal(due))
+
+	_, err = svc.ExportICS(context.Background(), 1, at(8, 0, 0), at(1, 0, 0))
+	require.ErrorIs(t, err, ErrInvalidWindow)
+}
+
+func TestTaskService_ImportICS(t *testing.T) {
+	r := &fakeTaskRepo{}
+	svc := NewTaskService(NewCalendarService(&fakeRepo{}, zap.NewNop()), r, zap.NewNop())
+	data := (&ical.Calendar{Todos: []ical.Todo{
+		{UID: "t1", Summary: "Buy milk", Status: ical.TodoCompleted, Completed: at(2, 8, 0)},
+		{UID: "t2", Summary: "File taxes", Due: at(9, 0, 0), Priority: 2},
+	}}).Encode()
+
+	tasks, err := svc.ImportICS(context.Background(), 1, data)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	require.Equal(t, r.upserted, tasks)
+	require.Equal(t, models.TaskCompleted, tasks[0].Status)
+	require.Equal(t, 100, tasks[0].PercentComplete)
+	require.True(t, tasks[0].CompletedAt.Equal(at(2, 8, 0)))
+	require.Equal(t, models.TaskNeedsAction, tasks[1].Status)
+	require.True(t, tasks[1].Due.Equal(at(9, 0, 0)))
+
+	_, err = svc.ImportICS(context.Background(), 1, []byte("not a calendar"))
+	require.ErrorIs(t, err, ErrInvalidCalendar)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"go.uber.org/zap"
+)
+
+type TrashRepository interface {
+	GetTrash(ctx context.Context, userID int64) ([]models.TrashedEvent, error)
+	RestoreEvent(ctx context.Context, userID, eventID int64) (*models.Event, error)
+	PurgeEvent(ctx context.Context, userID, eventID int64) error
+}
+
+// TrashService lists, restores and purges deleted events. Only the organizer sees the
+// trash; a restored event reappears for its attendees as well.
+type TrashService struct {
+	calendar *CalendarService
+	repo     TrashRepository
+	log      *zap.Logger
+}
+
+func NewTrashService(calendar *CalendarService, repo TrashRepository, log *zap.Logger) *TrashService {
+	return &TrashService{calendar: calendar, repo: repo, log: log.Named("TrashService")}
+}
+
+func (s *TrashService) GetTrash(ctx context.Context, userID int64) ([]models.TrashedEvent, error) {
+	s.log.Info("Getting trash", zap.Int64("user_id", userID))
+	return s.repo.GetTrash(ctx, userID)
+}
+
+// RestoreEvent brings the event back. Its resources may have been booked by others in the
+// meantime, so they answer again.
+func (s *TrashService) RestoreEvent(ctx context.Context, userID, eventID int64) (*models.Event, error) {
+	s.log.Info("Restoring event", zap.Int64("user_id", userID), zap.Int64("event_id", eventID))
+	event, err := s.repo.RestoreEvent(ctx, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if hasResources(event.Attendees) {
+		s.calendar.bookResources(ctx, event)
+	}
+	s.calendar.notifyChange(ctx, models.NotificationEventRestored, event)
+	return event, nil
+}
+
+func (s *TrashService) PurgeEvent(ctx context.Context, userID, eventID int64) error {
+	s.log.Info("Purging event", zap.Int64("user_id", userID), zap.Int64("event_id", eventID))
+	return s.repo.PurgeEvent(ctx, userID, eventID)
+}
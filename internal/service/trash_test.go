@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeTrashRepo holds the trashed events of every user.
+type fakeTrashRepo struct {
+	trash []models.TrashedEvent
+}
+
+func (f *fakeTrashRepo) GetTrash(ctx context.Context, userID int64) ([]models.TrashedEvent, error) {
+	var out []models.TrashedEvent
+	for _, ev := range f.trash {
+		if ev.UserID == userID {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTrashRepo) RestoreEvent(ctx context.Context, userID, eventID int64) (*models.Event, error) {
+	for i, ev := range f.trash {
+		if ev.ID == eventID && ev.UserID == userID {
+			f.trash = append(f.trash[:i], f.trash[i+1:]...)
+			return &ev.Event, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (f *fakeTrashRepo) PurgeEvent(ctx context.Context, userID, eventID int64) error {
+	for i, ev := range f.trash {
+		if ev.ID == eventID && ev.UserID == userID {
+			f.trash = append(f.trash[:i], f.trash[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrNotFound
+}
+
+func TestTrashService_RestoreEvent_RebooksResources(t *testing.T) {
+	room := models.Resource{ID: 1, Kind: models.ResourceRoom, Capacity: 10}
+	trashed := timedEvent(1, at(2, 9, 0), at(2, 10, 0))
+	trashed.ID = 7
+	trashed.Attendees = []models.Attendee{{UserID: 2}, {ResourceID: 1, Status: models.AttendeeAccepted}}
+	repo := &fakeTrashRepo{trash: []models.TrashedEvent{{Event: trashed, DeletedAt: at(1, 12, 0)}}}
+	// The room was booked by someone else while the event was in the trash.
+	resources := &fakeResourceRepo{
+		resources: []models.Resource{room},
+		bookings:  map[int64][]models.Event{1: {timedEvent(3, at(2, 9, 30), at(2, 10, 30))}},
+		stored:    trashed,
+	}
+	calendar := NewCalendarService(&fakeRepo{}, zap.NewNop())
+	calendar.SetResourceBooker(NewResourceService(calendar, resources, zap.NewNop()))
+	n := &recordingNotifier{}
+	calendar.SetNotifier(n)
+	svc := NewTrashService(calendar, repo, zap.NewNop())
+
+	_, err := svc.RestoreEvent(context.Background(), 2, 7)
+	require.ErrorIs(t, err, models.ErrNotFound)
+
+	event, err := svc.RestoreEvent(context.Background(), 1, 7)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]string{1: models.AttendeeDeclined}, resources.decided)
+	require.Equal(t, models.AttendeeDeclined, event.Attendees[1].Status)
+	require.Len(t, n.got, 1)
+	require.Equal(t, models.NotificationEventRestored, n.got[0].Type)
+	require.Empty(t, repo.trash)
+}
+
+func TestTrashService_PurgeEvent(t *testing.T) {
+	ev := timedEvent(1, at(2, 9, 0), at(2, 10, 0))
+	ev.ID = 3
+	repo := &fakeTrashRepo{trash: []models.TrashedEvent{{Event: ev}}}
+	svc := NewTrashService(NewCalendarService(&fakeRepo{}, zap.NewNop()), repo, zap.NewNop())
+
+	require.ErrorIs(t, svc.PurgeEvent(context.Background(), 2, 3), models.ErrNotFound)
+	trash, err := svc.GetTrash(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+
+	require.NoError(t, svc.PurgeEvent(context.Background(), 1, 3))
+	trash, err = svc.GetTrash(context.Background(), 1)
+	require.NoError(t, err)
+	require.Empty(t, trash)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"awesomeProject/internal/models"
+	"awesomeProject/internal/webhook"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"net"
+	"net/url"
+)
+
+var ErrInvalidWebhookURL = errors.New("invalid webhook url")
+
+const deliveryLogLimit = 100
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, hook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, hook *models.Webhook) error
+	GetWebhooks(ctx context.Context, userID int64) ([]models.Webhook, error)
+	GetDeliveries(ctx context.Context, userID int64, webhookID int64, limit int) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, userID int64, deliveryID int64) (*models.WebhookDelivery, error)
+}
+
+type WebhookService struct {
+	repo     WebhookRepository
+	lookupIP func(ctx context.Context, network, host string) ([]net.IP, error)
+	log      *zap.Logger
+}
+
+func NewWebhookService(repo WebhookRepository, log *zap.Logger) *WebhookService {
+	return &WebhookService{repo: repo, lookupIP: net.DefaultResolver.LookupIP, log: log.Named("WebhookService")}
+}
+
+// CreateWebhook registers an endpoint; a signing secret is generated when none is given.
+// Hosts that resolve to a loopback, link-local, private or unspecified address are refused;
+// the sender checks the address again when it connects.
+func (s *WebhookService) CreateWebhook(ctx context.Context, hook *models.Webhook) error {
+	s.log.Info("Creating webhook", zap.Int64("user_id", hook.UserID), zap.String("url", hook.URL))
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	if err := s.checkHost(ctx, u.Hostname()); err != nil {
+		return err
+	}
+	if hook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		hook.Secret = hex.EncodeToString(secret)
+	}
+	return s.repo.CreateWebhook(ctx, hook)
+}
+
+// checkHost resolves host and fails unless every address it resolves to is public.
+func (s *WebhookService) checkHost(ctx context.Context, host string) error {
+	ips, err := s.lookupIP(ctx, "ip", host)
+	if err != nil || len(ips) == 0 {
+		return fmt.Errorf("%w: cannot resolve %s", ErrInvalidWebhookURL, host)
+	}
+	for _, ip := range ips {
+		if !webhook.PublicIP(ip) {
+			return fmt.Errorf("%w: %s", ErrInvalidWebhookURL, webhook.ErrNonPublicAddress)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, hook *models.Webhook) error {
+	s.log.Info("Deleting webhook", zap.Int64("user_id", hook.UserID), zap.Int64("id", hook.ID))
+	return s.repo.DeleteWebhook(ctx, hook)
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context, userID int64) ([]models.Webhook, error) {
+	s.log.Info("Getting webhooks", zap.Int64("user_id", userID))
+	return s.repo.GetWebhooks(ctx, userID)
+}
+
+// GetDeliveries returns the most recent deliveries, optionally limited to one webhook.
+func (s *WebhookService) GetDeliveries(ctx context.Context, userID int64, webhookID int64) ([]models.WebhookDelivery, error) {
+	s.log.Info("Getting webhook deliveries", zap.Int64("user_id", userID), zap.Int64("webhook_id", webhookID))
+	return s.repo.GetDeliveries(ctx, userID, webhookID, deliveryLogLimit)
+}
+
+func (s *WebhookService) Redeliver(ctx context.Context, userID int64, deliveryID int64) (*models.WebhookDelivery, error) {
+	s.log.Info("Redelivering webhook", zap.Int64("user_id", userID), zap.Int64("delivery_id", deliveryID))
+	return s.repo.Redeliver(ctx, userID, deliveryID)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeWebhookRepo implements WebhookRepository for testing.
+type fakeWebhookRepo struct {
+	created *models.Webhook
+}
+
+func (f *fakeWebhookRepo) CreateWebhook(ctx context.Context, hook *models.Webhook) error {
+	f.created = hook
+	return nil
+}
+
+func (f *fakeWebhookRepo) DeleteWebhook(ctx context.Context, hook *models.Webhook) error {
+	return nil
+}
+
+func (f *fakeWebhookRepo) GetWebhooks(ctx context.Context, userID int64) ([]models.Webhook, error) {
+	return nil, nil
+}
+
+func (f *fakeWebhookRepo) GetDeliveries(ctx context.Context, userID int64, webhookID int64, limit int) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (f *fakeWebhookRepo) Redeliver(ctx context.Context, userID int64, deliveryID int64) (*models.WebhookDelivery, error) {
+	return &models.WebhookDelivery{ID: deliveryID + 1}, nil
+}
+
+// newTestWebhookService resolves host names from hosts instead of DNS; IP literals resolve
+// to themselves as they do with the real resolver.
+func newTestWebhookService(r WebhookRepository, hosts map[string]string) *WebhookService {
+	svc := NewWebhookService(r, zap.NewNop())
+	svc.lookupIP = func(ctx context.Context, network, host string) ([]net.IP, error) {
+		if ip := net.ParseIP(host); ip != nil {
+			return []net.IP{ip}, nil
+		}
+		if addr, ok := hosts[host]; ok {
+			return []net.IP{net.ParseIP(addr)}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	return svc
+}
+
+func TestWebhookService_CreateWebhook_GeneratesSecret(t *testing.T) {
+	r := &fakeWebhookRepo{}
+	svc := newTestWebhookService(r, map[string]string{"example.com": "93.184.215.14"})
+
+	hook := &models.Webhook{UserID: 1, URL: "https://example.com/hook"}
+	require.NoError(t, svc.CreateWebhook(context.Background(), hook))
+	require.Len(t, r.created.Secret, 64)
+
+	keep := &models.Webhook{UserID: 1, URL: "http://example.com:9000/hook", Secret: "mine"}
+	require.NoError(t, svc.CreateWebhook(context.Background(), keep))
+	require.Equal(t, "mine", r.created.Secret)
+}
+
+func TestWebhookService_CreateWebhook_InvalidURL(t *testing.T) {
+	r := &fakeWebhookRepo{}
+	svc := newTestWebhookService(r, map[string]string{"localhost": "127.0.0.1", "internal.example.com": "10.1.2.3"})
+
+	for _, u := range []string{
+		"example.com/hook", "ftp://example.com", "http://", "://bad",
+		"http://unknown.example.com/hook",
+		"http://localhost:9000/hook",
+		"http://internal.example.com/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://192.168.1.1/hook",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+	} {
+		err := svc.CreateWebhook(context.Background(), &models.Webhook{UserID: 1, URL: u})
+		require.ErrorIs(t, err, ErrInvalidWebhookURL, u)
+	}
+	require.Nil(t, r.created)
+}
+
+func TestCalendarService_NotifiesEventChanges(t *testing.T) {
+	r := &fakeRepo{event: &models.Event{ID: 5, UserID: 2}}
+	n := &recordingNotifier{}
+	svc := NewCalendarService(r, zap.NewNop())
+	svc.SetNotifier(n)
+
+	ev := &models.Event{ID: 5, UserID: 2, Event: "Demo"}
+	require.NoError(t, svc.CreateEvent(context.Background(), ev))
+	require.NoError(t, svc.DeleteEvent(context.Background(), ev))
+
+	require.Len(t, n.got, 2)
+	require.Equal(t, models.NotificationEventCreated, n.got[0].Type)
+	require.Equal(t, models.NotificationEventDeleted, n.got[1].Type)
+	require.Equal(t, int64(2), n.got[1].UserID)
+}
+
+type recordingNotifier struct {
+	got []models.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	n.got = append(n.got, notification)
+	return nil
+}
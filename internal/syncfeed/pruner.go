@@ -0,0 +1,69 @@
+package syncfeed
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+const defaultBatchSize = 500
+
+type Repository interface {
+	PruneSyncChanges(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// Pruner periodically deletes tombstones older than the retention. Sync tokens issued
+// before a pruned tombstone expire, and their clients sync in full.
+type Pruner struct {
+	repo      Repository
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+	log       *zap.Logger
+}
+
+func NewPruner(repo Repository, retention, interval time.Duration, log *zap.Logger) *Pruner {
+	return &Pruner{
+		repo:      repo,
+		retention: retention,
+		interval:  interval,
+		batchSize: defaultBatchSize,
+		now:       time.Now,
+		log:       log.Named("SyncPruner"),
+	}
+}
+
+// Run prunes old tombstones until ctx is cancelled.
+func (p *Pruner) Run(ctx context.Context) {
+	p.log.Info("Starting sync pruner", zap.Duration("retention", p.retention), zap.Duration("interval", p.interval))
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			p.log.Info("Sync pruner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick prunes all old tombstones, batch by batch.
+func (p *Pruner) Tick(ctx context.Context) {
+	before := p.now().Add(-p.retention)
+	for ctx.Err() == nil {
+		pruned, err := p.repo.PruneSyncChanges(ctx, before, p.batchSize)
+		if err != nil {
+			p.log.Error("Failed to prune sync changes", zap.Error(err))
+			return
+		}
+		if pruned > 0 {
+			p.log.Info("Pruned sync changes", zap.Int("count", pruned))
+		}
+		if pruned < p.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package syncfeed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeRepo struct {
+	changedAt []time.Time
+	calls     int
+}
+
+func (f *fakeRepo) PruneSyncChanges(ctx context.Context, before time.Time, limit int) (int, error) {
+	f.calls++
+	var kept []time.Time
+	pruned := 0
+	for _, at := range f.changedAt {
+		if at.Before(before) && pruned < limit {
+			pruned++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	f.changedAt = kept
+	return pruned, nil
+}
+
+func TestPruner_Tick_PrunesOldTombstonesInBatches(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Hour)
+	repo := &fakeRepo{changedAt: []time.Time{now.AddDate(0, -2, 0), now.AddDate(0, -3, 0), now.AddDate(0, -4, 0), recent}}
+	p := NewPruner(repo, 30*24*time.Hour, time.Hour, zap.NewNop())
+	p.now = func() time.Time { return now }
+	p.batchSize = 2
+
+	p.Tick(context.Background())
+	require.Equal(t, []time.Time{recent}, repo.changedAt)
+	require.Equal(t, 2, repo.calls)
+}
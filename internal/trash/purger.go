@@ -0,0 +1,68 @@
+package trash
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+const defaultBatchSize = 500
+
+type Repository interface {
+	PurgeTrash(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// Purger periodically deletes events that have been in the trash longer than the retention.
+type Purger struct {
+	repo      Repository
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+	log       *zap.Logger
+}
+
+func NewPurger(repo Repository, retention, interval time.Duration, log *zap.Logger) *Purger {
+	return &Purger{
+		repo:      repo,
+		retention: retention,
+		interval:  interval,
+		batchSize: defaultBatchSize,
+		now:       time.Now,
+		log:       log.Named("TrashPurger"),
+	}
+}
+
+// Run purges expired trash until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	p.log.Info("Starting trash purger", zap.Duration("retention", p.retention), zap.Duration("interval", p.interval))
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			p.log.Info("Trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick purges all expired trash, batch by batch, so no single delete holds locks for long.
+func (p *Purger) Tick(ctx context.Context) {
+	before := p.now().Add(-p.retention)
+	for ctx.Err() == nil {
+		purged, err := p.repo.PurgeTrash(ctx, before, p.batchSize)
+		if err != nil {
+			p.log.Error("Failed to purge trash", zap.Error(err))
+			return
+		}
+		if purged > 0 {
+			p.log.Info("Purged trash", zap.Int("count", purged))
+		}
+		if purged < p.batchSize {
+			return
+		}
+	}
+}
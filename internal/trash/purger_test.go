@@ -0,0 +1,61 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRepo holds the deletion times of trashed events.
+type fakeRepo struct {
+	deletedAt []time.Time
+	calls     int
+	err       error
+}
+
+func (f *fakeRepo) PurgeTrash(ctx context.Context, before time.Time, limit int) (int, error) {
+	f.calls++
+	if f.err != nil {
+		return 0, f.err
+	}
+	var kept []time.Time
+	purged := 0
+	for _, at := range f.deletedAt {
+		if at.Before(before) && purged < limit {
+			purged++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	f.deletedAt = kept
+	return purged, nil
+}
+
+func TestPurger_Tick_PurgesExpiredInBatches(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRepo{}
+	for d := 1; d <= 5; d++ {
+		repo.deletedAt = append(repo.deletedAt, now.AddDate(0, 0, -30-d))
+	}
+	recent := now.AddDate(0, 0, -29)
+	repo.deletedAt = append(repo.deletedAt, recent)
+	p := NewPurger(repo, 30*24*time.Hour, time.Hour, zap.NewNop())
+	p.now = func() time.Time { return now }
+	p.batchSize = 2
+
+	p.Tick(context.Background())
+	require.Equal(t, []time.Time{recent}, repo.deletedAt)
+	require.Equal(t, 3, repo.calls)
+}
+
+func TestPurger_Tick_StopsOnError(t *testing.T) {
+	repo := &fakeRepo{err: errors.New("database down")}
+	p := NewPurger(repo, time.Hour, time.Hour, zap.NewNop())
+
+	p.Tick(context.Background())
+	require.Equal(t, 1, repo.calls)
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"awesomeProject/internal/models"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	defaultBatchSize   = 20
+	defaultMaxAttempts = 8
+	baseBackoff        = 30 * time.Second
+	maxBackoff         = 6 * time.Hour
+)
+
+type DeliveryStore interface {
+	ProcessDueDeliveries(ctx context.Context, now time.Time, limit int, fn func(ctx context.Context, d *models.WebhookDelivery, hook models.Webhook)) (int, error)
+}
+
+// Dispatcher sends pending deliveries, retrying failures with exponential backoff until
+// maxAttempts is reached, after which the delivery is parked in the dead state.
+type Dispatcher struct {
+	store       DeliveryStore
+	sender      *Sender
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	now         func() time.Time
+	log         *zap.Logger
+}
+
+func NewDispatcher(store DeliveryStore, sender *Sender, interval time.Duration, log *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:       store,
+		sender:      sender,
+		interval:    interval,
+		batchSize:   defaultBatchSize,
+		maxAttempts: defaultMaxAttempts,
+		now:         time.Now,
+		log:         log.Named("WebhookDispatcher"),
+	}
+}
+
+// Run polls for pending deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.log.Info("Starting webhook dispatcher", zap.Duration("interval", d.interval))
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			d.log.Info("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick attempts every delivery that is due now, batch by batch.
+func (d *Dispatcher) Tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := d.store.ProcessDueDeliveries(ctx, d.now(), d.batchSize, d.attempt)
+		if err != nil {
+			d.log.Error("Failed to process webhook deliveries", zap.Error(err))
+			return
+		}
+		if n < d.batchSize {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery, hook models.Webhook) {
+	code, err := d.sender.Send(ctx, hook, delivery)
+	now := d.now()
+	delivery.Attempts++
+	delivery.ResponseCode = code
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		d.log.Debug("Webhook delivered", zap.Int64("delivery_id", delivery.ID), zap.Int("status", code))
+		return
+	}
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = models.DeliveryDead
+		d.log.Warn("Webhook delivery moved to dead letter", zap.Int64("delivery_id", delivery.ID), zap.Int("attempts", delivery.Attempts), zap.Error(err))
+		return
+	}
+	delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts))
+	d.log.Info("Webhook delivery failed, will retry", zap.Int64("delivery_id", delivery.ID), zap.Time("next_attempt_at", delivery.NextAttemptAt), zap.Error(err))
+}
+
+// Backoff returns the delay before the next attempt after the given number of failed attempts.
+func Backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
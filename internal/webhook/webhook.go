@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"awesomeProject/internal/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Payload is the JSON body posted to webhook endpoints.
+type Payload struct {
+	Type       string           `json:"type"`
+	UserID     int64            `json:"user_id"`
+	Event      models.Event     `json:"event"`
+	Reminder   *models.Reminder `json:"reminder,omitempty"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+// Sign returns the signature header value for body sent at timestamp:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature produced by Sign in constant time.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// ErrNonPublicAddress is returned when a webhook endpoint resolves to an address the
+// service must not reach on behalf of users.
+var ErrNonPublicAddress = errors.New("webhook endpoint is not a public address")
+
+// PublicIP reports whether ip may be used as a webhook endpoint: loopback, link-local,
+// private and unspecified addresses are refused.
+func PublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsPrivate() && !ip.IsUnspecified()
+}
+
+// Sender posts signed deliveries to webhook endpoints.
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender returns a sender that only connects to public addresses and does not follow
+// redirects, so a registered host cannot be re-pointed at internal services after the
+// registration check.
+func NewSender(timeout time.Duration) *Sender {
+	return newSender(timeout, PublicIP)
+}
+
+func newSender(timeout time.Duration, allowed func(net.IP) bool) *Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// Control runs after name resolution, on the address actually dialled.
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return fmt.Errorf("%w: %s", ErrNonPublicAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialled instead of the endpoint and defeat the address check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return &Sender{client: client, now: time.Now}
+}
+
+// Send posts the delivery payload to hook.URL and returns the response status code.
+// Any non-2xx response is reported as an error.
+func (s *Sender) Send(ctx context.Context, hook models.Webhook, d *models.WebhookDelivery) (int, error) {
+	timestamp := s.now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, d.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, timestamp, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+type Enqueuer interface {
+	EnqueueDeliveries(ctx context.Context, userID int64, eventType string, payload []byte) (int, error)
+}
+
+// Notifier queues notifications as deliveries to every webhook the recipient registered.
+type Notifier struct {
+	store Enqueuer
+	now   func() time.Time
+}
+
+func NewNotifier(store Enqueuer) *Notifier {
+	return &Notifier{store: store, now: time.Now}
+}
+
+func (n *Notifier) Notify(ctx context.Context, notification models.Notification) error {
+	body, err := json.Marshal(Payload{
+		Type:       notification.Type,
+		UserID:     notification.UserID,
+		Event:      notification.Event,
+		Reminder:   notification.Reminder,
+		OccurredAt: n.now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	if _, err := n.store.EnqueueDeliveries(ctx, notification.UserID, notification.Type, body); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"awesomeProject/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// receiver is a local webhook endpoint that verifies signatures and answers with scripted status codes.
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	statuses []int
+	bodies   [][]byte
+	verified []bool
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	ts, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.bodies = append(rc.bodies, body)
+	rc.verified = append(rc.verified, Verify(rc.secret, ts, body, r.Header.Get(SignatureHeader)))
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+// fakeStore keeps deliveries in memory and mimics the repository's due-delivery processing.
+type fakeStore struct {
+	hook       models.Webhook
+	deliveries []*models.WebhookDelivery
+}
+
+func (f *fakeStore) EnqueueDeliveries(ctx context.Context, userID int64, eventType string, payload []byte) (int, error) {
+	if userID != f.hook.UserID {
+		return 0, nil
+	}
+	f.deliveries = append(f.deliveries, &models.WebhookDelivery{
+		ID:        int64(len(f.deliveries) + 1),
+		WebhookID: f.hook.ID,
+		EventType: eventType,
+		Payload:   payload,
+		Status:    models.DeliveryPending,
+	})
+	return 1, nil
+}
+
+func (f *fakeStore) ProcessDueDeliveries(ctx context.Context, now time.Time, limit int, fn func(ctx context.Context, d *models.WebhookDelivery, hook models.Webhook)) (int, error) {
+	n := 0
+	for _, d := range f.deliveries {
+		if n == limit {
+			break
+		}
+		if d.Status != models.DeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		fn(ctx, d, f.hook)
+		n++
+	}
+	return n, nil
+}
+
+func newTestDispatcher(t *testing.T, rc *receiver) (*Dispatcher, *fakeStore, *time.Time) {
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	store := &fakeStore{hook: models.Webhook{ID: 1, UserID: 7, URL: srv.URL, Secret: rc.secret}}
+	// The test server listens on loopback, which NewSender refuses.
+	sender := newSender(time.Second, func(net.IP) bool { return true })
+	d := NewDispatcher(store, sender, time.Minute, zap.NewNop())
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	return d, store, &now
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"event.created"}`)
+	sig := Sign("secret", 1700000000, body)
+	require.True(t, Verify("secret", 1700000000, body, sig))
+	require.False(t, Verify("other", 1700000000, body, sig))
+	require.False(t, Verify("secret", 1700000001, body, sig))
+	require.False(t, Verify("secret", 1700000000, []byte(`{}`), sig))
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	rc := &receiver{secret: "s3cret"}
+	d, store, _ := newTestDispatcher(t, rc)
+
+	n := NewNotifier(store)
+	err := n.Notify(context.Background(), models.Notification{
+		Type:   models.NotificationEventCreated,
+		UserID: 7,
+		Event:  models.Event{ID: 3, UserID: 7, Event: "Standup"},
+	})
+	require.NoError(t, err)
+
+	d.Tick(context.Background())
+
+	require.Len(t, rc.bodies, 1)
+	require.True(t, rc.verified[0])
+	var payload Payload
+	require.NoError(t, json.Unmarshal(rc.bodies[0], &payload))
+	require.Equal(t, models.NotificationEventCreated, payload.Type)
+	require.Equal(t, "Standup", payload.Event.Event)
+
+	delivery := store.deliveries[0]
+	require.Equal(t, models.DeliveryDelivered, delivery.Status)
+	require.Equal(t, 1, delivery.Attempts)
+	require.Equal(t, http.StatusOK, delivery.ResponseCode)
+	require.NotNil(t, delivery.DeliveredAt)
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	rc := &receiver{secret: "s3cret", statuses: []int{500, 502}}
+	d, store, now := newTestDispatcher(t, rc)
+	_, _ = store.EnqueueDeliveries(context.Background(), 7, models.NotificationEventUpdated, []byte(`{}`))
+	delivery := store.deliveries[0]
+
+	d.Tick(context.Background())
+	require.Equal(t, models.DeliveryPending, delivery.Status)
+	require.Equal(t, 500, delivery.ResponseCode)
+	require.Equal(t, now.Add(Backoff(1)), delivery.NextAttemptAt)
+
+	// Not due yet: nothing is sent.
+	d.Tick(context.Background())
+	require.Len(t, rc.bodies, 1)
+
+	*now = delivery.NextAttemptAt
+	d.Tick(context.Background())
+	require.Equal(t, 2, delivery.Attempts)
+	require.Equal(t, now.Add(Backoff(2)), delivery.NextAttemptAt)
+
+	*now = delivery.NextAttemptAt
+	d.Tick(context.Background())
+	require.Equal(t, models.DeliveryDelivered, delivery.Status)
+	require.Equal(t, 3, delivery.Attempts)
+	require.Empty(t, delivery.LastError)
+}
+
+func TestDispatcher_DeadLetterAfterMaxAttempts(t *testing.T) {
+	rc := &receiver{secret: "s3cret", statuses: []int{500, 500, 500}}
+	d, store, now := newTestDispatcher(t, rc)
+	d.maxAttempts = 3
+	_, _ = store.EnqueueDeliveries(context.Background(), 7, models.NotificationReminder, []byte(`{}`))
+	delivery := store.deliveries[0]
+
+	for i := 0; i < 3; i++ {
+		d.Tick(context.Background())
+		*now = now.Add(maxBackoff)
+	}
+	require.Equal(t, models.DeliveryDead, delivery.Status)
+	require.Equal(t, 3, delivery.Attempts)
+	require.NotEmpty(t, delivery.LastError)
+
+	d.Tick(context.Background())
+	require.Len(t, rc.bodies, 3)
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, baseBackoff, Backoff(1))
+	require.Equal(t, 2*baseBackoff, Backoff(2))
+	require.Equal(t, 8*baseBackoff, Backoff(4))
+	require.Equal(t, maxBackoff, Backoff(50))
+}
+
+func TestSender_RefusesNonPublicAddresses(t *testing.T) {
+	rc := &receiver{secret: "s3cret"}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	hook := models.Webhook{ID: 1, UserID: 7, URL: srv.URL, Secret: rc.secret}
+	_, err := NewSender(time.Second).Send(context.Background(), hook, &models.WebhookDelivery{ID: 1, Payload: []byte(`{}`)})
+	require.ErrorIs(t, err, ErrNonPublicAddress)
+	require.Empty(t, rc.bodies)
+}
+
+func TestSender_DoesNotFollowRedirects(t *testing.T) {
+	rc := &receiver{secret: "s3cret"}
+	target := httptest.NewServer(rc)
+	t.Cleanup(target.Close)
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	t.Cleanup(redirect.Close)
+
+	sender := newSender(time.Second, func(net.IP) bool { return true })
+	hook := models.Webhook{ID: 1, UserID: 7, URL: redirect.URL, Secret: rc.secret}
+	code, err := sender.Send(context.Background(), hook, &models.WebhookDelivery{ID: 1, Payload: []byte(`{}`)})
+	require.Error(t, err)
+	require.Equal(t, http.StatusTemporaryRedirect, code)
+	require.Empty(t, rc.bodies)
+}
+
+func TestPublicIP(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"93.184.215.14":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.0.0.1":        false,
+		"172.16.5.4":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"::":              false,
+	} {
+		require.Equal(t, public, PublicIP(net.ParseIP(addr)), addr)
+	}
+}
@@ -1 +1 @@
-DROP TABLE IF EXISTS calendar;
\ No newline at end of file
+DROP TABLE IF EXISTS calendar;
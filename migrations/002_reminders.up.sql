@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS reminders (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES calendar (id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    before_minutes INT NOT NULL,
+    fired_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS reminders_pending_idx ON reminders (event_id) WHERE fired_at IS NULL;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS calendar_user_start_idx;
+
+ALTER TABLE calendar
+    DROP COLUMN IF EXISTS start_at,
+    DROP COLUMN IF EXISTS end_at,
+    DROP COLUMN IF EXISTS all_day,
+    DROP COLUMN IF EXISTS transparency,
+    DROP COLUMN IF EXISTS visibility,
+    DROP COLUMN IF EXISTS rrule;
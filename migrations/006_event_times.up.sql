@@ -0,0 +1,20 @@
+ALTER TABLE calendar
+    ADD COLUMN IF NOT EXISTS start_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS end_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS all_day BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS transparency TEXT NOT NULL DEFAULT 'opaque',
+    ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'default',
+    ADD COLUMN IF NOT EXISTS rrule TEXT NOT NULL DEFAULT '';
+
+-- Existing events are date-only: they become all-day events that do not block time.
+UPDATE calendar
+SET start_at = date::timestamp AT TIME ZONE 'UTC',
+    end_at = (date + 1)::timestamp AT TIME ZONE 'UTC',
+    transparency = 'transparent'
+WHERE start_at IS NULL;
+
+ALTER TABLE calendar
+    ALTER COLUMN start_at SET NOT NULL,
+    ALTER COLUMN end_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS calendar_user_start_idx ON calendar (user_id, start_at);
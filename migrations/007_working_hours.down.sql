@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS working_hours;
+
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS time_zone;
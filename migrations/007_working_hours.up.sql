@@ -0,0 +1,10 @@
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
+
+CREATE TABLE IF NOT EXISTS working_hours (
+    user_id INT NOT NULL,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_minute SMALLINT NOT NULL CHECK (start_minute BETWEEN 0 AND 1440),
+    end_minute SMALLINT NOT NULL CHECK (end_minute BETWEEN 0 AND 1440),
+    PRIMARY KEY (user_id, weekday),
+    CHECK (start_minute < end_minute)
+);
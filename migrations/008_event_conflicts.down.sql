@@ -0,0 +1,9 @@
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS no_double_booking;
+
+ALTER TABLE calendar DROP CONSTRAINT IF EXISTS calendar_no_double_booking;
+
+DROP INDEX IF EXISTS calendar_user_during_idx;
+
+ALTER TABLE calendar
+    DROP COLUMN IF EXISTS during,
+    DROP COLUMN IF EXISTS exclusive;
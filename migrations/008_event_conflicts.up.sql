@@ -0,0 +1,15 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE calendar
+    ADD COLUMN IF NOT EXISTS during TSTZRANGE GENERATED ALWAYS AS (tstzrange(start_at, end_at)) STORED,
+    ADD COLUMN IF NOT EXISTS exclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS calendar_user_during_idx ON calendar USING gist (user_id, during);
+
+-- Calendars marked "no double booking" copy the flag onto their events; opaque events of
+-- such a calendar may then never overlap.
+ALTER TABLE calendar
+    ADD CONSTRAINT calendar_no_double_booking
+    EXCLUDE USING gist (user_id WITH =, during WITH &&) WHERE (exclusive AND transparency = 'opaque');
+
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS no_double_booking BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS event_attendees (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES calendar (id) ON DELETE CASCADE,
+    -- Internal invitees have a user_id; external ones are identified by email only.
+    user_id INT,
+    email TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'needs-action'
+        CHECK (status IN ('needs-action', 'accepted', 'declined', 'tentative')),
+    responded_at TIMESTAMPTZ,
+    CHECK (user_id IS NOT NULL OR email <> '')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS event_attendees_user_idx ON event_attendees (event_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS event_attendees_email_idx ON event_attendees (event_id, email) WHERE user_id IS NULL;
+CREATE INDEX IF NOT EXISTS event_attendees_invitee_idx ON event_attendees (user_id, event_id);
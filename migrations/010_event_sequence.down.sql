@@ -0,0 +1 @@
+ALTER TABLE calendar DROP COLUMN IF EXISTS sequence;
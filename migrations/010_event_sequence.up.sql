@@ -0,0 +1,2 @@
+-- SEQUENCE of the iTIP messages sent for an event; bumped on every update.
+ALTER TABLE calendar ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS event_attendees_booking_idx;
+DROP INDEX IF EXISTS event_attendees_resource_idx;
+DELETE FROM event_attendees WHERE resource_id IS NOT NULL;
+
+DROP INDEX IF EXISTS event_attendees_email_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS event_attendees_email_idx ON event_attendees (event_id, email) WHERE user_id IS NULL;
+
+ALTER TABLE event_attendees DROP CONSTRAINT IF EXISTS event_attendees_identity_check;
+ALTER TABLE event_attendees ADD CONSTRAINT event_attendees_check CHECK (user_id IS NOT NULL OR email <> '');
+ALTER TABLE event_attendees DROP COLUMN IF EXISTS resource_id;
+
+DROP TABLE IF EXISTS resources;
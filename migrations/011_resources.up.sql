@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS resources (
+    id SERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    -- Seats for rooms and cars; 0 when capacity does not apply.
+    capacity INT NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+    attributes JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS resources_attributes_idx ON resources USING gin (attributes);
+
+-- Resources are booked by inviting them like attendees.
+ALTER TABLE event_attendees ADD COLUMN IF NOT EXISTS resource_id INT REFERENCES resources (id) ON DELETE CASCADE;
+ALTER TABLE event_attendees DROP CONSTRAINT IF EXISTS event_attendees_check;
+ALTER TABLE event_attendees ADD CONSTRAINT event_attendees_identity_check
+    CHECK (num_nonnulls(user_id, resource_id, NULLIF(email, '')) = 1);
+
+DROP INDEX IF EXISTS event_attendees_email_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS event_attendees_email_idx ON event_attendees (event_id, email)
+    WHERE user_id IS NULL AND resource_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS event_attendees_resource_idx ON event_attendees (event_id, resource_id)
+    WHERE resource_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS event_attendees_booking_idx ON event_attendees (resource_id, event_id)
+    WHERE resource_id IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE event_attendees DROP COLUMN IF EXISTS comment;
+
+DROP TABLE IF EXISTS out_of_office;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS out_of_office (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    start_at TIMESTAMPTZ NOT NULL,
+    end_at TIMESTAMPTZ NOT NULL,
+    -- Sent with automatic declines of invitations during the period.
+    message TEXT NOT NULL DEFAULT '',
+    CHECK (start_at < end_at)
+);
+
+CREATE INDEX IF NOT EXISTS out_of_office_user_idx ON out_of_office (user_id, end_at);
+
+ALTER TABLE event_attendees ADD COLUMN IF NOT EXISTS comment TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS tasks (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    -- iCalendar UID; imports update the task with the same UID.
+    uid TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    due_at TIMESTAMPTZ,
+    -- 0 is undefined, 1 the highest and 9 the lowest priority as in RFC 5545.
+    priority SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 9),
+    status TEXT NOT NULL DEFAULT 'needs-action'
+        CHECK (status IN ('needs-action', 'in-process', 'completed', 'cancelled')),
+    percent_complete SMALLINT NOT NULL DEFAULT 0 CHECK (percent_complete BETWEEN 0 AND 100),
+    completed_at TIMESTAMPTZ,
+    UNIQUE (user_id, uid)
+);
+
+CREATE INDEX IF NOT EXISTS tasks_user_due_idx ON tasks (user_id, due_at);
@@ -0,0 +1,11 @@
+-- Without the column trashed events would come back, so they are purged.
+DELETE FROM calendar WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE calendar DROP CONSTRAINT IF EXISTS calendar_no_double_booking;
+ALTER TABLE calendar
+    ADD CONSTRAINT calendar_no_double_booking
+    EXCLUDE USING gist (user_id WITH =, during WITH &&) WHERE (exclusive AND transparency = 'opaque');
+
+DROP INDEX IF EXISTS calendar_trash_idx;
+
+ALTER TABLE calendar DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,10 @@
+-- Deleted events stay in the trash until they are restored or purged.
+ALTER TABLE calendar ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS calendar_trash_idx ON calendar (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Trashed events no longer block the calendar; restoring one checks the constraint again.
+ALTER TABLE calendar DROP CONSTRAINT IF EXISTS calendar_no_double_booking;
+ALTER TABLE calendar
+    ADD CONSTRAINT calendar_no_double_booking
+    EXCLUDE USING gist (user_id WITH =, during WITH &&) WHERE (exclusive AND transparency = 'opaque' AND deleted_at IS NULL);
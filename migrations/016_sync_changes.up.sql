@@ -0,0 +1,30 @@
+-- One row per user and event the user sees or has seen; deleted rows are tombstones.
+CREATE TABLE IF NOT EXISTS sync_changes (
+    user_id INT NOT NULL,
+    event_id INT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    -- The transaction of the last change. Feeds are read in transaction order up to the
+    -- oldest running transaction, so a change that commits late is never skipped.
+    xid BIGINT NOT NULL DEFAULT (pg_current_xact_id()::text)::bigint,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS sync_changes_feed_idx ON sync_changes (user_id, xid, event_id);
+CREATE INDEX IF NOT EXISTS sync_changes_tombstone_idx ON sync_changes (changed_at) WHERE deleted;
+
+-- Tokens at or before the newest pruned tombstone may have missed deletions.
+CREATE TABLE IF NOT EXISTS sync_watermark (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    pruned_xid BIGINT NOT NULL DEFAULT 0
+);
+INSERT INTO sync_watermark DEFAULT VALUES ON CONFLICT DO NOTHING;
+
+-- Existing events enter the feeds as of this migration.
+INSERT INTO sync_changes (user_id, event_id)
+SELECT user_id, id FROM calendar WHERE deleted_at IS NULL
+UNION
+SELECT a.user_id, a.event_id FROM event_attendees a
+JOIN calendar c ON c.id = a.event_id
+WHERE a.user_id IS NOT NULL AND c.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
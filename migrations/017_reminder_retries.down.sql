@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS reminders_pending_idx;
+CREATE INDEX IF NOT EXISTS reminders_pending_idx ON reminders (event_id) WHERE fired_at IS NULL;
+
+ALTER TABLE reminders
+    DROP COLUMN IF EXISTS failed_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts;
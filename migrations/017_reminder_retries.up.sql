@@ -0,0 +1,8 @@
+ALTER TABLE reminders
+    ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS reminders_pending_idx;
+CREATE INDEX IF NOT EXISTS reminders_pending_idx ON reminders (event_id) WHERE fired_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1,2 @@
+-- Channels that already delivered a reminder are not notified again when another one is retried.
+ALTER TABLE reminders ADD COLUMN IF NOT EXISTS delivered_channels TEXT[] NOT NULL DEFAULT '{}';
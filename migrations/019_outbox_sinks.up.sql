@@ -0,0 +1,5 @@
+-- Every sink records the messages it delivered, so a failing sink neither repeats the
+-- others nor holds them back. A message is published once all sinks have it.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS published_sinks TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS outbox_published_idx ON outbox (published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,15 @@
+// Package migrations embeds the schema migrations so the binary does not depend on SQL
+// files on disk.
+package migrations
+
+import "embed"
+
+// Postgres holds the Postgres migrations at its root.
+//
+//go:embed *.sql
+var Postgres embed.FS
+
+// SQLite holds the SQLite migrations under the sqlite directory.
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
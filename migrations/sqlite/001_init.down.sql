@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS event_attendees;
+DROP TABLE IF EXISTS calendar;
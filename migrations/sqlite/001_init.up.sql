@@ -0,0 +1,34 @@
+-- Times are stored as Unix microseconds and dates as YYYY-MM-DD text so that both
+-- compare correctly as plain values.
+CREATE TABLE IF NOT EXISTS calendar (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    date TEXT NOT NULL,
+    event TEXT NOT NULL,
+    start_at INTEGER NOT NULL,
+    end_at INTEGER NOT NULL,
+    all_day INTEGER NOT NULL DEFAULT 0,
+    transparency TEXT NOT NULL DEFAULT 'opaque' CHECK (transparency IN ('opaque', 'transparent')),
+    visibility TEXT NOT NULL DEFAULT 'default' CHECK (visibility IN ('default', 'public', 'private')),
+    rrule TEXT NOT NULL DEFAULT '',
+    sequence INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS calendar_user_date_idx ON calendar (user_id, date);
+CREATE INDEX IF NOT EXISTS calendar_user_start_idx ON calendar (user_id, start_at);
+
+CREATE TABLE IF NOT EXISTS event_attendees (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL REFERENCES calendar (id) ON DELETE CASCADE,
+    user_id INTEGER,
+    email TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'needs-action'
+        CHECK (status IN ('needs-action', 'accepted', 'declined', 'tentative')),
+    comment TEXT NOT NULL DEFAULT '',
+    responded_at INTEGER,
+    CHECK ((user_id IS NULL) <> (email = ''))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS event_attendees_user_idx ON event_attendees (event_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS event_attendees_email_idx ON event_attendees (event_id, email) WHERE user_id IS NULL;
+CREATE INDEX IF NOT EXISTS event_attendees_invitee_idx ON event_attendees (user_id);
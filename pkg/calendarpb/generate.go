@@ -0,0 +1,5 @@
+// Package calendarpb holds the generated protobuf messages and gRPC stubs of the calendar
+// API defined in proto/calendar/v1/calendar.proto.
+package calendarpb
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=module=awesomeProject/pkg/calendarpb --go-grpc_out=. --go-grpc_opt=module=awesomeProject/pkg/calendarpb calendar/v1/calendar.proto